@@ -1,6 +1,7 @@
 package zabbix
 
 import (
+	"encoding/json"
 	"fmt"
 )
 
@@ -16,6 +17,93 @@ const (
 	// ActionEvalTypeOr indicated that an Action will evaluate its conditions
 	// using OR bitwise logic.
 	ActionEvalTypeOr
+
+	// ActionEvalTypeCustom indicates that an Action will evaluate its
+	// conditions using a custom expression, given by the Formula of the Action.
+	ActionEvalTypeCustom
+)
+
+const (
+	// ActionStatusEnabled indicates that an Action is enabled.
+	ActionStatusEnabled = 0
+
+	// ActionStatusDisabled indicates that an Action is disabled.
+	ActionStatusDisabled = 1
+)
+
+// Action condition types are the subjects of the conditions of an Action.
+//
+// See: https://www.zabbix.com/documentation/current/manual/api/reference/action/object#action-filter-condition
+const (
+	ActionConditionTypeHostGroup             = 0
+	ActionConditionTypeHost                  = 1
+	ActionConditionTypeTrigger               = 2
+	ActionConditionTypeEventName             = 3
+	ActionConditionTypeTriggerSeverity       = 4
+	ActionConditionTypeTimePeriod            = 6
+	ActionConditionTypeHostIP                = 7
+	ActionConditionTypeDiscoveredServiceType = 8
+	ActionConditionTypeDiscoveredServicePort = 9
+	ActionConditionTypeDiscoveryStatus       = 10
+	ActionConditionTypeUptimeDowntime        = 11
+	ActionConditionTypeReceivedValue         = 12
+	ActionConditionTypeHostTemplate          = 13
+	ActionConditionTypeEventAcknowledged     = 14
+	ActionConditionTypeApplication           = 15
+	ActionConditionTypeProblemSuppressed     = 16
+	ActionConditionTypeDiscoveryRule         = 18
+	ActionConditionTypeDiscoveryCheck        = 19
+	ActionConditionTypeProxy                 = 20
+	ActionConditionTypeDiscoveryObject       = 21
+	ActionConditionTypeHostName              = 22
+	ActionConditionTypeEventType             = 23
+	ActionConditionTypeHostMetadata          = 24
+	ActionConditionTypeEventTag              = 25
+	ActionConditionTypeEventTagValue         = 26
+	ActionConditionTypeService               = 27
+	ActionConditionTypeServiceName           = 28
+)
+
+// Action condition operators compare the subject of a condition of an Action
+// with its value.
+const (
+	ActionConditionOperatorEqual = iota
+	ActionConditionOperatorNotEqual
+	ActionConditionOperatorContains
+	ActionConditionOperatorNotContains
+	ActionConditionOperatorIn
+	ActionConditionOperatorGreaterOrEqual
+	ActionConditionOperatorLessOrEqual
+	ActionConditionOperatorNotIn
+	ActionConditionOperatorMatches
+	ActionConditionOperatorNotMatches
+	ActionConditionOperatorYes
+	ActionConditionOperatorNo
+)
+
+// Action operation types are the kinds of operations of an Action.
+//
+// See: https://www.zabbix.com/documentation/current/manual/api/reference/action/object#action-operation
+const (
+	ActionOperationTypeSendMessage = iota
+	ActionOperationTypeRemoteCommand
+	ActionOperationTypeAddHost
+	ActionOperationTypeRemoveHost
+	ActionOperationTypeAddToHostGroup
+	ActionOperationTypeRemoveFromHostGroup
+	ActionOperationTypeLinkTemplate
+	ActionOperationTypeUnlinkTemplate
+	ActionOperationTypeEnableHost
+	ActionOperationTypeDisableHost
+	ActionOperationTypeSetInventoryMode
+
+	// ActionOperationTypeNotifyRecoveryAll notifies all users who were
+	// notified of the problem. It is only valid as a recovery operation.
+	ActionOperationTypeNotifyRecoveryAll
+
+	// ActionOperationTypeNotifyUpdateAll notifies all users who were notified
+	// of the problem. It is only valid as an update operation.
+	ActionOperationTypeNotifyUpdateAll
 )
 
 // Action represents a Zabbix Action returned from the Zabbix API.
@@ -25,7 +113,8 @@ type Action struct {
 	// ActionID is the unique ID of the Action.
 	ActionID string
 
-	// StepDuration is the interval in seconds between each operation step.
+	// StepDuration is the default interval in seconds between each operation
+	// step. StepDuration is zero if the interval is given as a user macro.
 	StepDuration int
 
 	// EvaluationType determines the bitwise logic used to evaluate the Actions
@@ -33,9 +122,20 @@ type Action struct {
 	//
 	// EvaluationType must be one of the ActionEvalType constants.
 	//
-	// EvaluationType is only supported up to Zabbix v2.2.
+	// EvaluationType is only populated from Zabbix v2.4 if
+	// ActionGetParams.SelectFilter is given in the query parameters that
+	// returned this Action.
 	EvaluationType int
 
+	// Formula is the custom expression of the conditions of the Action if
+	// EvaluationType is ActionEvalTypeCustom. Conditions are referenced by
+	// their FormulaID.
+	Formula string
+
+	// EvalFormula is the expression used to evaluate the conditions of the
+	// Action, generated by Zabbix for all evaluation types.
+	EvalFormula string
+
 	// EventType is the type of Events that this Action will handle.
 	//
 	// Source must be one of the EventSource constants.
@@ -46,6 +146,9 @@ type Action struct {
 
 	// ProblemMessageBody is the message body text to be submitted for this
 	// Action.
+	//
+	// Messages of the Action are only supported up to Zabbix v4.4. Later
+	// versions configure messages in each operation.
 	ProblemMessageBody string
 
 	// ProblemMessageSubject is the short summary text to be submitted for this
@@ -60,6 +163,14 @@ type Action struct {
 	// Action.
 	RecoveryMessageSubject string
 
+	// AcknowledgeMessageBody is the message body text to be submitted when
+	// the problem of this Action is updated.
+	AcknowledgeMessageBody string
+
+	// AcknowledgeMessageSubject is the short summary text to be submitted
+	// when the problem of this Action is updated.
+	AcknowledgeMessageSubject string
+
 	// RecoveryMessageEnabled determines whether recovery messages will be
 	// submitted for the Action when the source problem is resolved.
 	RecoveryMessageEnabled bool
@@ -67,23 +178,226 @@ type Action struct {
 	// Enabled determines whether the Action is enabled or disabled.
 	Enabled bool
 
+	// PauseSuppressed determines whether escalation is paused while the
+	// problem is suppressed, such as during maintenance.
+	PauseSuppressed bool
+
+	// NotifyIfCanceled determines whether users are notified when the
+	// escalation of the Action is canceled.
+	NotifyIfCanceled bool
+
 	// Conditions are the conditions which must be met for this Action to
 	// execute.
+	//
+	// Conditions are only populated if ActionGetParams.SelectFilter is given
+	// in the query parameters that returned this Action.
 	Conditions []ActionCondition
 
 	// Operations are the operations which will be exectuted for this Action.
+	//
+	// Operations are only populated if ActionGetParams.SelectOperations is
+	// given in the query parameters that returned this Action.
 	Operations []ActionOperation
+
+	// RecoveryOperations are the operations which will be executed when the
+	// problem of this Action is resolved.
+	//
+	// RecoveryOperations are only populated if
+	// ActionGetParams.SelectRecoveryOperations is given in the query
+	// parameters that returned this Action.
+	RecoveryOperations []ActionOperation
+
+	// UpdateOperations are the operations which will be executed when the
+	// problem of this Action is updated.
+	//
+	// UpdateOperations are only populated if
+	// ActionGetParams.SelectUpdateOperations is given in the query parameters
+	// that returned this Action.
+	UpdateOperations []ActionOperation
 }
 
-// ActionCondition is action condition
-type ActionCondition struct{}
+// ActionFilter is the filter of an Action, written by CreateActions and
+// UpdateActions.
+type ActionFilter struct {
+	// EvaluationType must be one of the ActionEvalType constants.
+	EvaluationType int `json:"evaltype"`
+
+	// Formula is required with ActionEvalTypeCustom.
+	Formula string `json:"formula,omitempty"`
+
+	Conditions []ActionCondition `json:"conditions"`
+}
+
+// ActionCondition is a condition which must be met for an Action to execute.
+type ActionCondition struct {
+	// ConditionType must be one of the ActionConditionType constants.
+	ConditionType int `json:"conditiontype"`
+
+	// Operator must be one of the ActionConditionOperator constants.
+	Operator int `json:"operator"`
+
+	// Value is the value to compare with, such as a host group ID, a trigger
+	// severity, a time period or a tag name.
+	Value string `json:"value"`
+
+	// Value2 is the tag name of ActionConditionTypeEventTagValue conditions.
+	Value2 string `json:"value2,omitempty"`
+
+	// FormulaID is the label of the condition in the Formula of the Action.
+	FormulaID string `json:"formulaid,omitempty"`
+}
 
-// ActionOperation is action operation
-type ActionOperation struct{}
+// ActionOperationCondition is a condition which must be met for an operation
+// of an Action to execute.
+type ActionOperationCondition struct {
+	// ConditionType must be ActionConditionTypeEventAcknowledged.
+	ConditionType int
+
+	// Operator must be ActionConditionOperatorEqual.
+	Operator int
+
+	// Value is "1" for acknowledged and "0" for unacknowledged events.
+	Value string
+}
+
+// ActionMessage is the message sent by an operation of an Action.
+type ActionMessage struct {
+	// DefaultMessage determines whether the message template of the Media
+	// Type, or of the Action before Zabbix v5.0, is used instead of Subject and
+	// Message.
+	DefaultMessage bool
+
+	Subject string
+	Message string
+
+	// MediaTypeID is the ID of the Media Type used to send the message, or
+	// empty to use all Media Types of each recipient.
+	MediaTypeID string
+}
+
+// ActionCommand is the remote command run by an operation of an Action.
+type ActionCommand struct {
+	// ScriptID is the ID of the global script to run.
+	ScriptID string
+
+	// Type, Command and ExecuteOn are the command to run before Zabbix v5.4,
+	// which runs global scripts only.
+	Type      int
+	Command   string
+	ExecuteOn int
+}
+
+// ActionOperation is an operation which will be executed for an Action.
+type ActionOperation struct {
+	// OperationID is the unique ID of the operation. It is empty for new
+	// operations.
+	OperationID string
+
+	// OperationType must be one of the ActionOperationType constants.
+	OperationType int
+
+	// StepDuration is the interval in seconds before the next step, or zero to
+	// use the StepDuration of the Action.
+	StepDuration int
+
+	// StepFrom and StepTo are the escalation steps of the operation. A StepTo
+	// of zero runs the operation in every step from StepFrom.
+	StepFrom int
+	StepTo   int
+
+	// EvaluationType determines the logic used to evaluate Conditions and must
+	// be one of the ActionEvalType constants.
+	EvaluationType int
+
+	// Conditions are the conditions which must be met for the operation to
+	// execute.
+	Conditions []ActionOperationCondition
+
+	// Message is the message of ActionOperationTypeSendMessage operations and
+	// notifications of all involved users.
+	Message *ActionMessage
+
+	// UserGroupIDs and UserIDs are the recipients of the Message.
+	UserGroupIDs []string
+	UserIDs      []string
+
+	// Command is the command of ActionOperationTypeRemoteCommand operations.
+	Command *ActionCommand
+
+	// CommandHostGroupIDs and CommandHostIDs are the hosts the Command runs
+	// on. A host ID of "0" is the host of the event.
+	CommandHostGroupIDs []string
+	CommandHostIDs      []string
+
+	// HostGroupIDs are the host groups to add or remove discovered hosts to
+	// or from.
+	HostGroupIDs []string
+
+	// TemplateIDs are the templates to link or unlink discovered hosts to or
+	// from.
+	TemplateIDs []string
+
+	// InventoryMode is the inventory mode of
+	// ActionOperationTypeSetInventoryMode operations.
+	InventoryMode int
+}
+
+// MarshalJSON encodes the ActionOperation as an action operation object of
+// the Zabbix API.
+func (c ActionOperation) MarshalJSON() ([]byte, error) {
+	return json.Marshal(newJActionOperation(&c))
+}
 
 // ActionGetParams is query params for action.get call
 type ActionGetParams struct {
 	GetParameters
+
+	// ActionIDs filters search results to Actions that matched the given
+	// Action IDs.
+	ActionIDs []string `json:"actionids,omitempty"`
+
+	// GroupIDs filters search results to Actions that use the given host
+	// groups in their conditions.
+	GroupIDs []string `json:"groupids,omitempty"`
+
+	// HostIDs filters search results to Actions that use the given hosts in
+	// their conditions.
+	HostIDs []string `json:"hostids,omitempty"`
+
+	// TriggerIDs filters search results to Actions that use the given
+	// Triggers in their conditions.
+	TriggerIDs []string `json:"triggerids,omitempty"`
+
+	// MediaTypeIDs filters search results to Actions that send messages with
+	// the given Media Types.
+	MediaTypeIDs []string `json:"mediatypeids,omitempty"`
+
+	// UserGroupIDs filters search results to Actions that send messages to
+	// the given user groups.
+	UserGroupIDs []string `json:"usrgrpids,omitempty"`
+
+	// UserIDs filters search results to Actions that send messages to the
+	// given Users.
+	UserIDs []string `json:"userids,omitempty"`
+
+	// ScriptIDs filters search results to Actions that run the given scripts.
+	ScriptIDs []string `json:"scriptids,omitempty"`
+
+	// SelectFilter causes the conditions of each Action to be attached in the
+	// search results.
+	SelectFilter SelectQuery `json:"selectFilter,omitempty"`
+
+	// SelectOperations causes the operations of each Action to be attached in
+	// the search results.
+	SelectOperations SelectQuery `json:"selectOperations,omitempty"`
+
+	// SelectRecoveryOperations causes the recovery operations of each Action
+	// to be attached in the search results.
+	SelectRecoveryOperations SelectQuery `json:"selectRecoveryOperations,omitempty"`
+
+	// SelectUpdateOperations causes the update operations of each Action to
+	// be attached in the search results.
+	SelectUpdateOperations SelectQuery `json:"selectUpdateOperations,omitempty"`
 }
 
 // GetActions queries the Zabbix API for Actions matching the given search
@@ -92,8 +406,13 @@ type ActionGetParams struct {
 // ErrNotFound is returned if the search result set is empty.
 // An error is returned if a transport, parsing or API error occurs.
 func (c *Session) GetActions(params ActionGetParams) ([]Action, error) {
+	query, err := c.actionQuery(params)
+	if err != nil {
+		return nil, err
+	}
+
 	actions := make([]jAction, 0)
-	err := c.Get("action.get", params, &actions)
+	err = c.Get("action.get", query, &actions)
 	if err != nil {
 		return nil, err
 	}
@@ -115,3 +434,180 @@ func (c *Session) GetActions(params ActionGetParams) ([]Action, error) {
 
 	return out, nil
 }
+
+// actionQuery returns the given ActionGetParams with the parameter names of
+// the connected Zabbix API version.
+func (c *Session) actionQuery(params ActionGetParams) (interface{}, error) {
+	if params.SelectUpdateOperations == nil {
+		return params, nil
+	}
+
+	renamed, err := c.VersionAtLeast(5, 0)
+	if err != nil || renamed {
+		return params, err
+	}
+
+	b, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+
+	query := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(b, &query); err != nil {
+		return nil, err
+	}
+
+	// update operations are named acknowledge operations before v5.0
+	query["selectAcknowledgeOperations"] = query["selectUpdateOperations"]
+	delete(query, "selectUpdateOperations")
+
+	return query, nil
+}
+
+// CountActions queries the Zabbix API for the number of Actions matching the
+// given search parameters.
+//
+// An error is returned if a transport, parsing or API error occurs.
+func (c *Session) CountActions(params ActionGetParams) (int, error) {
+	params.CountOutput = true
+	return c.count("action.get", params)
+}
+
+// ActionResponse represent action write response body
+type ActionResponse struct {
+	ActionIDs []string `json:"actionids"`
+}
+
+// ActionCreateParams represent the parameters for an `action.create` API
+// call.
+//
+// See: https://www.zabbix.com/documentation/current/manual/api/reference/action/create
+type ActionCreateParams struct {
+	Name string `json:"name"`
+
+	// EventSource must be one of the EventSource constants.
+	EventSource int `json:"eventsource"`
+
+	// StepDuration is the default interval between operation steps in
+	// seconds or with a time suffix, such as "1h".
+	StepDuration string `json:"esc_period,omitempty"`
+
+	// Status must be one of the ActionStatus constants.
+	Status int `json:"status"`
+
+	// PauseSuppressed is 1 to pause escalation while the problem is
+	// suppressed, and 0 otherwise. Zabbix defaults to 1.
+	PauseSuppressed *int `json:"pause_suppressed,omitempty"`
+
+	// NotifyIfCanceled is 1 to notify users when escalation is canceled, and
+	// 0 otherwise. Zabbix defaults to 1.
+	NotifyIfCanceled *int `json:"notify_if_canceled,omitempty"`
+
+	Filter             *ActionFilter     `json:"filter,omitempty"`
+	Operations         []ActionOperation `json:"operations,omitempty"`
+	RecoveryOperations []ActionOperation `json:"recovery_operations,omitempty"`
+	UpdateOperations   []ActionOperation `json:"update_operations,omitempty"`
+}
+
+// ActionUpdateParams represent the parameters for an `action.update` API
+// call.
+//
+// Only the fields that are set are updated. Filter and operations replace the
+// existing values of the Action when given, so a pointer to an empty slice of
+// operations removes all operations of that kind.
+//
+// See: https://www.zabbix.com/documentation/current/manual/api/reference/action/update
+type ActionUpdateParams struct {
+	// ActionID is the ID of the Action to update.
+	ActionID string `json:"actionid"`
+
+	Name             string  `json:"name,omitempty"`
+	StepDuration     *string `json:"esc_period,omitempty"`
+	Status           *int    `json:"status,omitempty"`
+	PauseSuppressed  *int    `json:"pause_suppressed,omitempty"`
+	NotifyIfCanceled *int    `json:"notify_if_canceled,omitempty"`
+
+	Filter             *ActionFilter      `json:"filter,omitempty"`
+	Operations         *[]ActionOperation `json:"operations,omitempty"`
+	RecoveryOperations *[]ActionOperation `json:"recovery_operations,omitempty"`
+	UpdateOperations   *[]ActionOperation `json:"update_operations,omitempty"`
+}
+
+// actionParams returns the given create or update parameters with the
+// parameter names of the connected Zabbix API version.
+func (c *Session) actionParams(params interface{}) (interface{}, error) {
+	renamed, err := c.VersionAtLeast(5, 0)
+	if err != nil || renamed {
+		return params, err
+	}
+
+	b, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+
+	query := make([]map[string]json.RawMessage, 0)
+	if err := json.Unmarshal(b, &query); err != nil {
+		return nil, err
+	}
+
+	// update operations are named acknowledge operations before v5.0
+	for _, action := range query {
+		if v, ok := action["update_operations"]; ok {
+			action["acknowledge_operations"] = v
+			delete(action, "update_operations")
+		}
+	}
+
+	return query, nil
+}
+
+// actionAction calls the given action write method and returns the affected
+// Action IDs.
+func (c *Session) actionAction(method string, params interface{}) ([]string, error) {
+	var body ActionResponse
+
+	if err := c.Get(method, params, &body); err != nil {
+		return nil, err
+	}
+
+	if len(body.ActionIDs) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return body.ActionIDs, nil
+}
+
+// CreateActions creates a single or multiple new actions.
+// Returns a list of action id(s) of created action(s).
+//
+// Zabbix API docs: https://www.zabbix.com/documentation/current/manual/api/reference/action/create
+func (c *Session) CreateActions(actions ...ActionCreateParams) (actionIDs []string, err error) {
+	params, err := c.actionParams(actions)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.actionAction("action.create", params)
+}
+
+// UpdateActions updates a single or multiple existing actions.
+// Returns a list of updated action id(s).
+//
+// Zabbix API docs: https://www.zabbix.com/documentation/current/manual/api/reference/action/update
+func (c *Session) UpdateActions(actions ...ActionUpdateParams) (actionIDs []string, err error) {
+	params, err := c.actionParams(actions)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.actionAction("action.update", params)
+}
+
+// DeleteActions deletes a single or multiple actions.
+// Returns a list of deleted action id(s).
+//
+// Zabbix API docs: https://www.zabbix.com/documentation/current/manual/api/reference/action/delete
+func (c *Session) DeleteActions(actionIDs ...string) ([]string, error) {
+	return c.actionAction("action.delete", actionIDs)
+}
@@ -1,24 +1,57 @@
 package zabbix
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 )
 
 // jAction is a private map for the Zabbix API Action object.
 // See: https://www.zabbix.com/documentation/2.2/manual/api/reference/action/object
 type jAction struct {
-	ActionID     string `json:"actionid"`
-	EscPeriod    string `json:"esc_period"`
-	EvalType     string `json:"evaltype"`
-	EventSource  string `json:"eventsource"`
-	Name         string `json:"name"`
-	DefLongData  string `json:"def_longdata"`
-	DefShortData string `json:"def_shortdata"`
-	RLongData    string `json:"r_longdata"`
-	RShortData   string `json:"r_shortdata"`
-	RecoveryMsg  string `json:"recovery_msg"`
-	Status       string `json:"status"`
+	ActionID         string `json:"actionid"`
+	EscPeriod        string `json:"esc_period"`
+	EvalType         string `json:"evaltype"`
+	EventSource      string `json:"eventsource"`
+	Name             string `json:"name"`
+	DefLongData      string `json:"def_longdata"`
+	DefShortData     string `json:"def_shortdata"`
+	RLongData        string `json:"r_longdata"`
+	RShortData       string `json:"r_shortdata"`
+	AckLongData      string `json:"ack_longdata"`
+	AckShortData     string `json:"ack_shortdata"`
+	RecoveryMsg      string `json:"recovery_msg"`
+	Status           string `json:"status"`
+	MaintenanceMode  string `json:"maintenance_mode"`
+	PauseSuppressed  string `json:"pause_suppressed"`
+	NotifyIfCanceled string `json:"notify_if_canceled"`
+
+	Filter     *jActionFilter    `json:"filter"`
+	Conditions jActionConditions `json:"conditions"`
+	Operations jActionOperations `json:"operations"`
+
+	// recovery and update operations are renamed in v4.0 and v5.0
+	RecoveryOperations       jActionOperations `json:"recovery_operations"`
+	RecoveryOperationsLegacy jActionOperations `json:"recoveryOperations"`
+	UpdateOperations         jActionOperations `json:"update_operations"`
+	AcknowledgeOperations    jActionOperations `json:"acknowledge_operations"`
+	AcknowledgeOperationsOld jActionOperations `json:"acknowledgeOperations"`
+}
+
+// parseStepDuration parses an escalation period in seconds or with a time
+// suffix. Periods given as user macros are zero.
+func parseStepDuration(s string) (int, error) {
+	if s == "" || strings.HasPrefix(s, "{") {
+		return 0, nil
+	}
+
+	d, err := parseTimeSuffix(s)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(d.Seconds()), nil
 }
 
 // Action returns a native Go Action struct mapped from the given JSON Action
@@ -32,11 +65,15 @@ func (c *jAction) Action() (*Action, error) {
 	action.ProblemMessageSubject = c.DefShortData
 	action.ProblemMessageBody = c.DefLongData
 	action.RecoveryMessageSubject = c.RShortData
-	action.RecoveryMessageBody = c.RShortData
+	action.RecoveryMessageBody = c.RLongData
+	action.AcknowledgeMessageSubject = c.AckShortData
+	action.AcknowledgeMessageBody = c.AckLongData
 	action.RecoveryMessageEnabled = (c.RecoveryMsg == "1")
 	action.Enabled = (c.Status == "0")
+	action.PauseSuppressed = (c.PauseSuppressed == "1" || c.MaintenanceMode == "1")
+	action.NotifyIfCanceled = (c.NotifyIfCanceled == "1")
 
-	action.StepDuration, err = strconv.Atoi(c.EscPeriod)
+	action.StepDuration, err = parseStepDuration(c.EscPeriod)
 	if err != nil {
 		return nil, fmt.Errorf("Error parsing Action Step Duration: %v", err)
 	}
@@ -53,5 +90,378 @@ func (c *jAction) Action() (*Action, error) {
 		return nil, fmt.Errorf("Error parsing Action Event Type: %v", err)
 	}
 
+	// map conditions of the filter since v2.4
+	conditions := c.Conditions
+	if c.Filter != nil {
+		action.EvaluationType, err = atoi(c.Filter.EvalType)
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing Action Evaluation Type: %v", err)
+		}
+
+		action.Formula = c.Filter.Formula
+		action.EvalFormula = c.Filter.EvalFormula
+		conditions = c.Filter.Conditions
+	}
+
+	if action.Conditions, err = conditions.Conditions(); err != nil {
+		return nil, err
+	}
+
+	// map operations
+	if action.Operations, err = c.Operations.Operations(); err != nil {
+		return nil, err
+	}
+
+	recovery := c.RecoveryOperations
+	if recovery == nil {
+		recovery = c.RecoveryOperationsLegacy
+	}
+	if action.RecoveryOperations, err = recovery.Operations(); err != nil {
+		return nil, err
+	}
+
+	update := c.UpdateOperations
+	if update == nil {
+		update = c.AcknowledgeOperations
+	}
+	if update == nil {
+		update = c.AcknowledgeOperationsOld
+	}
+	if action.UpdateOperations, err = update.Operations(); err != nil {
+		return nil, err
+	}
+
 	return action, nil
 }
+
+// jActionFilter is a private map for the Zabbix API Action filter object.
+type jActionFilter struct {
+	EvalType    string            `json:"evaltype"`
+	Formula     string            `json:"formula"`
+	EvalFormula string            `json:"eval_formula"`
+	Conditions  jActionConditions `json:"conditions"`
+}
+
+// jActionCondition is a private map for the Zabbix API Action condition
+// object.
+type jActionCondition struct {
+	ConditionType string `json:"conditiontype"`
+	Operator      string `json:"operator"`
+	Value         string `json:"value"`
+	Value2        string `json:"value2"`
+	FormulaID     string `json:"formulaid"`
+}
+
+// Condition returns a native Go ActionCondition struct mapped from the given
+// JSON Action condition data.
+func (c *jActionCondition) Condition() (*ActionCondition, error) {
+	var err error
+	condition := &ActionCondition{
+		Value:     c.Value,
+		Value2:    c.Value2,
+		FormulaID: c.FormulaID,
+	}
+
+	if condition.ConditionType, err = atoi(c.ConditionType); err != nil {
+		return nil, fmt.Errorf("Error parsing Action Condition Type: %v", err)
+	}
+
+	if condition.Operator, err = atoi(c.Operator); err != nil {
+		return nil, fmt.Errorf("Error parsing Action Condition Operator: %v", err)
+	}
+
+	return condition, nil
+}
+
+// jActionConditions is a slice of jActionCondition structs.
+type jActionConditions []jActionCondition
+
+// Conditions returns a native Go slice of ActionConditions mapped from the
+// given JSON Action conditions data.
+func (c jActionConditions) Conditions() ([]ActionCondition, error) {
+	if c == nil {
+		return nil, nil
+	}
+
+	out := make([]ActionCondition, len(c))
+	for i, jcondition := range c {
+		condition, err := jcondition.Condition()
+		if err != nil {
+			return nil, fmt.Errorf("Error unmarshalling Action Condition %d in JSON data: %v", i, err)
+		}
+
+		out[i] = *condition
+	}
+
+	return out, nil
+}
+
+// jActionMessage is a private map for the Zabbix API Action operation message
+// object.
+type jActionMessage struct {
+	DefaultMsg  string `json:"default_msg"`
+	Subject     string `json:"subject"`
+	Message     string `json:"message"`
+	MediaTypeID string `json:"mediatypeid,omitempty"`
+}
+
+// jActionCommand is a private map for the Zabbix API Action operation command
+// object.
+type jActionCommand struct {
+	ScriptID  string `json:"scriptid,omitempty"`
+	Type      string `json:"type,omitempty"`
+	Command   string `json:"command,omitempty"`
+	ExecuteOn string `json:"execute_on,omitempty"`
+}
+
+// jActionOperationCondition is a private map for the Zabbix API Action
+// operation condition object.
+type jActionOperationCondition struct {
+	ConditionType string `json:"conditiontype"`
+	Operator      string `json:"operator"`
+	Value         string `json:"value"`
+}
+
+// jActionOperation is a private map for the Zabbix API Action operation
+// object. It is used to both decode and encode operations.
+type jActionOperation struct {
+	OperationID   string                      `json:"operationid,omitempty"`
+	OperationType string                      `json:"operationtype"`
+	EscPeriod     string                      `json:"esc_period,omitempty"`
+	EscStepFrom   string                      `json:"esc_step_from,omitempty"`
+	EscStepTo     string                      `json:"esc_step_to,omitempty"`
+	EvalType      string                      `json:"evaltype,omitempty"`
+	OpConditions  []jActionOperationCondition `json:"opconditions,omitempty"`
+	OpMessage     json.RawMessage             `json:"opmessage,omitempty"`
+	OpMessageGrp  []map[string]string         `json:"opmessage_grp,omitempty"`
+	OpMessageUsr  []map[string]string         `json:"opmessage_usr,omitempty"`
+	OpCommand     json.RawMessage             `json:"opcommand,omitempty"`
+	OpCommandGrp  []map[string]string         `json:"opcommand_grp,omitempty"`
+	OpCommandHst  []map[string]string         `json:"opcommand_hst,omitempty"`
+	OpGroup       []map[string]string         `json:"opgroup,omitempty"`
+	OpTemplate    []map[string]string         `json:"optemplate,omitempty"`
+	OpInventory   json.RawMessage             `json:"opinventory,omitempty"`
+}
+
+// ids returns the values of the given field of each of the given objects.
+func ids(objects []map[string]string, field string) []string {
+	if objects == nil {
+		return nil
+	}
+
+	out := make([]string, len(objects))
+	for i, o := range objects {
+		out[i] = o[field]
+	}
+
+	return out
+}
+
+// unmarshalObject decodes the given JSON object into v. Zabbix returns an empty
+// array instead of an object for missing objects, which is ignored, and false is
+// returned.
+func unmarshalObject(data json.RawMessage, v interface{}) (bool, error) {
+	if len(data) == 0 || data[0] != '{' {
+		return false, nil
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Operation returns a native Go ActionOperation struct mapped from the given
+// JSON Action operation data.
+func (c *jActionOperation) Operation() (*ActionOperation, error) {
+	var err error
+	operation := &ActionOperation{
+		OperationID:         c.OperationID,
+		UserGroupIDs:        ids(c.OpMessageGrp, "usrgrpid"),
+		UserIDs:             ids(c.OpMessageUsr, "userid"),
+		CommandHostGroupIDs: ids(c.OpCommandGrp, "groupid"),
+		CommandHostIDs:      ids(c.OpCommandHst, "hostid"),
+		HostGroupIDs:        ids(c.OpGroup, "groupid"),
+		TemplateIDs:         ids(c.OpTemplate, "templateid"),
+	}
+
+	if operation.OperationType, err = atoi(c.OperationType); err != nil {
+		return nil, fmt.Errorf("Error parsing Action Operation Type: %v", err)
+	}
+
+	if operation.StepDuration, err = parseStepDuration(c.EscPeriod); err != nil {
+		return nil, fmt.Errorf("Error parsing Action Operation Step Duration: %v", err)
+	}
+
+	if operation.StepFrom, err = atoi(c.EscStepFrom); err != nil {
+		return nil, fmt.Errorf("Error parsing Action Operation Step From: %v", err)
+	}
+
+	if operation.StepTo, err = atoi(c.EscStepTo); err != nil {
+		return nil, fmt.Errorf("Error parsing Action Operation Step To: %v", err)
+	}
+
+	if operation.EvaluationType, err = atoi(c.EvalType); err != nil {
+		return nil, fmt.Errorf("Error parsing Action Operation Evaluation Type: %v", err)
+	}
+
+	if c.OpConditions != nil {
+		operation.Conditions = make([]ActionOperationCondition, len(c.OpConditions))
+		for i, jcondition := range c.OpConditions {
+			condition := &operation.Conditions[i]
+			condition.Value = jcondition.Value
+			if condition.ConditionType, err = atoi(jcondition.ConditionType); err != nil {
+				return nil, fmt.Errorf("Error parsing Action Operation Condition Type: %v", err)
+			}
+			if condition.Operator, err = atoi(jcondition.Operator); err != nil {
+				return nil, fmt.Errorf("Error parsing Action Operation Condition Operator: %v", err)
+			}
+		}
+	}
+
+	var jmessage jActionMessage
+	if ok, err := unmarshalObject(c.OpMessage, &jmessage); err != nil {
+		return nil, fmt.Errorf("Error parsing Action Operation Message: %v", err)
+	} else if ok {
+		operation.Message = &ActionMessage{
+			DefaultMessage: jmessage.DefaultMsg == "1",
+			Subject:        jmessage.Subject,
+			Message:        jmessage.Message,
+			MediaTypeID:    jmessage.MediaTypeID,
+		}
+
+		// all media types are given as ID zero
+		if operation.Message.MediaTypeID == "0" {
+			operation.Message.MediaTypeID = ""
+		}
+	}
+
+	var jcommand jActionCommand
+	if ok, err := unmarshalObject(c.OpCommand, &jcommand); err != nil {
+		return nil, fmt.Errorf("Error parsing Action Operation Command: %v", err)
+	} else if ok {
+		operation.Command = &ActionCommand{
+			ScriptID: jcommand.ScriptID,
+			Command:  jcommand.Command,
+		}
+
+		if operation.Command.Type, err = atoi(jcommand.Type); err != nil {
+			return nil, fmt.Errorf("Error parsing Action Operation Command Type: %v", err)
+		}
+
+		if operation.Command.ExecuteOn, err = atoi(jcommand.ExecuteOn); err != nil {
+			return nil, fmt.Errorf("Error parsing Action Operation Command Execute On: %v", err)
+		}
+	}
+
+	var jinventory struct {
+		InventoryMode string `json:"inventory_mode"`
+	}
+	if ok, err := unmarshalObject(c.OpInventory, &jinventory); err != nil {
+		return nil, fmt.Errorf("Error parsing Action Operation Inventory: %v", err)
+	} else if ok {
+		if operation.InventoryMode, err = atoi(jinventory.InventoryMode); err != nil {
+			return nil, fmt.Errorf("Error parsing Action Operation Inventory Mode: %v", err)
+		}
+	}
+
+	return operation, nil
+}
+
+// newJActionOperation returns the JSON Action operation data of the given
+// ActionOperation, with only the objects used by its operation type.
+func newJActionOperation(c *ActionOperation) *jActionOperation {
+	joperation := &jActionOperation{
+		OperationID:   c.OperationID,
+		OperationType: strconv.Itoa(c.OperationType),
+		OpMessageGrp:  objectIDs("usrgrpid", c.UserGroupIDs),
+		OpMessageUsr:  objectIDs("userid", c.UserIDs),
+		OpCommandGrp:  objectIDs("groupid", c.CommandHostGroupIDs),
+		OpCommandHst:  objectIDs("hostid", c.CommandHostIDs),
+		OpGroup:       objectIDs("groupid", c.HostGroupIDs),
+		OpTemplate:    objectIDs("templateid", c.TemplateIDs),
+	}
+
+	// escalation steps are only valid for problem operations
+	if c.StepDuration > 0 {
+		joperation.EscPeriod = strconv.Itoa(c.StepDuration)
+	}
+
+	if c.StepFrom > 0 {
+		joperation.EscStepFrom = strconv.Itoa(c.StepFrom)
+		joperation.EscStepTo = strconv.Itoa(c.StepTo)
+	}
+
+	if len(c.Conditions) > 0 {
+		joperation.EvalType = strconv.Itoa(c.EvaluationType)
+		joperation.OpConditions = make([]jActionOperationCondition, len(c.Conditions))
+		for i, condition := range c.Conditions {
+			joperation.OpConditions[i] = jActionOperationCondition{
+				ConditionType: strconv.Itoa(condition.ConditionType),
+				Operator:      strconv.Itoa(condition.Operator),
+				Value:         condition.Value,
+			}
+		}
+	}
+
+	if c.Message != nil {
+		jmessage := jActionMessage{
+			DefaultMsg:  "0",
+			Subject:     c.Message.Subject,
+			Message:     c.Message.Message,
+			MediaTypeID: c.Message.MediaTypeID,
+		}
+		if c.Message.DefaultMessage {
+			jmessage.DefaultMsg = "1"
+		}
+
+		joperation.OpMessage, _ = json.Marshal(jmessage)
+	}
+
+	if c.Command != nil {
+		jcommand := jActionCommand{
+			ScriptID: c.Command.ScriptID,
+			Command:  c.Command.Command,
+		}
+
+		// commands other than global scripts are removed in v5.4
+		if c.Command.Command != "" {
+			jcommand.Type = strconv.Itoa(c.Command.Type)
+			jcommand.ExecuteOn = strconv.Itoa(c.Command.ExecuteOn)
+		}
+
+		joperation.OpCommand, _ = json.Marshal(jcommand)
+	}
+
+	if c.OperationType == ActionOperationTypeSetInventoryMode {
+		joperation.OpInventory, _ = json.Marshal(map[string]string{
+			"inventory_mode": strconv.Itoa(c.InventoryMode),
+		})
+	}
+
+	return joperation
+}
+
+// jActionOperations is a slice of jActionOperation structs.
+type jActionOperations []jActionOperation
+
+// Operations returns a native Go slice of ActionOperations mapped from the
+// given JSON Action operations data.
+func (c jActionOperations) Operations() ([]ActionOperation, error) {
+	if c == nil {
+		return nil, nil
+	}
+
+	out := make([]ActionOperation, len(c))
+	for i, joperation := range c {
+		operation, err := joperation.Operation()
+		if err != nil {
+			return nil, fmt.Errorf("Error unmarshalling Action Operation %d in JSON data: %v", i, err)
+		}
+
+		out[i] = *operation
+	}
+
+	return out, nil
+}
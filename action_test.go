@@ -1,9 +1,162 @@
 package zabbix
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
 )
 
+func TestActionMapping(t *testing.T) {
+	data := `{
+		"actionid": "7",
+		"name": "Notify on-call",
+		"eventsource": "0",
+		"status": "0",
+		"esc_period": "1h",
+		"def_shortdata": "Problem: {EVENT.NAME}",
+		"r_shortdata": "Resolved: {EVENT.NAME}",
+		"r_longdata": "Problem has been resolved",
+		"recovery_msg": "1",
+		"maintenance_mode": "1",
+		"filter": {
+			"evaltype": "3",
+			"formula": "A and B",
+			"eval_formula": "A and B",
+			"conditions": [
+				{"conditiontype": "0", "operator": "0", "value": "2", "value2": "", "formulaid": "A"},
+				{"conditiontype": "4", "operator": "5", "value": "3", "value2": "", "formulaid": "B"}
+			]
+		},
+		"operations": [
+			{
+				"operationid": "10",
+				"operationtype": "0",
+				"esc_period": "0",
+				"esc_step_from": "1",
+				"esc_step_to": "2",
+				"evaltype": "0",
+				"opconditions": [{"conditiontype": "14", "operator": "0", "value": "0"}],
+				"opmessage": {"default_msg": "1", "subject": "", "message": "", "mediatypeid": "0"},
+				"opmessage_grp": [{"usrgrpid": "8"}],
+				"opmessage_usr": [{"userid": "2"}]
+			},
+			{
+				"operationid": "11",
+				"operationtype": "1",
+				"esc_period": "10m",
+				"esc_step_from": "3",
+				"esc_step_to": "0",
+				"opmessage": [],
+				"opcommand": {"scriptid": "5"},
+				"opcommand_hst": [{"hostid": "0"}]
+			}
+		],
+		"recoveryOperations": [
+			{"operationid": "12", "operationtype": "11", "opmessage": {"default_msg": "0", "subject": "Resolved", "message": "OK"}}
+		]
+	}`
+
+	var jaction jAction
+	if err := json.Unmarshal([]byte(data), &jaction); err != nil {
+		t.Fatalf("Error unmarshalling Action: %v", err)
+	}
+
+	action, err := jaction.Action()
+	if err != nil {
+		t.Fatalf("Error mapping Action: %v", err)
+	}
+
+	if action.StepDuration != 3600 || !action.PauseSuppressed || action.RecoveryMessageBody != "Problem has been resolved" {
+		t.Errorf("Unexpected Action fields: %+v", action)
+	}
+
+	if action.EvaluationType != ActionEvalTypeCustom || action.Formula != "A and B" || len(action.Conditions) != 2 {
+		t.Fatalf("Unexpected Action filter: %+v", action)
+	}
+
+	if c := action.Conditions[1]; c.ConditionType != ActionConditionTypeTriggerSeverity || c.Operator != ActionConditionOperatorGreaterOrEqual || c.FormulaID != "B" {
+		t.Errorf("Unexpected Action condition: %+v", c)
+	}
+
+	if len(action.Operations) != 2 {
+		t.Fatalf("Expected 2 operations, got %d", len(action.Operations))
+	}
+
+	op := action.Operations[0]
+	if op.StepFrom != 1 || op.StepTo != 2 || len(op.Conditions) != 1 || op.Conditions[0].ConditionType != ActionConditionTypeEventAcknowledged {
+		t.Errorf("Unexpected Action operation: %+v", op)
+	}
+
+	if op.Message == nil || !op.Message.DefaultMessage || op.Message.MediaTypeID != "" || op.UserGroupIDs[0] != "8" || op.UserIDs[0] != "2" {
+		t.Errorf("Unexpected Action operation message: %+v", op)
+	}
+
+	op = action.Operations[1]
+	if op.StepDuration != 600 || op.Message != nil || op.Command == nil || op.Command.ScriptID != "5" || op.CommandHostIDs[0] != "0" {
+		t.Errorf("Unexpected Action operation command: %+v", op)
+	}
+
+	if len(action.RecoveryOperations) != 1 || action.RecoveryOperations[0].Message.Subject != "Resolved" {
+		t.Errorf("Unexpected Action recovery operations: %+v", action.RecoveryOperations)
+	}
+}
+
+func TestActionOperationMarshal(t *testing.T) {
+	b, err := json.Marshal(ActionOperation{
+		OperationType: ActionOperationTypeSendMessage,
+		StepFrom:      1,
+		StepTo:        1,
+		Message:       &ActionMessage{DefaultMessage: true, MediaTypeID: "1"},
+		UserGroupIDs:  []string{"8"},
+	})
+	if err != nil {
+		t.Fatalf("Error marshalling Action operation: %v", err)
+	}
+
+	expect := `{"operationtype":"0","esc_step_from":"1","esc_step_to":"1","opmessage":{"default_msg":"1","subject":"","message":"","mediatypeid":"1"},"opmessage_grp":[{"usrgrpid":"8"}]}`
+	if string(b) != expect {
+		t.Errorf("Expected %s, got %s", expect, b)
+	}
+
+	// recovery operations have no escalation steps
+	b, err = json.Marshal(ActionOperation{OperationType: ActionOperationTypeNotifyRecoveryAll, Message: &ActionMessage{DefaultMessage: true}})
+	if err != nil || strings.Contains(string(b), "esc_") {
+		t.Errorf("Unexpected recovery operation: %s (%v)", b, err)
+	}
+}
+
+func TestActionParams(t *testing.T) {
+	empty := []ActionOperation{}
+	update := []ActionOperation{{OperationType: ActionOperationTypeNotifyUpdateAll, Message: &ActionMessage{DefaultMessage: true}}}
+	params := []ActionUpdateParams{{ActionID: "7", Operations: &empty, UpdateOperations: &update}}
+
+	// an empty slice of operations clears them
+	session := &Session{APIVersion: "6.2.0"}
+	query, err := session.actionParams(params)
+	if err != nil {
+		t.Fatalf("Error mapping Action parameters: %v", err)
+	}
+
+	b, _ := json.Marshal(query)
+	if !strings.Contains(string(b), `"operations":[]`) ||
+		!strings.Contains(string(b), `"update_operations":[{`) ||
+		strings.Contains(string(b), "recovery_operations") {
+		t.Errorf("Unexpected Action parameters: %s", b)
+	}
+
+	// update operations are named acknowledge operations before v5.0
+	session = &Session{APIVersion: "4.4.0"}
+	query, err = session.actionParams(params)
+	if err != nil {
+		t.Fatalf("Error mapping Action parameters: %v", err)
+	}
+
+	b, _ = json.Marshal(query)
+	if !strings.Contains(string(b), `"acknowledge_operations":[{`) || strings.Contains(string(b), "update_operations") {
+		t.Errorf("Unexpected Action parameters for v4.4: %s", b)
+	}
+}
+
 func TestActions(t *testing.T) {
 	session := GetTestSession(t)
 
@@ -34,3 +187,61 @@ func TestActions(t *testing.T) {
 
 	t.Logf("Validated %d Actions", len(actions))
 }
+
+func TestActionCRUD(t *testing.T) {
+	session := GetTestSession(t)
+
+	groups, err := session.GetHostgroups(HostgroupGetParams{})
+	if err != nil {
+		t.Fatalf("Error getting Hostgroups: %v", err)
+	}
+
+	actionIDs, err := session.CreateActions(ActionCreateParams{
+		Name:         "go-zabbix test action",
+		EventSource:  EventSourceTrigger,
+		StepDuration: "1h",
+		Status:       ActionStatusDisabled,
+		Filter: &ActionFilter{
+			EvaluationType: ActionEvalTypeAnd,
+			Conditions: []ActionCondition{
+				{ConditionType: ActionConditionTypeHostGroup, Operator: ActionConditionOperatorEqual, Value: groups[0].GroupID},
+				{ConditionType: ActionConditionTypeTriggerSeverity, Operator: ActionConditionOperatorGreaterOrEqual, Value: "4"},
+			},
+		},
+		Operations: []ActionOperation{
+			{
+				OperationType: ActionOperationTypeSendMessage,
+				StepFrom:      1,
+				StepTo:        1,
+				Message:       &ActionMessage{DefaultMessage: true},
+				UserIDs:       []string{"1"},
+			},
+		},
+		RecoveryOperations: []ActionOperation{
+			{OperationType: ActionOperationTypeNotifyRecoveryAll, Message: &ActionMessage{DefaultMessage: true}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Error creating Action: %v", err)
+	}
+	defer session.DeleteActions(actionIDs...)
+
+	actions, err := session.GetActions(ActionGetParams{
+		ActionIDs:                actionIDs,
+		SelectFilter:             SelectExtendedOutput,
+		SelectOperations:         SelectExtendedOutput,
+		SelectRecoveryOperations: SelectExtendedOutput,
+	})
+	if err != nil {
+		t.Fatalf("Error getting Action: %v", err)
+	}
+
+	if len(actions[0].Conditions) != 2 || len(actions[0].Operations) != 1 || len(actions[0].RecoveryOperations) != 1 {
+		t.Errorf("Unexpected Action: %+v", actions[0])
+	}
+
+	status := ActionStatusEnabled
+	if _, err := session.UpdateActions(ActionUpdateParams{ActionID: actionIDs[0], Status: &status}); err != nil {
+		t.Errorf("Error updating Action: %v", err)
+	}
+}
@@ -100,12 +100,75 @@ type Alert struct {
 	// Hosts is only populated if AlertGetParams.SelectHosts is given in the
 	// query parameters that returned this Alert.
 	Hosts []Host
+
+	// MediaTypes is an array of the Media Types used to send this Alert.
+	//
+	// MediaTypes is only populated if AlertGetParams.SelectMediaTypes is
+	// given in the query parameters that returned this Alert.
+	MediaTypes []MediaType
+
+	// Users is an array of the Users this Alert was addressed to.
+	//
+	// Users is only populated if AlertGetParams.SelectUsers is given in the
+	// query parameters that returned this Alert.
+	Users []User
 }
 
 // AlertGetParams is query params for alert.get call
 type AlertGetParams struct {
 	GetParameters
 
+	// AlertIDs filters search results to Alerts that matched the given Alert
+	// IDs.
+	AlertIDs []string `json:"alertids,omitempty"`
+
+	// ActionIDs filters search results to Alerts generated by the given
+	// Actions.
+	ActionIDs []string `json:"actionids,omitempty"`
+
+	// EventIDs filters search results to Alerts generated by the given
+	// Events.
+	EventIDs []string `json:"eventids,omitempty"`
+
+	// GroupIDs filters search results to Alerts generated by objects of hosts
+	// that are members of the given Group IDs.
+	GroupIDs []string `json:"groupids,omitempty"`
+
+	// HostIDs filters search results to Alerts generated by objects of the
+	// given Hosts.
+	HostIDs []string `json:"hostids,omitempty"`
+
+	// MediaTypeIDs filters search results to Alerts sent with the given Media
+	// Types.
+	MediaTypeIDs []string `json:"mediatypeids,omitempty"`
+
+	// ObjectIDs filters search results to Alerts generated by the given
+	// objects.
+	ObjectIDs []string `json:"objectids,omitempty"`
+
+	// UserIDs filters search results to Alerts addressed to the given Users.
+	UserIDs []string `json:"userids,omitempty"`
+
+	// EventSource filters search results to Alerts generated by Events of the
+	// given source. Must be one of the EventSource constants.
+	//
+	// Default: EventSourceTrigger
+	EventSource int `json:"eventsource"`
+
+	// EventObject filters search results to Alerts generated by Events of the
+	// given Object Type. Must be one of the EventObjectType constants.
+	//
+	// Default: EventObjectTypeTrigger
+	EventObject int `json:"eventobject"`
+
+	// MinTime filters search results to Alerts generated at or after the
+	// given timestamp.
+	MinTime int64 `json:"time_from,omitempty"`
+
+	// MaxTime filters search results to Alerts generated at or before the
+	// given timestamp.
+	MaxTime int64 `json:"time_till,omitempty"`
+
 	// SelectHosts causes all Hosts which triggered the Alert to be attached in
 	// the search results.
 	SelectHosts SelectQuery `json:"selectHosts,omitempty"`
@@ -148,3 +211,12 @@ func (c *Session) GetAlerts(params AlertGetParams) ([]Alert, error) {
 
 	return out, nil
 }
+
+// CountAlerts queries the Zabbix API for the number of Alerts matching the
+// given search parameters.
+//
+// An error is returned if a transport, parsing or API error occurs.
+func (c *Session) CountAlerts(params AlertGetParams) (int, error) {
+	params.CountOutput = true
+	return c.count("alert.get", params)
+}
@@ -22,6 +22,9 @@ type jAlert struct {
 	Subject     string `json:"subject"`
 	UserID      string `json:"userid"`
 	Hosts       jHosts `json:"hosts"`
+
+	MediaTypes jMediaTypes `json:"mediatypes"`
+	Users      jUsers      `json:"users"`
 }
 
 // Alert returns a native Go Alert struct mapped from the given JSON Alert data.
@@ -50,5 +53,13 @@ func (c *jAlert) Alert() (*Alert, error) {
 		return nil, err
 	}
 
+	// map Media Types and Users
+	alert.MediaTypes, err = c.MediaTypes.MediaTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	alert.Users = c.Users.Users()
+
 	return alert, nil
 }
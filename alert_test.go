@@ -1,9 +1,47 @@
 package zabbix
 
 import (
+	"encoding/json"
 	"testing"
 )
 
+func TestAlertMapping(t *testing.T) {
+	data := `{
+		"alertid": "3",
+		"actionid": "7",
+		"alerttype": "0",
+		"clock": "1600000001",
+		"error": "",
+		"esc_step": "1",
+		"eventid": "500",
+		"mediatypeid": "1",
+		"retries": "0",
+		"sendto": "ops@example.com",
+		"status": "1",
+		"userid": "2",
+		"mediatypes": [{"mediatypeid": "1", "description": "Email", "type": "0", "status": "0"}],
+		"users": [{"userid": "2", "alias": "oncall", "name": "On", "surname": "Call"}]
+	}`
+
+	var jalert jAlert
+	if err := json.Unmarshal([]byte(data), &jalert); err != nil {
+		t.Fatalf("Error unmarshalling Alert: %v", err)
+	}
+
+	alert, err := jalert.Alert()
+	if err != nil {
+		t.Fatalf("Error mapping Alert: %v", err)
+	}
+
+	if len(alert.MediaTypes) != 1 || alert.MediaTypes[0].Name != "Email" || alert.MediaTypes[0].Type != MediaTypeEmail || !alert.MediaTypes[0].Enabled {
+		t.Errorf("Unexpected Alert media types: %+v", alert.MediaTypes)
+	}
+
+	if len(alert.Users) != 1 || alert.Users[0].Username != "oncall" || alert.Users[0].Surname != "Call" {
+		t.Errorf("Unexpected Alert users: %+v", alert.Users)
+	}
+}
+
 func TestAlerts(t *testing.T) {
 	session := GetTestSession(t)
 
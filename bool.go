@@ -9,12 +9,21 @@ type ZBXBoolean bool
 
 func (bit *ZBXBoolean) UnmarshalJSON(data []byte) error {
 	asString := string(data)
-	if asString == "1" || asString == "true" {
+	if asString == "1" || asString == "true" || asString == `"1"` {
 		*bit = true
-	} else if asString == "0" || asString == "false" {
+	} else if asString == "0" || asString == "false" || asString == `"0"` {
 		*bit = false
 	} else {
 		return errors.New(fmt.Sprintf("Boolean unmarshal error: invalid input %s", asString))
 	}
 	return nil
 }
+
+// MarshalJSON encodes a ZBXBoolean as the "0" or "1" strings expected by the
+// Zabbix API.
+func (bit ZBXBoolean) MarshalJSON() ([]byte, error) {
+	if bit {
+		return []byte(`"1"`), nil
+	}
+	return []byte(`"0"`), nil
+}
@@ -0,0 +1,96 @@
+package zabbix
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// GroupCount represents a single row of a `countOutput` API query which was
+// grouped with `groupCount`.
+type GroupCount struct {
+	// Fields contains the value of each output field which the records in
+	// this group have in common, keyed by field name.
+	Fields map[string]string
+
+	// Count is the number of records in this group.
+	Count int
+}
+
+// count calls the given Zabbix API get method with parameters which have
+// `countOutput` set and returns the resulting number of records.
+//
+// The Zabbix API returns record counts as a string, so both JSON strings and
+// numbers are accepted.
+func (c *Session) count(method string, params interface{}) (int, error) {
+	var v interface{}
+	if err := c.Get(method, params, &v); err != nil {
+		return 0, err
+	}
+
+	switch n := v.(type) {
+	case string:
+		count, err := strconv.Atoi(n)
+		if err != nil {
+			return 0, fmt.Errorf("Error parsing record count: %v", err)
+		}
+		return count, nil
+
+	case float64:
+		return int(n), nil
+	}
+
+	return 0, fmt.Errorf("Error parsing record count: unexpected type %T", v)
+}
+
+// groupCount calls the given Zabbix API get method with parameters which have
+// both `countOutput` and `groupCount` set and returns each group of records
+// with the number of records in the group.
+func (c *Session) groupCount(method string, params interface{}) ([]GroupCount, error) {
+	rows := make([]map[string]json.RawMessage, 0)
+	if err := c.Get(method, params, &rows); err != nil {
+		return nil, err
+	}
+
+	out := make([]GroupCount, len(rows))
+	for i, row := range rows {
+		group := GroupCount{Fields: make(map[string]string, len(row))}
+		for field, raw := range row {
+			var value string
+			if err := json.Unmarshal(raw, &value); err != nil {
+				// numeric values are returned unquoted by some API versions
+				value = string(raw)
+			}
+
+			if field == "rowscount" {
+				count, err := strconv.Atoi(value)
+				if err != nil {
+					return nil, fmt.Errorf("Error parsing record count of group %d: %v", i, err)
+				}
+				group.Count = count
+				continue
+			}
+
+			group.Fields[field] = value
+		}
+
+		out[i] = group
+	}
+
+	return out, nil
+}
+
+// countBySeverity maps grouped count results to a map of record counts keyed by
+// the severity constants found in the given field.
+func countBySeverity(groups []GroupCount, field string) (map[int]int, error) {
+	out := make(map[int]int, len(groups))
+	for _, group := range groups {
+		severity, err := strconv.Atoi(group.Fields[field])
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing grouped severity: %v", err)
+		}
+		out[severity] += group.Count
+	}
+
+	return out, nil
+}
@@ -0,0 +1,499 @@
+package coverage
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cavaliercoder/go-zabbix"
+)
+
+// evaluator evaluates the conditions of an Action against an Event.
+type evaluator struct {
+	event      *Event
+	suppressed bool
+	loc        *time.Location
+
+	// parents are the IDs of the parent host groups of Events, by name
+	parents map[string]string
+
+	// unsupported are the conditions which cannot be evaluated locally
+	unsupported []zabbix.ActionCondition
+}
+
+// action returns true if the conditions of the given Action are met.
+func (c *evaluator) action(action *zabbix.Action) bool {
+	if len(action.Conditions) == 0 {
+		return true
+	}
+
+	values := make([]bool, len(action.Conditions))
+	for i := range action.Conditions {
+		values[i] = c.condition(&action.Conditions[i])
+	}
+
+	switch action.EvaluationType {
+	case zabbix.ActionEvalTypeAnd:
+		for _, v := range values {
+			if !v {
+				return false
+			}
+		}
+		return true
+
+	case zabbix.ActionEvalTypeOr:
+		for _, v := range values {
+			if v {
+				return true
+			}
+		}
+		return false
+
+	case zabbix.ActionEvalTypeCustom:
+		formula := action.Formula
+		if formula == "" {
+			formula = action.EvalFormula
+		}
+
+		labels := make(map[string]bool, len(values))
+		for i, cond := range action.Conditions {
+			labels[cond.FormulaID] = values[i]
+		}
+
+		v, err := evalFormula(formula, labels)
+		if err != nil {
+			c.unsupported = append(c.unsupported, action.Conditions...)
+			return true
+		}
+		return v
+	}
+
+	// conditions of the same type are or'ed and of different types and'ed
+	byType := make(map[int]bool)
+	for i, cond := range action.Conditions {
+		byType[cond.ConditionType] = byType[cond.ConditionType] || values[i]
+	}
+	for _, v := range byType {
+		if !v {
+			return false
+		}
+	}
+
+	return true
+}
+
+// condition returns true if the given condition is met. Conditions which
+// cannot be evaluated are recorded as unsupported and are met.
+func (c *evaluator) condition(cond *zabbix.ActionCondition) bool {
+	v, ok := c.evaluate(cond)
+	if !ok {
+		c.unsupported = append(c.unsupported, *cond)
+		return true
+	}
+
+	return v
+}
+
+// evaluate returns the value of the given condition, and false if it cannot be
+// evaluated.
+func (c *evaluator) evaluate(cond *zabbix.ActionCondition) (bool, bool) {
+	trigger := &c.event.Trigger
+	switch cond.ConditionType {
+	case zabbix.ActionConditionTypeHostGroup:
+		// host groups match their nested host groups as well
+		ids := make([]string, 0, len(trigger.Groups))
+		for _, group := range trigger.Groups {
+			ids = append(ids, group.GroupID)
+			for _, name := range parentGroupNames(group.Name) {
+				if id, ok := c.parents[name]; ok {
+					ids = append(ids, id)
+				}
+			}
+		}
+		return compareIDs(cond, ids)
+
+	case zabbix.ActionConditionTypeHost:
+		ids := make([]string, len(trigger.Hosts))
+		for i, host := range trigger.Hosts {
+			ids[i] = host.HostID
+		}
+		return compareIDs(cond, ids)
+
+	case zabbix.ActionConditionTypeHostTemplate:
+		return compareIDs(cond, c.event.TemplateIDs)
+
+	case zabbix.ActionConditionTypeTrigger:
+		return compareIDs(cond, []string{trigger.TriggerID})
+
+	case zabbix.ActionConditionTypeEventName:
+		return compareString(cond.Operator, c.event.name(), cond.Value)
+
+	case zabbix.ActionConditionTypeTriggerSeverity:
+		severity, err := strconv.Atoi(cond.Value)
+		if err != nil {
+			return false, false
+		}
+
+		switch cond.Operator {
+		case zabbix.ActionConditionOperatorEqual:
+			return trigger.Severity == severity, true
+		case zabbix.ActionConditionOperatorNotEqual:
+			return trigger.Severity != severity, true
+		case zabbix.ActionConditionOperatorGreaterOrEqual:
+			return trigger.Severity >= severity, true
+		case zabbix.ActionConditionOperatorLessOrEqual:
+			return trigger.Severity <= severity, true
+		}
+
+	case zabbix.ActionConditionTypeTimePeriod:
+		in, err := inTimePeriod(cond.Value, c.event.Time.In(c.loc))
+		if err != nil {
+			return false, false
+		}
+
+		switch cond.Operator {
+		case zabbix.ActionConditionOperatorIn:
+			return in, true
+		case zabbix.ActionConditionOperatorNotIn:
+			return !in, true
+		}
+
+	case zabbix.ActionConditionTypeProblemSuppressed:
+		switch cond.Operator {
+		case zabbix.ActionConditionOperatorYes:
+			return c.suppressed, true
+		case zabbix.ActionConditionOperatorNo:
+			return !c.suppressed, true
+		}
+
+	case zabbix.ActionConditionTypeEventTag:
+		return compareTags(cond.Operator, c.event.tags(), func(tag zabbix.TriggerTag) string { return tag.Name }, cond.Value)
+
+	case zabbix.ActionConditionTypeEventTagValue:
+		tags := make([]zabbix.TriggerTag, 0)
+		for _, tag := range c.event.tags() {
+			if tag.Name == cond.Value2 {
+				tags = append(tags, tag)
+			}
+		}
+		return compareTags(cond.Operator, tags, func(tag zabbix.TriggerTag) string { return tag.Value }, cond.Value)
+	}
+
+	return false, false
+}
+
+// parentGroupNames returns the names of the parent host groups of the host
+// group with the given name, such as "Linux" for "Linux/Web".
+func parentGroupNames(name string) []string {
+	out := make([]string, 0)
+	for i := strings.LastIndex(name, "/"); i > 0; i = strings.LastIndex(name[:i], "/") {
+		out = append(out, name[:i])
+	}
+
+	return out
+}
+
+// compareIDs compares the value of the given condition with the given IDs.
+func compareIDs(cond *zabbix.ActionCondition, ids []string) (bool, bool) {
+	found := false
+	for _, id := range ids {
+		found = found || id == cond.Value
+	}
+
+	switch cond.Operator {
+	case zabbix.ActionConditionOperatorEqual:
+		return found, true
+	case zabbix.ActionConditionOperatorNotEqual:
+		return !found, true
+	}
+
+	return false, false
+}
+
+// compareString compares s with the given value.
+func compareString(operator int, s, value string) (bool, bool) {
+	switch operator {
+	case zabbix.ActionConditionOperatorEqual:
+		return s == value, true
+	case zabbix.ActionConditionOperatorNotEqual:
+		return s != value, true
+	case zabbix.ActionConditionOperatorContains:
+		return strings.Contains(s, value), true
+	case zabbix.ActionConditionOperatorNotContains:
+		return !strings.Contains(s, value), true
+	}
+
+	return false, false
+}
+
+// compareTags returns true if any of the given tags matches the given value
+// with a positive operator, or if none matches with a negative operator.
+func compareTags(operator int, tags []zabbix.TriggerTag, field func(zabbix.TriggerTag) string, value string) (bool, bool) {
+	positive := operator
+	switch operator {
+	case zabbix.ActionConditionOperatorNotEqual:
+		positive = zabbix.ActionConditionOperatorEqual
+	case zabbix.ActionConditionOperatorNotContains:
+		positive = zabbix.ActionConditionOperatorContains
+	}
+
+	found := false
+	for _, tag := range tags {
+		v, ok := compareString(positive, field(tag), value)
+		if !ok {
+			return false, false
+		}
+		found = found || v
+	}
+
+	if positive != operator {
+		return !found, true
+	}
+
+	return found, true
+}
+
+// inTimePeriod returns true if t is in the given Zabbix time period, such as
+// "1-5,09:00-18:00;6-7,10:00-16:00", where days are 1 for Monday to 7 for
+// Sunday.
+func inTimePeriod(period string, t time.Time) (bool, error) {
+	day := int(t.Weekday())
+	if day == 0 {
+		day = 7
+	}
+	minute := t.Hour()*60 + t.Minute()
+
+	for _, p := range strings.Split(period, ";") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+
+		parts := strings.SplitN(p, ",", 2)
+		if len(parts) != 2 {
+			return false, fmt.Errorf("Invalid time period: %s", p)
+		}
+
+		fromDay, tillDay, err := parseRange(parts[0], "-", func(s string) (int, error) {
+			d, err := strconv.Atoi(s)
+			if err == nil && (d < 1 || d > 7) {
+				err = fmt.Errorf("Invalid day of week: %s", s)
+			}
+			return d, err
+		})
+		if err != nil {
+			return false, err
+		}
+
+		from, till, err := parseRange(parts[1], "-", parseClock)
+		if err != nil {
+			return false, err
+		}
+
+		if day >= fromDay && day <= tillDay && minute >= from && minute < till {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// parseRange parses a range of two values separated by sep. A single value is
+// a range of that value.
+func parseRange(s, sep string, parse func(string) (int, error)) (int, int, error) {
+	parts := strings.SplitN(s, sep, 2)
+	from, err := parse(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if len(parts) == 1 {
+		return from, from, nil
+	}
+
+	till, err := parse(strings.TrimSpace(parts[1]))
+	return from, till, err
+}
+
+// parseClock parses a time of day as "hh:mm" in minutes after midnight.
+func parseClock(s string) (int, error) {
+	parts := strings.SplitN(s, ":", 2)
+	if len(parts) != 2 {
+		return 0, fmt.Errorf("Invalid time of day: %s", s)
+	}
+
+	h, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, fmt.Errorf("Invalid time of day: %s", s)
+	}
+
+	m, err := strconv.Atoi(parts[1])
+	if err != nil || h < 0 || h > 24 || m < 0 || m > 59 {
+		return 0, fmt.Errorf("Invalid time of day: %s", s)
+	}
+
+	return h*60 + m, nil
+}
+
+// operationConditions returns true if the conditions of the given operation
+// are met by the given Event.
+func operationConditions(op *zabbix.ActionOperation, event *Event) bool {
+	if len(op.Conditions) == 0 {
+		return true
+	}
+
+	acknowledged := "0"
+	if event.Acknowledged {
+		acknowledged = "1"
+	}
+
+	values := make([]bool, len(op.Conditions))
+	for i, cond := range op.Conditions {
+		values[i] = cond.ConditionType != zabbix.ActionConditionTypeEventAcknowledged || cond.Value == acknowledged
+	}
+
+	// operation conditions are all of the same type, so they are or'ed unless
+	// and'ed explicitly
+	if op.EvaluationType != zabbix.ActionEvalTypeAnd {
+		for _, v := range values {
+			if v {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, v := range values {
+		if !v {
+			return false
+		}
+	}
+	return true
+}
+
+// evalFormula evaluates a custom condition formula such as "(A or B) and not
+// C" with the given values of its labels.
+func evalFormula(formula string, labels map[string]bool) (bool, error) {
+	tokens := tokenizeFormula(formula)
+	p := &formulaParser{tokens: tokens, labels: labels}
+	v, err := p.or()
+	if err != nil {
+		return false, err
+	}
+
+	if p.pos != len(tokens) {
+		return false, fmt.Errorf("Unexpected token in formula: %s", tokens[p.pos])
+	}
+
+	return v, nil
+}
+
+// tokenizeFormula splits a formula into parentheses and words.
+func tokenizeFormula(formula string) []string {
+	tokens := make([]string, 0)
+	word := strings.Builder{}
+	flush := func() {
+		if word.Len() > 0 {
+			tokens = append(tokens, word.String())
+			word.Reset()
+		}
+	}
+
+	for _, r := range formula {
+		switch {
+		case r == '(' || r == ')':
+			flush()
+			tokens = append(tokens, string(r))
+		case r == ' ' || r == '\t' || r == '\n':
+			flush()
+		default:
+			word.WriteRune(r)
+		}
+	}
+	flush()
+
+	return tokens
+}
+
+// formulaParser is a recursive descent parser of condition formulas.
+type formulaParser struct {
+	tokens []string
+	pos    int
+	labels map[string]bool
+}
+
+// peek returns the next token, or an empty string at the end.
+func (c *formulaParser) peek() string {
+	if c.pos < len(c.tokens) {
+		return c.tokens[c.pos]
+	}
+
+	return ""
+}
+
+// or parses a disjunction of conjunctions.
+func (c *formulaParser) or() (bool, error) {
+	v, err := c.and()
+	for err == nil && c.peek() == "or" {
+		c.pos++
+		var w bool
+		w, err = c.and()
+		v = v || w
+	}
+
+	return v, err
+}
+
+// and parses a conjunction of negations.
+func (c *formulaParser) and() (bool, error) {
+	v, err := c.not()
+	for err == nil && c.peek() == "and" {
+		c.pos++
+		var w bool
+		w, err = c.not()
+		v = v && w
+	}
+
+	return v, err
+}
+
+// not parses an optionally negated operand.
+func (c *formulaParser) not() (bool, error) {
+	if c.peek() == "not" {
+		c.pos++
+		v, err := c.not()
+		return !v, err
+	}
+
+	return c.operand()
+}
+
+// operand parses a condition label or a parenthesized formula.
+func (c *formulaParser) operand() (bool, error) {
+	token := c.peek()
+	c.pos++
+	switch token {
+	case "(":
+		v, err := c.or()
+		if err != nil {
+			return false, err
+		}
+
+		if c.peek() != ")" {
+			return false, fmt.Errorf("Missing closing parenthesis in formula")
+		}
+		c.pos++
+		return v, nil
+
+	case "":
+		return false, fmt.Errorf("Unexpected end of formula")
+	}
+
+	v, ok := c.labels[token]
+	if !ok {
+		return false, fmt.Errorf("Unknown condition in formula: %s", token)
+	}
+
+	return v, nil
+}
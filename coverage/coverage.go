@@ -0,0 +1,413 @@
+/*
+Package coverage simulates which Zabbix Actions would handle a problem of a
+Trigger, and which users they would notify.
+
+A Simulator evaluates the conditions of trigger Actions locally against an
+Event, including the maintenance status of its hosts and the time of the
+Event, and returns the escalation steps and recipients of each matching
+Action:
+
+	sim, events, err := coverage.Fetch(session, []string{"13491"}, time.Now())
+	if err != nil {
+		panic(err)
+	}
+
+	for _, result := range sim.Simulate(events[0]) {
+		for _, step := range result.Steps {
+			fmt.Println(result.Action.Name, step.Number, step.Start, step.Recipients)
+		}
+	}
+*/
+package coverage
+
+import (
+	"sort"
+	"time"
+
+	"github.com/cavaliercoder/go-zabbix"
+)
+
+// Event is a simulated problem Event of a Trigger.
+type Event struct {
+	// Trigger is the Trigger of the problem, which must have been queried
+	// with TriggerGetParams.SelectHosts, SelectGroups and SelectTags. Tags of
+	// the Hosts are event tags as well.
+	Trigger zabbix.Trigger
+
+	// TemplateIDs are the IDs of the templates the Trigger is inherited from,
+	// for ActionConditionTypeHostTemplate conditions.
+	TemplateIDs []string
+
+	// Time is the time of the problem.
+	Time time.Time
+
+	// Acknowledged is whether the problem is acknowledged when operation
+	// conditions are evaluated.
+	Acknowledged bool
+}
+
+// name returns the event name of the Trigger.
+func (c *Event) name() string {
+	if c.Trigger.EventName != "" {
+		return c.Trigger.EventName
+	}
+
+	return c.Trigger.Description
+}
+
+// tags returns the tags of the Trigger and its Hosts.
+func (c *Event) tags() []zabbix.TriggerTag {
+	out := append([]zabbix.TriggerTag{}, c.Trigger.Tags...)
+	for _, host := range c.Trigger.Hosts {
+		for _, tag := range host.Tags {
+			out = append(out, zabbix.TriggerTag{Name: tag.Tag, Value: tag.Value})
+		}
+	}
+
+	return out
+}
+
+// Recipient is a user group or user notified by an operation. Only one of
+// UserGroupID and UserID is set.
+type Recipient struct {
+	UserGroupID string
+	UserID      string
+
+	// MediaTypeID is the Media Type of the message, or empty if the message is
+	// sent with all Media Types of each user.
+	MediaTypeID string
+}
+
+// Step is an escalation step of an Action.
+type Step struct {
+	// Number is the number of the step, starting at 1.
+	Number int
+
+	// Start is the time of the step after the start of the escalation.
+	Start time.Duration
+
+	// Operations are the operations executed in the step whose conditions
+	// are met.
+	Operations []zabbix.ActionOperation
+
+	// Recipients are the recipients of the messages sent in the step.
+	Recipients []Recipient
+}
+
+// Result is an Action which would handle the Event.
+type Result struct {
+	// Action is the matching Action.
+	Action zabbix.Action
+
+	// Unsupported are the conditions of the Action which cannot be evaluated
+	// locally, such as time periods given as user macros. They are assumed to
+	// be met.
+	Unsupported []zabbix.ActionCondition
+
+	// Suppressed is whether the Event is in maintenance. Escalation is paused
+	// until the end of maintenance if Action.PauseSuppressed is set.
+	Suppressed bool
+
+	// Steps are the escalation steps with operations, in order.
+	Steps []Step
+
+	// Repeats is whether operations of the last step repeat until the problem
+	// is resolved.
+	Repeats bool
+}
+
+// Paused returns true if the escalation of the Action is paused by
+// maintenance.
+func (c *Result) Paused() bool {
+	return c.Suppressed && c.Action.PauseSuppressed
+}
+
+// Simulator evaluates Actions against Events.
+type Simulator struct {
+	// Actions are the Actions to evaluate, which must have been queried with
+	// ActionGetParams.SelectFilter and SelectOperations. Actions of other
+	// event sources are ignored.
+	Actions []zabbix.Action
+
+	// Maintenances are the Maintenances of the hosts of Events, which must
+	// have been queried with MaintenanceGetParams.SelectHosts, SelectGroups
+	// and SelectTimeperiods. Maintenance tags are not evaluated.
+	Maintenances []zabbix.Maintenance
+
+	// Hostgroups are the parent host groups of the host groups of Events, by
+	// name, such as "Linux" for "Linux/Web". Host group conditions match the
+	// nested host groups of their host group as well, which requires the
+	// parent host groups and the names of the host groups of Events.
+	Hostgroups []zabbix.Hostgroup
+
+	// Location is the time zone of the Zabbix server, in which time periods
+	// are evaluated. If nil, the local time zone is used.
+	Location *time.Location
+}
+
+// location returns the time zone of the Zabbix server.
+func (c *Simulator) location() *time.Location {
+	if c.Location == nil {
+		return time.Local
+	}
+
+	return c.Location
+}
+
+// Suppressed returns true if any host of the given Event is in maintenance at
+// the time of the Event.
+func (c *Simulator) Suppressed(event Event) bool {
+	for i := range c.Maintenances {
+		m := &c.Maintenances[i]
+		if m.AppliesTo(event.Trigger.Hosts, event.Trigger.Groups) &&
+			len(m.Windows(event.Time, event.Time.Add(time.Second), c.location())) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Simulate returns the enabled trigger Actions whose conditions are met by the
+// given Event, in the order given to the Simulator.
+func (c *Simulator) Simulate(event Event) []Result {
+	suppressed := c.Suppressed(event)
+	parents := make(map[string]string, len(c.Hostgroups))
+	for _, group := range c.Hostgroups {
+		parents[group.Name] = group.GroupID
+	}
+
+	out := make([]Result, 0)
+	for _, action := range c.Actions {
+		if !action.Enabled || action.EventType != zabbix.EventSourceTrigger {
+			continue
+		}
+
+		e := &evaluator{event: &event, suppressed: suppressed, loc: c.location(), parents: parents}
+		if !e.action(&action) {
+			continue
+		}
+
+		result := Result{
+			Action:      action,
+			Unsupported: e.unsupported,
+			Suppressed:  suppressed,
+		}
+		result.Steps, result.Repeats = steps(&action, &event)
+		out = append(out, result)
+	}
+
+	return out
+}
+
+// steps returns the escalation steps of the given Action with operations
+// whose conditions are met by the given Event, and whether the last step
+// repeats.
+func steps(action *zabbix.Action, event *Event) ([]Step, bool) {
+	last := 0
+	repeats := false
+	for _, op := range action.Operations {
+		from := op.StepFrom
+		if from < 1 {
+			from = 1
+		}
+		if from > last {
+			last = from
+		}
+		if op.StepTo > last {
+			last = op.StepTo
+		}
+	}
+
+	out := make([]Step, 0)
+	var start time.Duration
+	for n := 1; n <= last; n++ {
+		step := Step{Number: n, Start: start}
+		duration := time.Duration(0)
+		for _, op := range action.Operations {
+			from := op.StepFrom
+			if from < 1 {
+				from = 1
+			}
+			if n < from || (op.StepTo != 0 && n > op.StepTo) {
+				continue
+			}
+
+			if n == last && op.StepTo == 0 {
+				repeats = true
+			}
+
+			// the step lasts as long as its shortest operation
+			d := time.Duration(op.StepDuration) * time.Second
+			if d > 0 && (duration == 0 || d < duration) {
+				duration = d
+			}
+
+			if !operationConditions(&op, event) {
+				continue
+			}
+
+			step.Operations = append(step.Operations, op)
+			step.Recipients = appendRecipients(step.Recipients, &op)
+		}
+
+		if duration == 0 {
+			duration = time.Duration(action.StepDuration) * time.Second
+		}
+		start += duration
+
+		if len(step.Operations) > 0 {
+			out = append(out, step)
+		}
+	}
+
+	return out, repeats
+}
+
+// appendRecipients appends the recipients of the given operation which are not
+// already present.
+func appendRecipients(a []Recipient, op *zabbix.ActionOperation) []Recipient {
+	if op.OperationType != zabbix.ActionOperationTypeSendMessage {
+		return a
+	}
+
+	mediaTypeID := ""
+	if op.Message != nil {
+		mediaTypeID = op.Message.MediaTypeID
+	}
+
+	add := func(r Recipient) {
+		for _, v := range a {
+			if v == r {
+				return
+			}
+		}
+		a = append(a, r)
+	}
+
+	for _, id := range op.UserGroupIDs {
+		add(Recipient{UserGroupID: id, MediaTypeID: mediaTypeID})
+	}
+	for _, id := range op.UserIDs {
+		add(Recipient{UserID: id, MediaTypeID: mediaTypeID})
+	}
+
+	return a
+}
+
+// Fetch queries the Zabbix API for the given Triggers, the trigger Actions, the
+// Maintenances of their hosts and the parents of their host groups, and returns
+// a Simulator and an Event of each Trigger at the given time.
+//
+// An error is returned if a transport, parsing or API error occurs.
+func Fetch(session *zabbix.Session, triggerIDs []string, at time.Time) (*Simulator, []Event, error) {
+	triggers, err := session.GetTriggers(zabbix.TriggerGetParams{
+		TriggerIDs:   triggerIDs,
+		SelectHosts:  zabbix.SelectFields{"hostid", "host", "name"},
+		SelectGroups: zabbix.SelectFields{"groupid", "name"},
+		SelectTags:   zabbix.SelectExtendedOutput,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hostIDs := make([]string, 0)
+	groupIDs := make([]string, 0)
+	parentNames := make([]string, 0)
+	for _, trigger := range triggers {
+		for _, host := range trigger.Hosts {
+			hostIDs = append(hostIDs, host.HostID)
+		}
+		for _, group := range trigger.Groups {
+			groupIDs = append(groupIDs, group.GroupID)
+			parentNames = append(parentNames, parentGroupNames(group.Name)...)
+		}
+	}
+
+	var parents []zabbix.Hostgroup
+	if len(parentNames) > 0 {
+		parents, err = session.GetHostgroups(zabbix.HostgroupGetParams{
+			GetParameters: zabbix.GetParameters{
+				Filter: map[string]interface{}{"name": parentNames},
+			},
+		})
+		if err != nil && err != zabbix.ErrNotFound {
+			return nil, nil, err
+		}
+	}
+
+	// host tags are event tags as well
+	hosts, err := session.GetHosts(zabbix.HostGetParams{
+		HostIDs:    hostIDs,
+		SelectTags: zabbix.SelectExtendedOutput,
+	})
+	if err != nil && err != zabbix.ErrNotFound {
+		return nil, nil, err
+	}
+
+	hostTags := make(map[string][]zabbix.HostTag)
+	for _, host := range hosts {
+		hostTags[host.HostID] = host.Tags
+	}
+
+	events := make([]Event, len(triggers))
+	for i, trigger := range triggers {
+		for j := range trigger.Hosts {
+			trigger.Hosts[j].Tags = hostTags[trigger.Hosts[j].HostID]
+		}
+
+		events[i] = Event{Trigger: trigger, Time: at}
+		if events[i].TemplateIDs, err = templateIDs(session, &trigger); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	actions, err := session.GetActions(zabbix.ActionGetParams{
+		GetParameters: zabbix.GetParameters{
+			Filter: map[string]interface{}{"eventsource": zabbix.EventSourceTrigger},
+		},
+		SelectFilter:     zabbix.SelectExtendedOutput,
+		SelectOperations: zabbix.SelectExtendedOutput,
+	})
+	if err != nil && err != zabbix.ErrNotFound {
+		return nil, nil, err
+	}
+
+	maintenances, err := session.GetHostMaintenances(hostIDs, groupIDs)
+	if err != nil && err != zabbix.ErrNotFound {
+		return nil, nil, err
+	}
+
+	return &Simulator{
+		Actions:      actions,
+		Maintenances: maintenances,
+		Hostgroups:   parents,
+	}, events, nil
+}
+
+// templateIDs returns the IDs of the templates the given Trigger is inherited
+// from, by following its parent Triggers.
+func templateIDs(session *zabbix.Session, trigger *zabbix.Trigger) ([]string, error) {
+	out := make([]string, 0)
+	parentID := trigger.TemplateID
+	for parentID != "" && parentID != "0" {
+		parents, err := session.GetTriggers(zabbix.TriggerGetParams{
+			TriggerIDs:  []string{parentID},
+			SelectHosts: zabbix.SelectFields{"hostid"},
+		})
+		if err == zabbix.ErrNotFound {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, host := range parents[0].Hosts {
+			out = append(out, host.HostID)
+		}
+		parentID = parents[0].TemplateID
+	}
+
+	sort.Strings(out)
+	return out, nil
+}
@@ -0,0 +1,237 @@
+package coverage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cavaliercoder/go-zabbix"
+)
+
+func testSimulator() (*Simulator, Event) {
+	// a Monday at 10:00
+	at := time.Date(2021, time.March, 1, 10, 0, 0, 0, time.UTC)
+
+	event := Event{
+		Trigger: zabbix.Trigger{
+			TriggerID:   "13491",
+			Description: "High CPU load on web01",
+			Severity:    zabbix.TriggerSeverityHigh,
+			Hosts:       []zabbix.Host{{HostID: "101", Tags: []zabbix.HostTag{{Tag: "env", Value: "production"}}}},
+			Groups:      []zabbix.Hostgroup{{GroupID: "2"}},
+			Tags:        []zabbix.TriggerTag{{Name: "service", Value: "web"}},
+		},
+		TemplateIDs: []string{"10001"},
+		Time:        at,
+	}
+
+	condition := func(conditionType, operator int, value, value2, formulaID string) zabbix.ActionCondition {
+		return zabbix.ActionCondition{ConditionType: conditionType, Operator: operator, Value: value, Value2: value2, FormulaID: formulaID}
+	}
+
+	sim := &Simulator{
+		Actions: []zabbix.Action{
+			{
+				ActionID:     "1",
+				Name:         "Production on-call",
+				Enabled:      true,
+				StepDuration: 3600,
+				Conditions: []zabbix.ActionCondition{
+					condition(zabbix.ActionConditionTypeHostGroup, zabbix.ActionConditionOperatorEqual, "2", "", "A"),
+					condition(zabbix.ActionConditionTypeHostGroup, zabbix.ActionConditionOperatorEqual, "3", "", "B"),
+					condition(zabbix.ActionConditionTypeTriggerSeverity, zabbix.ActionConditionOperatorGreaterOrEqual, "4", "", "C"),
+					condition(zabbix.ActionConditionTypeEventTagValue, zabbix.ActionConditionOperatorEqual, "production", "env", "D"),
+					condition(zabbix.ActionConditionTypeProblemSuppressed, zabbix.ActionConditionOperatorNo, "", "", "E"),
+				},
+				Operations: []zabbix.ActionOperation{
+					{
+						OperationType: zabbix.ActionOperationTypeSendMessage,
+						StepFrom:      1,
+						StepTo:        2,
+						Message:       &zabbix.ActionMessage{DefaultMessage: true, MediaTypeID: "1"},
+						UserGroupIDs:  []string{"8"},
+					},
+					{
+						OperationType: zabbix.ActionOperationTypeSendMessage,
+						StepFrom:      2,
+						StepDuration:  600,
+						Message:       &zabbix.ActionMessage{DefaultMessage: true},
+						UserIDs:       []string{"2"},
+						Conditions: []zabbix.ActionOperationCondition{
+							{ConditionType: zabbix.ActionConditionTypeEventAcknowledged, Value: "0"},
+						},
+					},
+				},
+			},
+			{
+				ActionID:       "2",
+				Name:           "Office hours",
+				Enabled:        true,
+				EvaluationType: zabbix.ActionEvalTypeCustom,
+				Formula:        "A and not (B or C)",
+				Conditions: []zabbix.ActionCondition{
+					condition(zabbix.ActionConditionTypeTimePeriod, zabbix.ActionConditionOperatorIn, "1-5,09:00-18:00", "", "A"),
+					condition(zabbix.ActionConditionTypeEventName, zabbix.ActionConditionOperatorContains, "disk", "", "B"),
+					condition(zabbix.ActionConditionTypeHostTemplate, zabbix.ActionConditionOperatorEqual, "10002", "", "C"),
+				},
+			},
+			{
+				ActionID:       "3",
+				Name:           "Other services",
+				Enabled:        true,
+				EvaluationType: zabbix.ActionEvalTypeAnd,
+				Conditions: []zabbix.ActionCondition{
+					condition(zabbix.ActionConditionTypeEventTag, zabbix.ActionConditionOperatorEqual, "service", "", ""),
+					condition(zabbix.ActionConditionTypeEventTagValue, zabbix.ActionConditionOperatorNotEqual, "web", "service", ""),
+				},
+			},
+			{
+				ActionID: "4",
+				Name:     "Disabled",
+			},
+			{
+				ActionID: "5",
+				Name:     "Applications",
+				Enabled:  true,
+				Conditions: []zabbix.ActionCondition{
+					condition(zabbix.ActionConditionTypeApplication, zabbix.ActionConditionOperatorEqual, "Web", "", ""),
+				},
+			},
+		},
+		Location: time.UTC,
+	}
+
+	return sim, event
+}
+
+func TestSimulate(t *testing.T) {
+	sim, event := testSimulator()
+	results := sim.Simulate(event)
+
+	if len(results) != 3 || results[0].Action.ActionID != "1" || results[1].Action.ActionID != "2" || results[2].Action.ActionID != "5" {
+		t.Fatalf("Unexpected matching actions: %+v", results)
+	}
+
+	if len(results[2].Unsupported) != 1 {
+		t.Errorf("Expected 1 unsupported condition, got %+v", results[2].Unsupported)
+	}
+
+	steps := results[0].Steps
+	if len(steps) != 2 || !results[0].Repeats {
+		t.Fatalf("Unexpected escalation steps: %+v", steps)
+	}
+
+	if steps[0].Start != 0 || len(steps[0].Recipients) != 1 || steps[0].Recipients[0] != (Recipient{UserGroupID: "8", MediaTypeID: "1"}) {
+		t.Errorf("Unexpected first step: %+v", steps[0])
+	}
+
+	if steps[1].Start != time.Hour || len(steps[1].Recipients) != 2 || steps[1].Recipients[1].UserID != "2" {
+		t.Errorf("Unexpected second step: %+v", steps[1])
+	}
+
+	// operations for unacknowledged problems are skipped
+	event.Acknowledged = true
+	if results := sim.Simulate(event); len(results[0].Steps[1].Recipients) != 1 {
+		t.Errorf("Unexpected recipients of acknowledged problem: %+v", results[0].Steps[1].Recipients)
+	}
+
+	// outside office hours
+	event.Time = event.Time.Add(10 * time.Hour)
+	if results := sim.Simulate(event); len(results) != 2 {
+		t.Errorf("Expected 2 matching actions outside office hours, got %d", len(results))
+	}
+}
+
+func TestSimulateMaintenance(t *testing.T) {
+	sim, event := testSimulator()
+	sim.Maintenances = []zabbix.Maintenance{
+		{
+			Groups:      []zabbix.Hostgroup{{GroupID: "2"}},
+			ActiveSince: event.Time.Add(-time.Hour),
+			ActiveTill:  event.Time.Add(time.Hour),
+			Timeperiods: []zabbix.Timeperiods{
+				{TimeperiodType: zabbix.MaintenancePeriodOnce, StartDate: event.Time.Add(-time.Hour).Unix(), Period: 7200},
+			},
+		},
+	}
+
+	if !sim.Suppressed(event) {
+		t.Fatal("Expected event to be suppressed")
+	}
+
+	results := sim.Simulate(event)
+	if len(results) != 2 || results[0].Action.ActionID != "2" || !results[0].Suppressed {
+		t.Errorf("Unexpected matching actions in maintenance: %+v", results)
+	}
+}
+
+func TestSimulateNestedHostgroups(t *testing.T) {
+	sim, event := testSimulator()
+	event.Trigger.Groups = []zabbix.Hostgroup{{GroupID: "4", Name: "Production/Web/Frontend"}}
+
+	// without parent host groups, only direct host groups match
+	if results := sim.Simulate(event); len(results) > 0 && results[0].Action.ActionID == "1" {
+		t.Errorf("Unexpected match of nested host group without parents: %+v", results)
+	}
+
+	sim.Hostgroups = []zabbix.Hostgroup{{GroupID: "2", Name: "Production"}}
+	if results := sim.Simulate(event); len(results) == 0 || results[0].Action.ActionID != "1" {
+		t.Errorf("Expected nested host group to match its parent, got %+v", results)
+	}
+
+	names := parentGroupNames("Production/Web/Frontend")
+	if len(names) != 2 || names[0] != "Production/Web" || names[1] != "Production" {
+		t.Errorf("Unexpected parent host group names: %v", names)
+	}
+}
+
+func TestInTimePeriod(t *testing.T) {
+	sunday := time.Date(2021, time.March, 7, 12, 30, 0, 0, time.UTC)
+	tests := []struct {
+		Period string
+		Time   time.Time
+		In     bool
+	}{
+		{"1-5,09:00-18:00", sunday, false},
+		{"1-5,09:00-18:00;6-7,12:00-13:00", sunday, true},
+		{"7,00:00-24:00", sunday, true},
+		{"7,12:30-13:00", sunday, true},
+		{"7,12:00-12:30", sunday, false},
+	}
+
+	for _, test := range tests {
+		in, err := inTimePeriod(test.Period, test.Time)
+		if err != nil {
+			t.Errorf("Error evaluating %s: %v", test.Period, err)
+		} else if in != test.In {
+			t.Errorf("Expected %s to be %v, got %v", test.Period, test.In, in)
+		}
+	}
+
+	if _, err := inTimePeriod("{$OFFICE_HOURS}", sunday); err == nil {
+		t.Error("Expected an error for a user macro")
+	}
+}
+
+func TestEvalFormula(t *testing.T) {
+	labels := map[string]bool{"A": true, "B": false, "C": true}
+	tests := map[string]bool{
+		"A and B":                false,
+		"A or B":                 true,
+		"not B and C":            true,
+		"A and (B or not C)":     false,
+		"(A and B) or (A and C)": true,
+	}
+
+	for formula, expect := range tests {
+		v, err := evalFormula(formula, labels)
+		if err != nil || v != expect {
+			t.Errorf("Expected %s to be %v, got %v (%v)", formula, expect, v, err)
+		}
+	}
+
+	for _, formula := range []string{"A and", "(A or B", "A D", "X"} {
+		if _, err := evalFormula(formula, labels); err == nil {
+			t.Errorf("Expected an error for %s", formula)
+		}
+	}
+}
@@ -0,0 +1,422 @@
+/*
+Package delivery audits the delivery of Zabbix notification messages.
+
+An Auditor summarises the Alerts sent in a period by media type, recipient and
+Action, groups identical errors and flags recipients with repeated failures:
+
+	till := time.Now()
+	auditor, err := delivery.Fetch(session, delivery.Query{
+		From: till.Add(-24 * time.Hour),
+		Till: till,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	report := auditor.Audit(till.Add(-24*time.Hour), till)
+	for _, r := range report.Failing {
+		fmt.Println(r.Name, r.Failed, r.LastError)
+	}
+*/
+package delivery
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cavaliercoder/go-zabbix"
+)
+
+// DefaultThreshold is the number of failed messages at which a recipient is
+// flagged if Auditor.Threshold is not set.
+var DefaultThreshold = 3
+
+// Stats are the delivery outcomes of a set of messages.
+type Stats struct {
+	// Sent is the number of messages sent successfully.
+	Sent int
+
+	// Failed is the number of messages which failed to send.
+	Failed int
+
+	// Pending is the number of messages which are not sent yet.
+	Pending int
+}
+
+// Total returns the number of messages.
+func (c *Stats) Total() int {
+	return c.Sent + c.Failed + c.Pending
+}
+
+// FailureRate returns the percentage of messages which failed of those which
+// were either sent or failed, or zero if there were none.
+func (c *Stats) FailureRate() float64 {
+	if c.Sent+c.Failed == 0 {
+		return 0
+	}
+
+	return 100 * float64(c.Failed) / float64(c.Sent+c.Failed)
+}
+
+// add counts the outcome of the given Alert.
+func (c *Stats) add(alert *zabbix.Alert) {
+	switch alert.Status {
+	case zabbix.AlertMessageStatusSent:
+		c.Sent++
+	case zabbix.AlertMessageStatusFailed:
+		c.Failed++
+	default:
+		c.Pending++
+	}
+}
+
+// Group is the delivery outcomes of the messages of a media type, recipient or
+// Action.
+type Group struct {
+	Stats
+
+	// ID is the ID of the media type or Action, or the address of the
+	// recipient.
+	ID string
+
+	// Name is the name of the media type or Action, or the username of the
+	// recipient if known. Name is empty if it is not known.
+	Name string
+}
+
+// Recipient is the delivery outcomes of the messages sent to a recipient.
+type Recipient struct {
+	Group
+
+	// Streak is the number of consecutive failed messages up to the last
+	// message which was sent or failed.
+	Streak int
+
+	// LastFailure is the time of the last failed message.
+	LastFailure time.Time
+
+	// LastError is the error text of the last failed message.
+	LastError string
+}
+
+// Error is a failure reason shared by one or more failed messages.
+type Error struct {
+	// Text is the error text of the messages.
+	Text string
+
+	// Count is the number of messages which failed with the error.
+	Count int
+
+	// First and Last are the times of the first and last messages which failed
+	// with the error.
+	First time.Time
+	Last  time.Time
+
+	// MediaTypeIDs are the IDs of the media types of the messages.
+	MediaTypeIDs []string
+
+	// Recipients are the addresses of the recipients of the messages.
+	Recipients []string
+}
+
+// Report is the delivery outcomes of the messages sent in a period.
+type Report struct {
+	From time.Time
+	Till time.Time
+
+	// Total is the delivery outcomes of all messages.
+	Total Stats
+
+	// MediaTypes, Recipients and Actions are the delivery outcomes of each
+	// media type, recipient and Action in order of their ID.
+	MediaTypes []Group
+	Recipients []Recipient
+	Actions    []Group
+
+	// Errors are the failure reasons of failed messages in descending order of
+	// their count.
+	Errors []Error
+
+	// Failing are the Recipients with at least as many failed messages as the
+	// threshold of the Auditor, in descending order of their failures.
+	Failing []Recipient
+}
+
+// Auditor computes delivery Reports.
+type Auditor struct {
+	// Alerts are the Alerts to audit. Alerts which are not messages are
+	// ignored. Names of media types and recipients are only known if the
+	// Alerts were queried with AlertGetParams.SelectMediaTypes and
+	// SelectUsers.
+	Alerts []zabbix.Alert
+
+	// Actions are the Actions of the Alerts, used for their names.
+	Actions []zabbix.Action
+
+	// Threshold is the number of failed messages at which a recipient is
+	// flagged as failing. If zero, DefaultThreshold is used.
+	Threshold int
+}
+
+// Query selects the Alerts fetched by Fetch.
+type Query struct {
+	// MediaTypeIDs filters Alerts to messages sent with the given media types.
+	MediaTypeIDs []string
+
+	// ActionIDs filters Alerts to messages sent by the given Actions.
+	ActionIDs []string
+
+	// UserIDs filters Alerts to messages sent to the given users.
+	UserIDs []string
+
+	// From and Till select Alerts generated in the given time range.
+	From time.Time
+	Till time.Time
+}
+
+// Fetch queries the Zabbix API for the Alerts generated in the time range of
+// the given Query, with their media types, users and Actions.
+//
+// An error is returned if a transport, parsing or API error occurs.
+func Fetch(session *zabbix.Session, q Query) (*Auditor, error) {
+	if q.From.IsZero() || q.Till.IsZero() {
+		return nil, errors.New("A time range is required")
+	}
+
+	mediaTypeFields, userFields, err := selectFields(session)
+	if err != nil {
+		return nil, err
+	}
+
+	alerts, err := session.GetAlerts(zabbix.AlertGetParams{
+		MediaTypeIDs:     q.MediaTypeIDs,
+		ActionIDs:        q.ActionIDs,
+		UserIDs:          q.UserIDs,
+		MinTime:          q.From.Unix(),
+		MaxTime:          q.Till.Unix(),
+		SelectMediaTypes: mediaTypeFields,
+		SelectUsers:      userFields,
+	})
+	if err == zabbix.ErrNotFound {
+		return &Auditor{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	actionIDs := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, alert := range alerts {
+		if alert.ActionID != "" && !seen[alert.ActionID] {
+			seen[alert.ActionID] = true
+			actionIDs = append(actionIDs, alert.ActionID)
+		}
+	}
+
+	auditor := &Auditor{Alerts: alerts}
+	if len(actionIDs) == 0 {
+		return auditor, nil
+	}
+
+	auditor.Actions, err = session.GetActions(zabbix.ActionGetParams{ActionIDs: actionIDs})
+	if err != nil && err != zabbix.ErrNotFound {
+		return nil, err
+	}
+
+	return auditor, nil
+}
+
+// selectFields returns the media type and user fields of Alerts to select
+// from the connected Zabbix API version, which rejects unknown fields.
+func selectFields(session *zabbix.Session) (mediaTypes, users zabbix.SelectFields, err error) {
+	mediaTypes = zabbix.SelectFields{"mediatypeid", "name", "type", "status"}
+	users = zabbix.SelectFields{"userid", "username", "name", "surname"}
+
+	// media types are named by their description before v4.4
+	named, err := session.VersionAtLeast(4, 4)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !named {
+		mediaTypes[1] = "description"
+	}
+
+	// usernames are named alias before v5.4
+	renamed, err := session.VersionAtLeast(5, 4)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !renamed {
+		users[1] = "alias"
+	}
+
+	return mediaTypes, users, nil
+}
+
+// alertLess orders Alerts by time and ID.
+func alertLess(a, b *zabbix.Alert) bool {
+	if !a.Timestamp.Equal(b.Timestamp) {
+		return a.Timestamp.Before(b.Timestamp)
+	}
+
+	return idLess(a.AlertID, b.AlertID)
+}
+
+// idLess orders numeric IDs.
+func idLess(a, b string) bool {
+	if len(a) != len(b) {
+		return len(a) < len(b)
+	}
+
+	return a < b
+}
+
+// appendUnique appends s to the given strings if it is not empty and not
+// already present.
+func appendUnique(a []string, s string) []string {
+	if s == "" {
+		return a
+	}
+
+	for _, v := range a {
+		if v == s {
+			return a
+		}
+	}
+
+	return append(a, s)
+}
+
+// Audit computes the delivery outcomes of the messages generated in the given
+// period.
+func (c *Auditor) Audit(from, till time.Time) *Report {
+	threshold := c.Threshold
+	if threshold <= 0 {
+		threshold = DefaultThreshold
+	}
+
+	alerts := make([]*zabbix.Alert, 0, len(c.Alerts))
+	for i := range c.Alerts {
+		alert := &c.Alerts[i]
+		if alert.AlertType != zabbix.AlertTypeMessage || alert.Timestamp.Before(from) || alert.Timestamp.After(till) {
+			continue
+		}
+
+		alerts = append(alerts, alert)
+	}
+	sort.SliceStable(alerts, func(i, j int) bool { return alertLess(alerts[i], alerts[j]) })
+
+	actionNames := make(map[string]string)
+	for _, action := range c.Actions {
+		actionNames[action.ActionID] = action.Name
+	}
+
+	mediaTypes := make(map[string]*Group)
+	recipients := make(map[string]*Recipient)
+	actions := make(map[string]*Group)
+	errs := make(map[string]*Error)
+	report := &Report{From: from, Till: till}
+
+	for _, alert := range alerts {
+		report.Total.add(alert)
+
+		mediaType, ok := mediaTypes[alert.MediaTypeID]
+		if !ok {
+			mediaType = &Group{ID: alert.MediaTypeID}
+			mediaTypes[alert.MediaTypeID] = mediaType
+		}
+		for _, m := range alert.MediaTypes {
+			if m.MediaTypeID == alert.MediaTypeID {
+				mediaType.Name = m.Name
+			}
+		}
+		mediaType.add(alert)
+
+		recipient, ok := recipients[alert.Recipient]
+		if !ok {
+			recipient = &Recipient{Group: Group{ID: alert.Recipient}}
+			recipients[alert.Recipient] = recipient
+		}
+		for _, u := range alert.Users {
+			if u.UserID == alert.UserID {
+				recipient.Name = u.Username
+			}
+		}
+		recipient.add(alert)
+
+		action, ok := actions[alert.ActionID]
+		if !ok {
+			action = &Group{ID: alert.ActionID, Name: actionNames[alert.ActionID]}
+			actions[alert.ActionID] = action
+		}
+		action.add(alert)
+
+		switch alert.Status {
+		case zabbix.AlertMessageStatusSent:
+			recipient.Streak = 0
+
+		case zabbix.AlertMessageStatusFailed:
+			text := strings.TrimSpace(alert.ErrorText)
+			recipient.Streak++
+			recipient.LastFailure = alert.Timestamp
+			recipient.LastError = text
+
+			e, ok := errs[text]
+			if !ok {
+				e = &Error{Text: text, First: alert.Timestamp}
+				errs[text] = e
+			}
+			e.Count++
+			e.Last = alert.Timestamp
+			e.MediaTypeIDs = appendUnique(e.MediaTypeIDs, alert.MediaTypeID)
+			e.Recipients = appendUnique(e.Recipients, alert.Recipient)
+		}
+	}
+
+	report.MediaTypes = groups(mediaTypes)
+	report.Actions = groups(actions)
+
+	report.Recipients = make([]Recipient, 0, len(recipients))
+	report.Failing = make([]Recipient, 0)
+	for _, r := range recipients {
+		report.Recipients = append(report.Recipients, *r)
+		if r.Failed >= threshold {
+			report.Failing = append(report.Failing, *r)
+		}
+	}
+	sort.Slice(report.Recipients, func(i, j int) bool {
+		return report.Recipients[i].ID < report.Recipients[j].ID
+	})
+	sort.Slice(report.Failing, func(i, j int) bool {
+		if report.Failing[i].Failed != report.Failing[j].Failed {
+			return report.Failing[i].Failed > report.Failing[j].Failed
+		}
+		return report.Failing[i].ID < report.Failing[j].ID
+	})
+
+	report.Errors = make([]Error, 0, len(errs))
+	for _, e := range errs {
+		report.Errors = append(report.Errors, *e)
+	}
+	sort.Slice(report.Errors, func(i, j int) bool {
+		if report.Errors[i].Count != report.Errors[j].Count {
+			return report.Errors[i].Count > report.Errors[j].Count
+		}
+		return report.Errors[i].Text < report.Errors[j].Text
+	})
+
+	return report
+}
+
+// groups returns the given Groups in order of their ID.
+func groups(m map[string]*Group) []Group {
+	out := make([]Group, 0, len(m))
+	for _, g := range m {
+		out = append(out, *g)
+	}
+	sort.Slice(out, func(i, j int) bool { return idLess(out[i].ID, out[j].ID) })
+
+	return out
+}
@@ -0,0 +1,156 @@
+package delivery
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cavaliercoder/go-zabbix"
+)
+
+func testAuditor() (*Auditor, time.Time, time.Time) {
+	from := time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC)
+	till := from.Add(24 * time.Hour)
+	at := func(hour int) time.Time { return from.Add(time.Duration(hour) * time.Hour) }
+
+	email := []zabbix.MediaType{{MediaTypeID: "1", Name: "Email"}}
+	sms := []zabbix.MediaType{{MediaTypeID: "3", Name: "SMS"}}
+	oncall := []zabbix.User{{UserID: "2", Username: "oncall"}}
+	alert := func(id string, hour int, mediaTypes []zabbix.MediaType, sendTo string, status int, text string) zabbix.Alert {
+		return zabbix.Alert{
+			AlertID:     id,
+			ActionID:    "7",
+			AlertType:   zabbix.AlertTypeMessage,
+			Timestamp:   at(hour),
+			MediaTypeID: mediaTypes[0].MediaTypeID,
+			MediaTypes:  mediaTypes,
+			Recipient:   sendTo,
+			Status:      status,
+			ErrorText:   text,
+			UserID:      "2",
+			Users:       oncall,
+		}
+	}
+
+	auditor := &Auditor{
+		Alerts: []zabbix.Alert{
+			alert("1", 1, email, "ops@example.com", zabbix.AlertMessageStatusSent, ""),
+			alert("2", 1, sms, "+15550100", zabbix.AlertMessageStatusSent, ""),
+			alert("3", 2, sms, "+15550100", zabbix.AlertMessageStatusFailed, "gateway timeout"),
+			alert("4", 3, sms, "+15550100", zabbix.AlertMessageStatusFailed, "gateway timeout "),
+			alert("5", 4, sms, "+15550100", zabbix.AlertMessageStatusFailed, "invalid number"),
+			alert("6", 5, email, "ops@example.com", zabbix.AlertMessageStatusNotSent, ""),
+			// outside the period
+			alert("7", 30, sms, "+15550100", zabbix.AlertMessageStatusFailed, "gateway timeout"),
+			// not a message
+			{AlertID: "8", AlertType: zabbix.AlertTypeRemoteCommand, Timestamp: at(6)},
+		},
+		Actions: []zabbix.Action{{ActionID: "7", Name: "Notify on-call"}},
+	}
+
+	return auditor, from, till
+}
+
+func TestAudit(t *testing.T) {
+	auditor, from, till := testAuditor()
+	report := auditor.Audit(from, till)
+
+	if report.Total != (Stats{Sent: 2, Failed: 3, Pending: 1}) {
+		t.Errorf("Unexpected total: %+v", report.Total)
+	}
+
+	if len(report.MediaTypes) != 2 || report.MediaTypes[1].Name != "SMS" || report.MediaTypes[1].FailureRate() != 75 {
+		t.Errorf("Unexpected media types: %+v", report.MediaTypes)
+	}
+
+	if len(report.Actions) != 1 || report.Actions[0].Name != "Notify on-call" || report.Actions[0].Total() != 6 {
+		t.Errorf("Unexpected actions: %+v", report.Actions)
+	}
+
+	// identical errors are grouped regardless of surrounding space
+	if len(report.Errors) != 2 || report.Errors[0].Text != "gateway timeout" || report.Errors[0].Count != 2 {
+		t.Errorf("Unexpected errors: %+v", report.Errors)
+	}
+
+	if len(report.Failing) != 1 {
+		t.Fatalf("Expected 1 failing recipient, got %d", len(report.Failing))
+	}
+
+	r := report.Failing[0]
+	if r.ID != "+15550100" || r.Name != "oncall" || r.Failed != 3 || r.Streak != 3 || r.LastError != "invalid number" {
+		t.Errorf("Unexpected failing recipient: %+v", r)
+	}
+
+	auditor.Threshold = 4
+	if report := auditor.Audit(from, till); len(report.Failing) != 0 {
+		t.Errorf("Expected no failing recipients, got %+v", report.Failing)
+	}
+}
+
+func TestReport(t *testing.T) {
+	auditor, from, till := testAuditor()
+	report := auditor.Audit(from, till)
+
+	var buf bytes.Buffer
+	if err := report.WriteText(&buf); err != nil {
+		t.Fatalf("Error writing text: %v", err)
+	}
+
+	for _, s := range []string{"6 messages: 2 sent, 3 failed, 1 pending", "SMS (3)", "75.0%", "Failing recipients", "gateway timeout"} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("Expected %q in text report:\n%s", s, buf.String())
+		}
+	}
+}
+
+func TestFetchSelectFields(t *testing.T) {
+	var params struct {
+		SelectMediaTypes []string `json:"selectMediatypes"`
+		SelectUsers      []string `json:"selectUsers"`
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     uint64          `json:"id"`
+			Params json.RawMessage `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Error decoding request: %v", err)
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			t.Errorf("Error decoding request parameters: %v", err)
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","result":[],"id":%d}`, req.ID)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		Version    string
+		MediaTypes string
+		Users      string
+	}{
+		{"4.0.0", "mediatypeid,description,type,status", "userid,alias,name,surname"},
+		{"5.0.0", "mediatypeid,name,type,status", "userid,alias,name,surname"},
+		{"6.0.0", "mediatypeid,name,type,status", "userid,username,name,surname"},
+	}
+
+	till := time.Now()
+	for _, test := range tests {
+		session := &zabbix.Session{URL: srv.URL, APIVersion: test.Version}
+		if _, err := Fetch(session, Query{From: till.Add(-time.Hour), Till: till}); err != nil {
+			t.Fatalf("Error fetching Alerts for v%s: %v", test.Version, err)
+		}
+
+		if s := strings.Join(params.SelectMediaTypes, ","); s != test.MediaTypes {
+			t.Errorf("Expected media type fields %s for v%s, got %s", test.MediaTypes, test.Version, s)
+		}
+		if s := strings.Join(params.SelectUsers, ","); s != test.Users {
+			t.Errorf("Expected user fields %s for v%s, got %s", test.Users, test.Version, s)
+		}
+	}
+}
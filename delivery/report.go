@@ -0,0 +1,83 @@
+package delivery
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// formatRate formats a failure rate percentage with one decimal.
+func formatRate(v float64) string {
+	return strconv.FormatFloat(v, 'f', 1, 64)
+}
+
+// label returns the name of a Group followed by its ID, or only its ID if the
+// name is not known.
+func label(g *Group) string {
+	if g.Name == "" || g.Name == g.ID {
+		return g.ID
+	}
+
+	return fmt.Sprintf("%s (%s)", g.Name, g.ID)
+}
+
+// WriteText writes the Report as aligned text tables of media types, Actions,
+// recipients, errors and failing recipients.
+func (c *Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "Delivery from %s till %s\n", c.From.Format(time.RFC3339), c.Till.Format(time.RFC3339))
+	fmt.Fprintf(tw, "%d messages: %d sent, %d failed, %d pending\n", c.Total.Total(), c.Total.Sent, c.Total.Failed, c.Total.Pending)
+
+	recipients := make([]Group, len(c.Recipients))
+	for i, r := range c.Recipients {
+		recipients[i] = r.Group
+	}
+
+	for _, section := range []struct {
+		Title  string
+		Groups []Group
+	}{
+		{"Media types", c.MediaTypes},
+		{"Actions", c.Actions},
+		{"Recipients", recipients},
+	} {
+		if len(section.Groups) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(tw, "\n%s\n", section.Title)
+		fmt.Fprintln(tw, "NAME\tSENT\tFAILED\tPENDING\tFAILURE RATE")
+		for i := range section.Groups {
+			g := &section.Groups[i]
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%s%%\n", label(g), g.Sent, g.Failed, g.Pending, formatRate(g.FailureRate()))
+		}
+	}
+
+	if len(c.Errors) > 0 {
+		fmt.Fprintf(tw, "\nErrors\n")
+		fmt.Fprintln(tw, "COUNT\tFIRST\tLAST\tRECIPIENTS\tERROR")
+		for _, e := range c.Errors {
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n",
+				e.Count,
+				e.First.Format(time.RFC3339),
+				e.Last.Format(time.RFC3339),
+				strings.Join(e.Recipients, ", "),
+				e.Text,
+			)
+		}
+	}
+
+	if len(c.Failing) > 0 {
+		fmt.Fprintf(tw, "\nFailing recipients\n")
+		fmt.Fprintln(tw, "NAME\tFAILED\tSTREAK\tLAST FAILURE\tLAST ERROR")
+		for i := range c.Failing {
+			r := &c.Failing[i]
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%s\n", label(&r.Group), r.Failed, r.Streak, r.LastFailure.Format(time.RFC3339), r.LastError)
+		}
+	}
+
+	return tw.Flush()
+}
@@ -1,10 +1,59 @@
 package zabbix
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 )
 
+// AcknowledgeAction is a bitmask of the actions of an Event update.
+type AcknowledgeAction int
+
+const (
+	// AcknowledgeActionClose closes the problem.
+	AcknowledgeActionClose AcknowledgeAction = 1 << iota
+
+	// AcknowledgeActionAcknowledge acknowledges the event.
+	AcknowledgeActionAcknowledge
+
+	// AcknowledgeActionMessage adds a message.
+	AcknowledgeActionMessage
+
+	// AcknowledgeActionSeverity changes the severity.
+	AcknowledgeActionSeverity
+
+	// AcknowledgeActionUnacknowledge removes the acknowledgement.
+	AcknowledgeActionUnacknowledge
+
+	// AcknowledgeActionSuppress suppresses the event. Requires Zabbix 6.2 or
+	// later.
+	AcknowledgeActionSuppress
+
+	// AcknowledgeActionUnsuppress removes the suppression. Requires Zabbix 6.2
+	// or later.
+	AcknowledgeActionUnsuppress
+
+	// AcknowledgeActionCause changes the event to a cause event. Requires
+	// Zabbix 6.4 or later.
+	AcknowledgeActionCause
+
+	// AcknowledgeActionSymptom changes the event to a symptom event of the
+	// event given by EventAcknowledgeParams.CauseEventID. Requires Zabbix 6.4
+	// or later.
+	AcknowledgeActionSymptom
+)
+
+// Has returns true if all of the given actions are set.
+func (c AcknowledgeAction) Has(action AcknowledgeAction) bool {
+	return c&action == action
+}
+
+// ErrPermissionDenied is returned when the user of a Session has no
+// permission to perform an operation on the referenced objects.
+var ErrPermissionDenied = errors.New("Permission denied")
+
 const (
 	// EventSourceTrigger indicates that an Event was created by a Trigger.
 	EventSourceTrigger = iota
@@ -131,6 +180,117 @@ type Event struct {
 	// query parameters that returned this Event and the Event Source is one of
 	// EventSourceTrigger or EventSourceDiscoveryRule.
 	Hosts []Host
+
+	// Name is the resolved name of the Event.
+	Name string
+
+	// Severity is the current severity of the Event.
+	//
+	// Severity must be one of the TriggerSeverity constants.
+	Severity int
+
+	// RecoveryEventID is the ID of the recovery Event of a problem Event, or
+	// empty if the problem is unresolved.
+	RecoveryEventID string
+
+	// CorrelationEventID is the ID of the Event which closed a problem Event
+	// by a global correlation rule, or empty if there is none.
+	CorrelationEventID string
+
+	// CorrelationID is the ID of the correlation rule which generated a
+	// recovery Event.
+	CorrelationID string
+
+	// UserID is the ID of the user who closed the problem manually.
+	UserID string
+
+	// Suppressed indicates if the Event is suppressed by a maintenance.
+	Suppressed bool
+
+	// OpData is the operational data of the Event.
+	OpData string
+
+	// Tags are the tags of the Event.
+	//
+	// Tags is only populated if EventGetParams.SelectTags is given in the
+	// query parameters that returned this Event.
+	Tags []EventTag
+
+	// Acknowledges are the updates of the Event, in reverse chronological
+	// order.
+	//
+	// Acknowledges is only populated if EventGetParams.SelectAcknowledgements
+	// is given in the query parameters that returned this Event.
+	Acknowledges []Acknowledgement
+
+	// Alerts are the Alerts generated by the Event, in reverse chronological
+	// order.
+	//
+	// Alerts is only populated if EventGetParams.SelectAlerts is given in the
+	// query parameters that returned this Event.
+	Alerts []Alert
+
+	// RelatedTrigger is the Trigger which caused the Event if ObjectType is
+	// EventObjectTypeTrigger.
+	//
+	// RelatedTrigger is only populated if EventGetParams.SelectRelatedObject
+	// is given in the query parameters that returned this Event.
+	RelatedTrigger *Trigger
+
+	// RelatedItem is the Item which caused the Event if ObjectType is
+	// EventObjectTypeItem or EventObjectTypeLLDRule.
+	//
+	// RelatedItem is only populated if EventGetParams.SelectRelatedObject is
+	// given in the query parameters that returned this Event.
+	RelatedItem *Item
+}
+
+// EventTag is a tag of an Event or Problem.
+type EventTag struct {
+	// Tag is the tag name.
+	Tag string `json:"tag"`
+
+	// Value is the tag value.
+	Value string `json:"value"`
+}
+
+// Acknowledgement is an update of an Event or Problem by a user, such as an
+// acknowledgement, a message or a change of severity.
+type Acknowledgement struct {
+	// AcknowledgeID is the ID of the update.
+	AcknowledgeID string
+
+	// UserID is the ID of the user who updated the Event.
+	UserID string
+
+	// Username, Name and Surname describe the user who updated the Event.
+	//
+	// They are only populated for updates returned by GetEvents.
+	Username string
+	Name     string
+	Surname  string
+
+	// EventID is the ID of the updated Event.
+	EventID string
+
+	// Timestamp is the time at which the Event was updated.
+	Timestamp time.Time
+
+	// Message is the message given by the user.
+	Message string
+
+	// Action is a bitmask of the actions performed by the update.
+	Action AcknowledgeAction
+
+	// OldSeverity is the severity of the Event before the update.
+	OldSeverity int
+
+	// NewSeverity is the severity of the Event after the update.
+	NewSeverity int
+
+	// SuppressUntil is the time until which the Event was suppressed by the
+	// update, or the zero time.
+	SuppressUntil time.Time
 }
 
 // EventGetParams is query params for event.get call
@@ -193,11 +353,25 @@ type EventGetParams struct {
 
 	// SelectAlerts causes Alerts generated by each Event to be attached in the
 	// search results.
+	//
+	// The parameter is named select_alerts before Zabbix 7.0 and selectAlerts
+	// since, which is handled by GetEvents.
 	SelectAlerts SelectQuery `json:"select_alerts,omitempty"`
 
 	// SelectAcknowledgements causes Acknowledgments for each Event to be
 	// attached in the search results in reverse chronological order.
+	//
+	// The parameter is named select_acknowledges before Zabbix 7.0 and
+	// selectAcknowledges since, which is handled by GetEvents.
 	SelectAcknowledgements SelectQuery `json:"select_acknowledges,omitempty"`
+
+	// SelectTags causes the tags of each Event to be attached in the search
+	// results.
+	SelectTags SelectQuery `json:"selectTags,omitempty"`
+
+	// GroupCount causes a CountOutput query to return the number of Events
+	// grouped by the values of the fields given in OutputFields.
+	GroupCount bool `json:"groupCount,omitempty"`
 }
 
 // GetEvents queries the Zabbix API for Events matching the given search
@@ -206,8 +380,13 @@ type EventGetParams struct {
 // ErrEventNotFound is returned if the search result set is empty.
 // An error is returned if a transport, parsing or API error occurs.
 func (c *Session) GetEvents(params EventGetParams) ([]Event, error) {
+	query, err := c.eventQuery(params)
+	if err != nil {
+		return nil, err
+	}
+
 	events := make([]jEvent, 0)
-	err := c.Get("event.get", params, &events)
+	err = c.Get("event.get", query, &events)
 	if err != nil {
 		return nil, err
 	}
@@ -229,3 +408,173 @@ func (c *Session) GetEvents(params EventGetParams) ([]Event, error) {
 
 	return out, nil
 }
+
+// eventQuery returns the given EventGetParams with the parameter names of the
+// connected Zabbix API version.
+func (c *Session) eventQuery(params EventGetParams) (interface{}, error) {
+	if params.SelectAlerts == nil && params.SelectAcknowledgements == nil {
+		return params, nil
+	}
+
+	renamed, err := c.VersionAtLeast(7, 0)
+	if err != nil || !renamed {
+		return params, err
+	}
+
+	b, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+
+	query := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(b, &query); err != nil {
+		return nil, err
+	}
+
+	for old, name := range map[string]string{
+		"select_alerts":       "selectAlerts",
+		"select_acknowledges": "selectAcknowledges",
+	} {
+		if v, ok := query[old]; ok {
+			delete(query, old)
+			query[name] = v
+		}
+	}
+
+	return query, nil
+}
+
+// CountEvents queries the Zabbix API for the number of Events matching the
+// given search parameters.
+//
+// An error is returned if a transport, parsing or API error occurs.
+func (c *Session) CountEvents(params EventGetParams) (int, error) {
+	params.CountOutput = true
+	params.GroupCount = false
+	return c.count("event.get", params)
+}
+
+// CountEventsBy queries the Zabbix API for the number of Events matching the
+// given search parameters, grouped by the values of the given fields.
+//
+// An error is returned if a transport, parsing or API error occurs.
+func (c *Session) CountEventsBy(params EventGetParams, fields ...string) ([]GroupCount, error) {
+	params.CountOutput = true
+	params.GroupCount = true
+	params.OutputFields = SelectFields(fields)
+	return c.groupCount("event.get", params)
+}
+
+// CountEventsBySeverity queries the Zabbix API for the number of Events
+// matching the given search parameters for each severity. The returned map is
+// keyed by the TriggerSeverity constants.
+//
+// An error is returned if a transport, parsing or API error occurs.
+func (c *Session) CountEventsBySeverity(params EventGetParams) (map[int]int, error) {
+	groups, err := c.CountEventsBy(params, "severity")
+	if err != nil {
+		return nil, err
+	}
+
+	return countBySeverity(groups, "severity")
+}
+
+// EventAcknowledgeParams represent the parameters for an `event.acknowledge`
+// API call.
+//
+// See: https://www.zabbix.com/documentation/current/manual/api/reference/event/acknowledge
+type EventAcknowledgeParams struct {
+	// EventIDs are the IDs of the events to update.
+	EventIDs []string `json:"eventids"`
+
+	// Action is a bitmask of the actions to perform.
+	Action AcknowledgeAction `json:"action"`
+
+	// Message is required with AcknowledgeActionMessage.
+	Message string `json:"message,omitempty"`
+
+	// Severity is required with AcknowledgeActionSeverity and must be one of
+	// the TriggerSeverity constants.
+	Severity *int `json:"severity,omitempty"`
+
+	// SuppressUntil is the time until which the events are suppressed with
+	// AcknowledgeActionSuppress, as a Unix timestamp. Zero suppresses the
+	// events indefinitely.
+	SuppressUntil int64 `json:"suppress_until,omitempty"`
+
+	// CauseEventID is required with AcknowledgeActionSymptom.
+	CauseEventID string `json:"cause_eventid,omitempty"`
+}
+
+// AcknowledgeEvents updates events by acknowledging, closing, commenting on,
+// changing the severity of or suppressing them, as given by the actions in
+// params. Returns the IDs of the updated events.
+//
+// ErrUnsupportedVersion is returned if an action is not supported by the
+// connected Zabbix API. An error wrapping ErrPermissionDenied is returned if
+// the user has no permission to update the events, or the events do not
+// exist.
+// An error is returned if a transport, parsing or API error occurs.
+//
+// Zabbix API docs: https://www.zabbix.com/documentation/current/manual/api/reference/event/acknowledge
+func (c *Session) AcknowledgeEvents(params EventAcknowledgeParams) ([]string, error) {
+	if len(params.EventIDs) == 0 {
+		return nil, errors.New("No events given")
+	}
+
+	if params.Action&(AcknowledgeActionSuppress|AcknowledgeActionUnsuppress) != 0 {
+		if err := c.requireVersion(6, 2); err != nil {
+			return nil, err
+		}
+	}
+
+	if params.Action&(AcknowledgeActionCause|AcknowledgeActionSymptom) != 0 {
+		if err := c.requireVersion(6, 4); err != nil {
+			return nil, err
+		}
+	}
+
+	if params.Action.Has(AcknowledgeActionMessage) && params.Message == "" {
+		return nil, errors.New("A message is required to add a message")
+	}
+
+	if params.Action.Has(AcknowledgeActionSeverity) && params.Severity == nil {
+		return nil, errors.New("A severity is required to change the severity")
+	}
+
+	if params.Action.Has(AcknowledgeActionSymptom) && params.CauseEventID == "" {
+		return nil, errors.New("A cause event is required to change events to symptoms")
+	}
+
+	resp, err := c.Do(NewRequest("event.acknowledge", params))
+	if err != nil {
+		if resp != nil && isPermissionError(&resp.Error) {
+			return nil, fmt.Errorf("%w: %s", ErrPermissionDenied, resp.Error.Data)
+		}
+		return nil, err
+	}
+
+	// event IDs are returned as numbers by some versions
+	var body struct {
+		EventIDs []json.Number `json:"eventids"`
+	}
+
+	if err := resp.Bind(&body); err != nil {
+		return nil, err
+	}
+
+	eventIDs := make([]string, len(body.EventIDs))
+	for i, id := range body.EventIDs {
+		eventIDs[i] = id.String()
+	}
+
+	return eventIDs, nil
+}
+
+// isPermissionError returns true if the given API error was caused by missing
+// permissions.
+func isPermissionError(err *APIError) bool {
+	data := strings.ToLower(err.Data)
+	return strings.Contains(data, "no permissions") ||
+		strings.Contains(data, "do not have permission")
+}
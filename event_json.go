@@ -1,6 +1,7 @@
 package zabbix
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"time"
@@ -19,6 +20,19 @@ type jEvent struct {
 	Value        string `json:"value"`
 	ValueChanged string `json:"value_changed"`
 	Hosts        jHosts `json:"hosts"`
+
+	Name               string            `json:"name"`
+	Severity           string            `json:"severity"`
+	RecoveryEventID    string            `json:"r_eventid"`
+	CorrelationEventID string            `json:"c_eventid"`
+	CorrelationID      string            `json:"correlationid"`
+	UserID             string            `json:"userid"`
+	Suppressed         string            `json:"suppressed"`
+	OpData             string            `json:"opdata"`
+	Tags               []EventTag        `json:"tags"`
+	Acknowledges       jAcknowledgements `json:"acknowledges"`
+	Alerts             []jAlert          `json:"alerts"`
+	RelatedObject      json.RawMessage   `json:"relatedObject"`
 }
 
 // Event returns a native Go Event struct mapped from the given JSON Event data.
@@ -68,5 +82,156 @@ func (c *jEvent) Event() (*Event, error) {
 		return nil, err
 	}
 
+	event.Name = c.Name
+	event.CorrelationID = c.CorrelationID
+	event.UserID = c.UserID
+	event.Suppressed = (c.Suppressed == "1")
+	event.OpData = c.OpData
+	event.Tags = c.Tags
+
+	if c.RecoveryEventID != "0" {
+		event.RecoveryEventID = c.RecoveryEventID
+	}
+
+	if c.CorrelationEventID != "0" {
+		event.CorrelationEventID = c.CorrelationEventID
+	}
+
+	event.Severity, err = atoi(c.Severity)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing Event Severity: %v", err)
+	}
+
+	// map acknowledgements
+	event.Acknowledges, err = c.Acknowledges.Acknowledgements()
+	if err != nil {
+		return nil, err
+	}
+
+	// map alerts
+	if c.Alerts != nil {
+		event.Alerts = make([]Alert, len(c.Alerts))
+		for i, jalert := range c.Alerts {
+			alert, err := jalert.Alert()
+			if err != nil {
+				return nil, fmt.Errorf("Error mapping Event Alert %d: %v", i, err)
+			}
+			event.Alerts[i] = *alert
+		}
+	}
+
+	// map the related object, which is an empty array if there is none
+	if len(c.RelatedObject) > 0 && c.RelatedObject[0] == '{' {
+		switch event.ObjectType {
+		case EventObjectTypeTrigger:
+			var jtrigger jTrigger
+			if err := json.Unmarshal(c.RelatedObject, &jtrigger); err != nil {
+				return nil, fmt.Errorf("Error parsing Event related Trigger: %v", err)
+			}
+
+			event.RelatedTrigger, err = jtrigger.Trigger()
+			if err != nil {
+				return nil, err
+			}
+
+		case EventObjectTypeItem, EventObjectTypeLLDRule:
+			var jitem jItem
+			if err := json.Unmarshal(c.RelatedObject, &jitem); err != nil {
+				return nil, fmt.Errorf("Error parsing Event related Item: %v", err)
+			}
+
+			event.RelatedItem, err = jitem.Item()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	return event, nil
 }
+
+// jAcknowledgement is a private map for the Zabbix API Acknowledge object.
+type jAcknowledgement struct {
+	AcknowledgeID string `json:"acknowledgeid"`
+	UserID        string `json:"userid"`
+	Alias         string `json:"alias"`
+	Username      string `json:"username"`
+	Name          string `json:"name"`
+	Surname       string `json:"surname"`
+	EventID       string `json:"eventid"`
+	Clock         string `json:"clock"`
+	Message       string `json:"message"`
+	Action        string `json:"action"`
+	OldSeverity   string `json:"old_severity"`
+	NewSeverity   string `json:"new_severity"`
+	SuppressUntil string `json:"suppress_until"`
+}
+
+// Acknowledgement returns a native Go Acknowledgement struct mapped from the
+// given JSON Acknowledge data.
+func (c *jAcknowledgement) Acknowledgement() (*Acknowledgement, error) {
+	var err error
+	ack := &Acknowledgement{
+		AcknowledgeID: c.AcknowledgeID,
+		UserID:        c.UserID,
+		Username:      c.Username,
+		Name:          c.Name,
+		Surname:       c.Surname,
+		EventID:       c.EventID,
+		Message:       c.Message,
+	}
+
+	// the username is named alias before Zabbix 5.4
+	if ack.Username == "" {
+		ack.Username = c.Alias
+	}
+
+	ack.Timestamp, err = unixTime(c.Clock, "")
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing Acknowledgement timestamp: %v", err)
+	}
+
+	action, err := atoi(c.Action)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing Acknowledgement action: %v", err)
+	}
+	ack.Action = AcknowledgeAction(action)
+
+	if ack.OldSeverity, err = atoi(c.OldSeverity); err != nil {
+		return nil, fmt.Errorf("Error parsing Acknowledgement old severity: %v", err)
+	}
+
+	if ack.NewSeverity, err = atoi(c.NewSeverity); err != nil {
+		return nil, fmt.Errorf("Error parsing Acknowledgement new severity: %v", err)
+	}
+
+	ack.SuppressUntil, err = unixTime(c.SuppressUntil, "")
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing Acknowledgement suppression time: %v", err)
+	}
+
+	return ack, nil
+}
+
+// jAcknowledgements is a slice of jAcknowledgement structs.
+type jAcknowledgements []jAcknowledgement
+
+// Acknowledgements returns a native Go slice of Acknowledgements mapped from
+// the given JSON Acknowledges data.
+func (c jAcknowledgements) Acknowledgements() ([]Acknowledgement, error) {
+	if c == nil {
+		return nil, nil
+	}
+
+	out := make([]Acknowledgement, len(c))
+	for i, jack := range c {
+		ack, err := jack.Acknowledgement()
+		if err != nil {
+			return nil, fmt.Errorf("Error unmarshalling Acknowledgement %d in JSON data: %v", i, err)
+		}
+
+		out[i] = *ack
+	}
+
+	return out, nil
+}
@@ -1,9 +1,108 @@
 package zabbix
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
 )
 
+func TestEventMapping(t *testing.T) {
+	data := `{
+		"eventid": "500",
+		"source": "0",
+		"object": "0",
+		"objectid": "100",
+		"clock": "1600000000",
+		"ns": "0",
+		"value": "1",
+		"acknowledged": "1",
+		"name": "High load",
+		"severity": "4",
+		"r_eventid": "501",
+		"c_eventid": "0",
+		"tags": [{"tag": "service", "value": "web"}],
+		"acknowledges": [{"acknowledgeid": "1", "userid": "2", "alias": "oncall", "clock": "1600000060", "message": "on it", "action": "6"}],
+		"alerts": [{"alertid": "3", "mediatypeid": "1", "clock": "1600000001", "status": "1", "sendto": "ops@example.com"}],
+		"relatedObject": {"triggerid": "100", "description": "High load", "priority": "4"}
+	}`
+
+	var jevent jEvent
+	if err := json.Unmarshal([]byte(data), &jevent); err != nil {
+		t.Fatalf("Error unmarshalling Event: %v", err)
+	}
+
+	event, err := jevent.Event()
+	if err != nil {
+		t.Fatalf("Error mapping Event: %v", err)
+	}
+
+	if event.Name != "High load" || event.Severity != TriggerSeverityHigh || event.RecoveryEventID != "501" || event.CorrelationEventID != "" {
+		t.Errorf("Unexpected Event fields: %+v", event)
+	}
+
+	if len(event.Tags) != 1 || event.Tags[0].Tag != "service" {
+		t.Errorf("Unexpected Event tags: %+v", event.Tags)
+	}
+
+	if len(event.Acknowledges) != 1 || event.Acknowledges[0].Username != "oncall" || !event.Acknowledges[0].Action.Has(AcknowledgeActionAcknowledge) {
+		t.Errorf("Unexpected Event acknowledges: %+v", event.Acknowledges)
+	}
+
+	if len(event.Alerts) != 1 || event.Alerts[0].Recipient != "ops@example.com" {
+		t.Errorf("Unexpected Event alerts: %+v", event.Alerts)
+	}
+
+	if event.RelatedTrigger == nil || event.RelatedTrigger.TriggerID != "100" || event.RelatedItem != nil {
+		t.Errorf("Unexpected Event related object: %+v", event.RelatedTrigger)
+	}
+
+	// events without a related object return an empty array
+	jevent.RelatedObject = json.RawMessage("[]")
+	if event, err = jevent.Event(); err != nil || event.RelatedTrigger != nil {
+		t.Errorf("Unexpected Event related object: %+v (%v)", event, err)
+	}
+}
+
+func TestEventQuery(t *testing.T) {
+	params := EventGetParams{
+		SelectAlerts:           SelectExtendedOutput,
+		SelectAcknowledgements: SelectExtendedOutput,
+	}
+
+	tests := []struct {
+		Version string
+		Expect  []string
+		Reject  []string
+	}{
+		{"6.4.0", []string{`"select_alerts"`, `"select_acknowledges"`}, []string{`"selectAlerts"`, `"selectAcknowledges"`}},
+		{"7.0.0", []string{`"selectAlerts"`, `"selectAcknowledges"`}, []string{`"select_alerts"`, `"select_acknowledges"`}},
+	}
+
+	for _, test := range tests {
+		session := &Session{APIVersion: test.Version}
+		query, err := session.eventQuery(params)
+		if err != nil {
+			t.Fatalf("Error mapping Event query for v%s: %v", test.Version, err)
+		}
+
+		b, err := json.Marshal(query)
+		if err != nil {
+			t.Fatalf("Error marshalling Event query for v%s: %v", test.Version, err)
+		}
+
+		for _, name := range test.Expect {
+			if !strings.Contains(string(b), name) {
+				t.Errorf("Expected %s in Event query for v%s, got %s", name, test.Version, b)
+			}
+		}
+		for _, name := range test.Reject {
+			if strings.Contains(string(b), name) {
+				t.Errorf("Unexpected %s in Event query for v%s, got %s", name, test.Version, b)
+			}
+		}
+	}
+}
+
 func TestEvents(t *testing.T) {
 	session := GetTestSession(t)
 
@@ -35,3 +134,45 @@ func TestEvents(t *testing.T) {
 
 	t.Logf("Validated %d Events", len(events))
 }
+
+func TestAcknowledgeEventsValidation(t *testing.T) {
+	session := &Session{APIVersion: "6.0.0"}
+
+	tests := []struct {
+		Params EventAcknowledgeParams
+		Err    error
+	}{
+		{EventAcknowledgeParams{EventIDs: []string{"1"}, Action: AcknowledgeActionSuppress}, ErrUnsupportedVersion},
+		{EventAcknowledgeParams{EventIDs: []string{"1"}, Action: AcknowledgeActionAcknowledge | AcknowledgeActionSymptom}, ErrUnsupportedVersion},
+		{EventAcknowledgeParams{Action: AcknowledgeActionAcknowledge}, nil},
+		{EventAcknowledgeParams{EventIDs: []string{"1"}, Action: AcknowledgeActionMessage}, nil},
+		{EventAcknowledgeParams{EventIDs: []string{"1"}, Action: AcknowledgeActionSeverity | AcknowledgeActionMessage, Message: "raised"}, nil},
+	}
+
+	for i, test := range tests {
+		_, err := session.AcknowledgeEvents(test.Params)
+		if err == nil {
+			t.Errorf("Expected an error for test %d", i)
+			continue
+		}
+
+		if test.Err != nil && err != test.Err {
+			t.Errorf("Expected %v for test %d, got %v", test.Err, i, err)
+		}
+	}
+
+	action := AcknowledgeActionAcknowledge | AcknowledgeActionMessage
+	if !action.Has(AcknowledgeActionMessage) || action.Has(AcknowledgeActionClose) {
+		t.Errorf("Unexpected actions in bitmask %d", action)
+	}
+}
+
+func TestIsPermissionError(t *testing.T) {
+	if !isPermissionError(&APIError{Code: -32500, Data: "No permissions to referred object or it does not exist!"}) {
+		t.Error("Expected permission error")
+	}
+
+	if isPermissionError(&APIError{Code: -32602, Data: "Incorrect value for field \"message\"."}) {
+		t.Error("Unexpected permission error")
+	}
+}
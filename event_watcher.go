@@ -0,0 +1,500 @@
+package zabbix
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// minEventWatcherInterval is the minimum interval between polls of an
+// EventWatcher.
+const minEventWatcherInterval = time.Second
+
+// EventCursor is the position of an EventWatcher in the stream of Events.
+type EventCursor struct {
+	// LastEventID is the ID of the last delivered Event.
+	LastEventID string `json:"lastEventId"`
+
+	// Tracked are the unresolved problem Events which are watched for new
+	// acknowledgements, keyed by Event ID.
+	Tracked map[string]TrackedEvent `json:"tracked,omitempty"`
+}
+
+// TrackedEvent is a problem Event which is watched for new acknowledgements.
+type TrackedEvent struct {
+	// Clock is the Unix timestamp of the Event.
+	Clock int64 `json:"clock"`
+
+	// LastAcknowledgeID is the ID of the last delivered acknowledgement of
+	// the Event.
+	LastAcknowledgeID string `json:"lastAcknowledgeId,omitempty"`
+}
+
+// EventCursorStore persists the cursor of an EventWatcher, so that a watcher
+// resumes where it stopped after a restart.
+type EventCursorStore interface {
+	// LoadCursor returns the saved cursor, or nil if no cursor was saved.
+	LoadCursor() (*EventCursor, error)
+
+	// SaveCursor saves the given cursor.
+	SaveCursor(cursor *EventCursor) error
+}
+
+// EventCursorFileStore is an EventCursorStore which saves the cursor as JSON
+// in a file.
+type EventCursorFileStore struct {
+	filePath string
+}
+
+// NewEventCursorFileStore creates a new EventCursorFileStore which saves the
+// cursor in the given file.
+func NewEventCursorFileStore(filePath string) *EventCursorFileStore {
+	return &EventCursorFileStore{filePath: filePath}
+}
+
+// LoadCursor returns the saved cursor, or nil if the file does not exist.
+func (c *EventCursorFileStore) LoadCursor() (*EventCursor, error) {
+	b, err := ioutil.ReadFile(c.filePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cursor := &EventCursor{}
+	if err := json.Unmarshal(b, cursor); err != nil {
+		return nil, err
+	}
+
+	return cursor, nil
+}
+
+// SaveCursor saves the given cursor. The file is replaced atomically, so a
+// crash never leaves a partially written cursor.
+func (c *EventCursorFileStore) SaveCursor(cursor *EventCursor) error {
+	b, err := json.Marshal(cursor)
+	if err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(filepath.Dir(c.filePath), filepath.Base(c.filePath)+".tmp")
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+
+	return os.Rename(f.Name(), c.filePath)
+}
+
+// EventUpdate is an Event delivered by an EventWatcher.
+type EventUpdate struct {
+	// Event is the new or updated Event.
+	Event Event
+
+	// Acknowledges are the new acknowledgements of an Event which was
+	// delivered before, in chronological order. Acknowledges is empty for
+	// new Events.
+	Acknowledges []Acknowledgement
+}
+
+// IsNew returns true if the Event was not delivered before.
+func (c *EventUpdate) IsNew() bool {
+	return len(c.Acknowledges) == 0
+}
+
+// EventWatcher polls the Zabbix API for new Events and delivers them in order
+// of their ID.
+//
+// Unresolved problem Events are tracked after they are delivered, and
+// delivered again with their new acknowledgements when they are acknowledged,
+// until they are resolved or older than the tracking window.
+//
+// The Events returned must include the eventid, clock and r_eventid fields.
+type EventWatcher struct {
+	session    *Session
+	params     EventGetParams
+	interval   time.Duration
+	jitter     time.Duration
+	maxBackoff time.Duration
+	window     time.Duration
+	store      EventCursorStore
+	onError    func(error)
+
+	mu     sync.Mutex
+	cursor *EventCursor
+}
+
+// NewEventWatcher creates a new EventWatcher which polls for Events matching
+// the given search parameters.
+//
+// Without a saved cursor, the watcher starts with the first Event matching
+// the given parameters, so MinTime or MinEventID should be given to skip
+// older Events.
+func NewEventWatcher(session *Session, params EventGetParams) *EventWatcher {
+	return &EventWatcher{
+		session:    session,
+		params:     params,
+		interval:   30 * time.Second,
+		maxBackoff: 10 * time.Minute,
+		window:     24 * time.Hour,
+	}
+}
+
+// SetInterval sets the interval between polls. Default value is 30 seconds.
+// Intervals shorter than one second are raised to one second.
+func (c *EventWatcher) SetInterval(d time.Duration) *EventWatcher {
+	if d < minEventWatcherInterval {
+		d = minEventWatcherInterval
+	}
+	c.interval = d
+	return c
+}
+
+// SetJitter sets the maximum random delay added to the interval between
+// polls. Default value is zero.
+func (c *EventWatcher) SetJitter(d time.Duration) *EventWatcher {
+	c.jitter = d
+	return c
+}
+
+// SetMaxBackoff sets the maximum interval between polls after consecutive
+// errors, which double the interval. Default value is 10 minutes.
+func (c *EventWatcher) SetMaxBackoff(d time.Duration) *EventWatcher {
+	c.maxBackoff = d
+	return c
+}
+
+// SetTrackingWindow sets how long problem Events are tracked for new
+// acknowledgements. Default value is 24 hours. Zero disables tracking.
+func (c *EventWatcher) SetTrackingWindow(d time.Duration) *EventWatcher {
+	c.window = d
+	return c
+}
+
+// SetStore sets the store of the watcher cursor. Without a store, the cursor
+// is kept in memory only.
+func (c *EventWatcher) SetStore(store EventCursorStore) *EventWatcher {
+	c.store = store
+	return c
+}
+
+// SetErrorHandler sets a function which is called with each error of a poll.
+// Polls are retried after errors.
+func (c *EventWatcher) SetErrorHandler(fn func(error)) *EventWatcher {
+	c.onError = fn
+	return c
+}
+
+// Cursor returns a copy of the current cursor of the watcher. It is safe to
+// call Cursor while the watcher is running.
+func (c *EventWatcher) Cursor() EventCursor {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cursor == nil {
+		return EventCursor{}
+	}
+
+	return *copyCursor(c.cursor)
+}
+
+// copyCursor returns a deep copy of the given cursor.
+func copyCursor(cursor *EventCursor) *EventCursor {
+	out := &EventCursor{
+		LastEventID: cursor.LastEventID,
+		Tracked:     make(map[string]TrackedEvent, len(cursor.Tracked)),
+	}
+
+	for id, tracked := range cursor.Tracked {
+		out.Tracked[id] = tracked
+	}
+
+	return out
+}
+
+// idLess returns true if the numeric ID a is lesser than b.
+func idLess(a, b string) bool {
+	if len(a) != len(b) {
+		return len(a) < len(b)
+	}
+
+	return a < b
+}
+
+// newAcknowledges returns the acknowledgements with an ID greater than the
+// given ID, in chronological order.
+func newAcknowledges(acks []Acknowledgement, lastID string) []Acknowledgement {
+	out := make([]Acknowledgement, 0)
+	for _, ack := range acks {
+		if idLess(lastID, ack.AcknowledgeID) {
+			out = append(out, ack)
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return idLess(out[i].AcknowledgeID, out[j].AcknowledgeID)
+	})
+
+	return out
+}
+
+// lastAcknowledgeID returns the greatest ID of the given acknowledgements.
+func lastAcknowledgeID(acks []Acknowledgement) string {
+	last := ""
+	for _, ack := range acks {
+		if idLess(last, ack.AcknowledgeID) {
+			last = ack.AcknowledgeID
+		}
+	}
+
+	return last
+}
+
+// poll queries the Zabbix API for acknowledgements of tracked Events and new
+// Events, and returns them with the cursor after their delivery.
+func (c *EventWatcher) poll() ([]EventUpdate, *EventCursor, error) {
+	next, err := c.current()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	updates := make([]EventUpdate, 0)
+
+	// acknowledgements of tracked events
+	if len(next.Tracked) > 0 {
+		params := c.params
+		params.EventIDs = make([]string, 0, len(next.Tracked))
+		for id := range next.Tracked {
+			params.EventIDs = append(params.EventIDs, id)
+		}
+		params.MinEventID, params.MaxEventID = "", ""
+		params.MinTime, params.MaxTime = 0, 0
+		params.ResultLimit = 0
+		params.SortField = []string{"eventid"}
+		params.SortOrder = "ASC"
+		params.SelectAcknowledgements = SelectExtendedOutput
+
+		events, err := c.session.GetEvents(params)
+		if err != nil && err != ErrNotFound {
+			return nil, nil, err
+		}
+
+		found := make(map[string]bool, len(events))
+		for _, event := range events {
+			found[event.EventID] = true
+			tracked := next.Tracked[event.EventID]
+			acks := newAcknowledges(event.Acknowledges, tracked.LastAcknowledgeID)
+			if len(acks) > 0 {
+				updates = append(updates, EventUpdate{Event: event, Acknowledges: acks})
+				tracked.LastAcknowledgeID = acks[len(acks)-1].AcknowledgeID
+				next.Tracked[event.EventID] = tracked
+			}
+
+			if event.RecoveryEventID != "" {
+				delete(next.Tracked, event.EventID)
+			}
+		}
+
+		// events which were deleted or no longer match
+		for id := range next.Tracked {
+			if !found[id] {
+				delete(next.Tracked, id)
+			}
+		}
+	}
+
+	// new events
+	params := c.params
+	if next.LastEventID != "" {
+		id, err := strconv.ParseUint(next.LastEventID, 10, 64)
+		if err != nil {
+			return nil, nil, err
+		}
+		params.MinEventID = strconv.FormatUint(id+1, 10)
+	}
+	params.SortField = []string{"eventid"}
+	params.SortOrder = "ASC"
+	if c.window > 0 {
+		params.SelectAcknowledgements = SelectExtendedOutput
+	}
+
+	events, err := c.session.GetEvents(params)
+	if err != nil && err != ErrNotFound {
+		return nil, nil, err
+	}
+
+	for _, event := range events {
+		updates = append(updates, EventUpdate{Event: event})
+		next.LastEventID = event.EventID
+
+		if c.window > 0 &&
+			event.Source == EventSourceTrigger &&
+			event.Value == TriggerEventValueProblem &&
+			event.RecoveryEventID == "" {
+			next.Tracked[event.EventID] = TrackedEvent{
+				Clock:             event.Timestamp.Unix(),
+				LastAcknowledgeID: lastAcknowledgeID(event.Acknowledges),
+			}
+		}
+	}
+
+	// expire tracked events
+	expiry := time.Now().Add(-c.window).Unix()
+	for id, tracked := range next.Tracked {
+		if tracked.Clock < expiry {
+			delete(next.Tracked, id)
+		}
+	}
+
+	return updates, next, nil
+}
+
+// current returns a copy of the current cursor, which is loaded from the
+// store on first use.
+func (c *EventWatcher) current() (*EventCursor, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cursor == nil {
+		cursor := &EventCursor{}
+		if c.store != nil {
+			saved, err := c.store.LoadCursor()
+			if err != nil {
+				return nil, err
+			}
+			if saved != nil {
+				cursor = saved
+			}
+		}
+		c.cursor = cursor
+	}
+
+	return copyCursor(c.cursor), nil
+}
+
+// commit makes the given cursor current and saves it to the store.
+func (c *EventWatcher) commit(cursor *EventCursor) error {
+	c.mu.Lock()
+	c.cursor = cursor
+	c.mu.Unlock()
+
+	if c.store != nil {
+		return c.store.SaveCursor(cursor)
+	}
+
+	return nil
+}
+
+// Poll queries the Zabbix API once and returns new and updated Events. The
+// cursor is advanced and saved before Poll returns.
+//
+// An error is returned if a transport, parsing, API or store error occurs.
+func (c *EventWatcher) Poll() ([]EventUpdate, error) {
+	updates, next, err := c.poll()
+	if err != nil {
+		return nil, err
+	}
+
+	return updates, c.commit(next)
+}
+
+// delay returns the time to wait before the next poll after the given number
+// of consecutive errors.
+func (c *EventWatcher) delay(failures int) time.Duration {
+	d := c.interval
+	for i := 0; i < failures && d < c.maxBackoff; i++ {
+		d *= 2
+	}
+
+	if failures > 0 && d > c.maxBackoff {
+		d = c.maxBackoff
+	}
+
+	if c.jitter > 0 {
+		d += time.Duration(rand.Int63n(int64(c.jitter)))
+	}
+
+	return d
+}
+
+// Watch polls the Zabbix API until the given context is done, and calls fn
+// with each new and updated Event in order. The cursor is saved after each
+// successful poll of which all Events were delivered, so Events may be
+// delivered again after a restart if fn or the store fails.
+//
+// Watch returns the error of the context when it is done, or the first error
+// returned by fn or the store. Errors of polls are passed to the error handler
+// and retried with backoff.
+func (c *EventWatcher) Watch(ctx context.Context, fn func(EventUpdate) error) error {
+	failures := 0
+	for {
+		updates, next, err := c.poll()
+		if err != nil {
+			failures++
+			if c.onError != nil {
+				c.onError(err)
+			}
+		} else {
+			failures = 0
+			for _, update := range updates {
+				if err := fn(update); err != nil {
+					return err
+				}
+			}
+
+			if err := c.commit(next); err != nil {
+				return err
+			}
+		}
+
+		timer := time.NewTimer(c.delay(failures))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Events starts watching in a new goroutine and returns a channel of new and
+// updated Events, which is closed when the given context is done or a store
+// error occurs. Store errors are passed to the error handler.
+func (c *EventWatcher) Events(ctx context.Context) <-chan EventUpdate {
+	ch := make(chan EventUpdate)
+	go func() {
+		defer close(ch)
+		err := c.Watch(ctx, func(update EventUpdate) error {
+			select {
+			case ch <- update:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+
+		if err != nil && err != ctx.Err() && c.onError != nil {
+			c.onError(err)
+		}
+	}()
+
+	return ch
+}
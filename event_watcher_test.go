@@ -0,0 +1,255 @@
+package zabbix
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEventCursorFileStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-zabbix")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	store := NewEventCursorFileStore(filepath.Join(dir, "cursor.json"))
+	cursor, err := store.LoadCursor()
+	if err != nil || cursor != nil {
+		t.Fatalf("Expected no cursor, got %+v (%v)", cursor, err)
+	}
+
+	err = store.SaveCursor(&EventCursor{
+		LastEventID: "1234",
+		Tracked:     map[string]TrackedEvent{"1200": {Clock: 1600000000, LastAcknowledgeID: "7"}},
+	})
+	if err != nil {
+		t.Fatalf("Error saving cursor: %v", err)
+	}
+
+	cursor, err = store.LoadCursor()
+	if err != nil {
+		t.Fatalf("Error loading cursor: %v", err)
+	}
+
+	if cursor.LastEventID != "1234" || cursor.Tracked["1200"].LastAcknowledgeID != "7" {
+		t.Errorf("Unexpected cursor: %+v", cursor)
+	}
+}
+
+func TestNewAcknowledges(t *testing.T) {
+	acks := []Acknowledgement{
+		{AcknowledgeID: "12"},
+		{AcknowledgeID: "10"},
+		{AcknowledgeID: "9"},
+	}
+
+	out := newAcknowledges(acks, "9")
+	if len(out) != 2 || out[0].AcknowledgeID != "10" || out[1].AcknowledgeID != "12" {
+		t.Errorf("Unexpected new acknowledges: %+v", out)
+	}
+
+	if out := newAcknowledges(acks, ""); len(out) != 3 {
+		t.Errorf("Expected all acknowledges, got %+v", out)
+	}
+
+	if last := lastAcknowledgeID(acks); last != "12" {
+		t.Errorf("Expected last acknowledge ID 12, got %s", last)
+	}
+}
+
+func TestEventWatcherDelay(t *testing.T) {
+	c := NewEventWatcher(nil, EventGetParams{}).
+		SetInterval(time.Second).
+		SetMaxBackoff(10 * time.Second)
+
+	tests := []struct {
+		Failures int
+		Expect   time.Duration
+	}{
+		{0, time.Second},
+		{1, 2 * time.Second},
+		{3, 8 * time.Second},
+		{4, 10 * time.Second},
+		{100, 10 * time.Second},
+	}
+
+	for _, test := range tests {
+		if d := c.delay(test.Failures); d != test.Expect {
+			t.Errorf("Expected delay of %v after %d failures, got %v", test.Expect, test.Failures, d)
+		}
+	}
+
+	c.SetJitter(time.Second)
+	for i := 0; i < 10; i++ {
+		if d := c.delay(0); d < time.Second || d >= 2*time.Second {
+			t.Errorf("Delay with jitter out of range: %v", d)
+		}
+	}
+}
+
+func TestEventWatcherPoll(t *testing.T) {
+	now := time.Now().Unix()
+	var mu sync.Mutex
+	events := map[string]map[string]interface{}{}
+	addEvent := func(id string, clock int64, value string) {
+		events[id] = map[string]interface{}{
+			"eventid":      id,
+			"clock":        strconv.FormatInt(clock, 10),
+			"ns":           "0",
+			"object":       "0",
+			"objectid":     "100",
+			"source":       "0",
+			"value":        value,
+			"r_eventid":    "0",
+			"acknowledges": []map[string]string{},
+		}
+	}
+
+	// fake event.get which filters by eventids and eventid_from
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     uint64 `json:"id"`
+			Params struct {
+				EventIDs   []string `json:"eventids"`
+				MinEventID string   `json:"eventid_from"`
+			} `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Error decoding request: %v", err)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		filter := make(map[string]bool, len(req.Params.EventIDs))
+		for _, id := range req.Params.EventIDs {
+			filter[id] = true
+		}
+
+		ids := make([]string, 0)
+		for id := range events {
+			if len(filter) > 0 && !filter[id] {
+				continue
+			}
+			if req.Params.MinEventID != "" && idLess(id, req.Params.MinEventID) {
+				continue
+			}
+			ids = append(ids, id)
+		}
+		sort.Slice(ids, func(i, j int) bool { return idLess(ids[i], ids[j]) })
+
+		result := make([]map[string]interface{}, len(ids))
+		for i, id := range ids {
+			result[i] = events[id]
+		}
+		b, _ := json.Marshal(result)
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","result":%s,"id":%d}`, b, req.ID)
+	}))
+	defer srv.Close()
+
+	session := &Session{URL: srv.URL, APIVersion: "6.0.0", client: srv.Client()}
+	c := NewEventWatcher(session, EventGetParams{}).SetTrackingWindow(time.Hour)
+
+	expect := func(updates []EventUpdate, err error, ids ...string) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("Error polling: %v", err)
+		}
+		if len(updates) != len(ids) {
+			t.Fatalf("Expected %d updates, got %+v", len(ids), updates)
+		}
+		for i, id := range ids {
+			if updates[i].Event.EventID != id {
+				t.Errorf("Expected update %d for Event %s, got %s", i, id, updates[i].Event.EventID)
+			}
+		}
+	}
+	expectTracked := func(ids ...string) {
+		t.Helper()
+		cursor := c.Cursor()
+		if len(cursor.Tracked) != len(ids) {
+			t.Fatalf("Expected %d tracked Events, got %+v", len(ids), cursor.Tracked)
+		}
+		for _, id := range ids {
+			if _, ok := cursor.Tracked[id]; !ok {
+				t.Errorf("Expected Event %s to be tracked, got %+v", id, cursor.Tracked)
+			}
+		}
+	}
+
+	// new problem, expired problem and OK event
+	mu.Lock()
+	addEvent("1", now, "1")
+	addEvent("2", now-2*3600, "1")
+	addEvent("3", now, "0")
+	mu.Unlock()
+
+	updates, err := c.Poll()
+	expect(updates, err, "1", "2", "3")
+	expectTracked("1")
+	if cursor := c.Cursor(); cursor.LastEventID != "3" {
+		t.Errorf("Expected last Event ID 3, got %s", cursor.LastEventID)
+	}
+
+	// nothing changed
+	updates, err = c.Poll()
+	expect(updates, err)
+
+	// tracked problem is acknowledged and a new problem occurs
+	mu.Lock()
+	events["1"]["acknowledges"] = []map[string]string{{
+		"acknowledgeid": "20",
+		"eventid":       "1",
+		"clock":         strconv.FormatInt(now, 10),
+		"message":       "on it",
+	}}
+	addEvent("4", now, "1")
+	mu.Unlock()
+
+	updates, err = c.Poll()
+	expect(updates, err, "1", "4")
+	if updates[0].IsNew() || updates[0].Acknowledges[0].Message != "on it" {
+		t.Errorf("Expected acknowledgement update, got %+v", updates[0])
+	}
+	if !updates[1].IsNew() {
+		t.Errorf("Expected new Event update, got %+v", updates[1])
+	}
+	expectTracked("1", "4")
+	if tracked := c.Cursor().Tracked["1"]; tracked.LastAcknowledgeID != "20" {
+		t.Errorf("Expected last acknowledge ID 20, got %s", tracked.LastAcknowledgeID)
+	}
+
+	// acknowledgement is not delivered twice
+	updates, err = c.Poll()
+	expect(updates, err)
+
+	// tracked problem is resolved and the other one is deleted
+	mu.Lock()
+	events["1"]["r_eventid"] = "5"
+	delete(events, "4")
+	addEvent("5", now, "0")
+	mu.Unlock()
+
+	updates, err = c.Poll()
+	expect(updates, err, "5")
+	expectTracked()
+	if cursor := c.Cursor(); cursor.LastEventID != "5" {
+		t.Errorf("Expected last Event ID 5, got %s", cursor.LastEventID)
+	}
+}
+
+func TestEventWatcherMinInterval(t *testing.T) {
+	c := NewEventWatcher(nil, EventGetParams{}).SetInterval(0)
+	if d := c.delay(0); d != time.Second {
+		t.Errorf("Expected minimum interval of 1s, got %v", d)
+	}
+}
@@ -0,0 +1,104 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/cavaliercoder/go-zabbix"
+)
+
+// CSVWriter writes History or Trends as CSV with a header row.
+//
+// History columns are itemid, host, key, tags, clock, ns and value. Trend
+// columns are itemid, host, key, tags, clock, num, value_min, value_avg and
+// value_max. Tags are written as name=value pairs separated by semicolons.
+type CSVWriter struct {
+	writer
+	csv *csv.Writer
+}
+
+// NewCSVWriter returns a new CSVWriter which writes to w and labels values
+// with the given Labeler. If labeler is nil, values are labelled with their
+// Item ID only.
+func NewCSVWriter(w io.Writer, labeler Labeler) *CSVWriter {
+	c := &CSVWriter{writer: newWriter(w, labeler)}
+	c.csv = csv.NewWriter(c.w)
+	return c
+}
+
+// csvTags formats the tags of the given Labels as a CSV field.
+func csvTags(labels *Labels) string {
+	tags := make([]string, len(labels.Tags))
+	for i, tag := range labels.Tags {
+		tags[i] = tag.Tag + "=" + tag.Value
+	}
+
+	return strings.Join(tags, ";")
+}
+
+// WriteHistory writes a single History.
+func (c *CSVWriter) WriteHistory(history zabbix.History) error {
+	labels, first, err := c.begin(kindHistory, history.ItemID)
+	if err != nil {
+		return err
+	}
+
+	if first {
+		if err := c.csv.Write([]string{"itemid", "host", "key", "tags", "clock", "ns", "value"}); err != nil {
+			return err
+		}
+	}
+
+	return c.csv.Write([]string{
+		strconv.Itoa(history.ItemID),
+		labels.Host,
+		labels.Key,
+		csvTags(&labels),
+		strconv.Itoa(history.Clock),
+		strconv.Itoa(history.Ns),
+		history.Value,
+	})
+}
+
+// WriteTrend writes a single Trend.
+func (c *CSVWriter) WriteTrend(trend zabbix.Trend) error {
+	labels, first, err := c.begin(kindTrend, trend.ItemID)
+	if err != nil {
+		return err
+	}
+
+	if first {
+		if err := c.csv.Write([]string{"itemid", "host", "key", "tags", "clock", "num", "value_min", "value_avg", "value_max"}); err != nil {
+			return err
+		}
+	}
+
+	return c.csv.Write([]string{
+		strconv.Itoa(trend.ItemID),
+		labels.Host,
+		labels.Key,
+		csvTags(&labels),
+		strconv.Itoa(trend.Clock),
+		strconv.Itoa(trend.Num),
+		formatFloat(trend.ValueMin),
+		formatFloat(trend.ValueAvg),
+		formatFloat(trend.ValueMax),
+	})
+}
+
+// Close flushes all buffered data.
+func (c *CSVWriter) Close() error {
+	c.csv.Flush()
+	if err := c.csv.Error(); err != nil {
+		return err
+	}
+
+	return c.writer.Close()
+}
+
+// formatFloat formats a float in the shortest representation.
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,311 @@
+/*
+Package export writes Zabbix History and Trends in common time-series formats,
+so that data may be migrated from Zabbix into other systems.
+
+Writers are available for CSV, newline-delimited JSON, InfluxDB line protocol
+and Prometheus or OpenMetrics text. Each value is labelled with the host name,
+item key and item tags of its Item, which are resolved through the Zabbix API
+by a Resolver.
+
+Values are written as they are received, so exports may be streamed from the
+API without holding all values in memory:
+
+	w := export.NewInfluxWriter(os.Stdout, export.NewResolver(session))
+	if err := export.Histories(session, params, 10000, w); err != nil {
+		panic(err)
+	}
+
+	if err := w.Close(); err != nil {
+		panic(err)
+	}
+*/
+package export
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cavaliercoder/go-zabbix"
+)
+
+const (
+	// HistoryName is the measurement or metric name of exported History.
+	HistoryName = "zabbix_history"
+
+	// TrendName is the measurement or metric name of exported Trends.
+	TrendName = "zabbix_trend"
+)
+
+// ErrMixedKinds is returned if History and Trends are written to the same
+// Writer.
+var ErrMixedKinds = errors.New("History and Trends can not be written to the same Writer")
+
+// Writer writes History or Trends in a time-series format. A Writer may only
+// be used for one of History or Trends.
+type Writer interface {
+	// WriteHistory writes a single History.
+	WriteHistory(history zabbix.History) error
+
+	// WriteTrend writes a single Trend.
+	WriteTrend(trend zabbix.Trend) error
+
+	// Close writes any trailer required by the format and flushes all
+	// buffered data to the underlying io.Writer. Close does not close the
+	// underlying io.Writer.
+	Close() error
+}
+
+// Labels identify the Item of an exported value.
+type Labels struct {
+	// ItemID is the ID of the Item.
+	ItemID int
+
+	// Host is the technical name of the Host of the Item.
+	Host string
+
+	// Key is the Item key.
+	Key string
+
+	// Tags are the tags of the Item.
+	Tags []zabbix.ItemTag
+}
+
+// TagMap returns the Item tags as a map. Values of tags with the same name are
+// joined with a comma.
+func (c *Labels) TagMap() map[string]string {
+	out := make(map[string]string, len(c.Tags))
+	for _, tag := range c.Tags {
+		if v, ok := out[tag.Tag]; ok {
+			out[tag.Tag] = v + "," + tag.Value
+			continue
+		}
+		out[tag.Tag] = tag.Value
+	}
+
+	return out
+}
+
+// tagNames returns the names of the given tags in sorted order.
+func tagNames(tags map[string]string) []string {
+	out := make([]string, 0, len(tags))
+	for name := range tags {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+
+	return out
+}
+
+// Labeler returns the Labels of an Item.
+type Labeler interface {
+	Labels(itemID int) (Labels, error)
+}
+
+// StaticLabels is a Labeler for a fixed set of Items, keyed by Item ID. Items
+// which are not found are labelled with their Item ID only.
+type StaticLabels map[int]Labels
+
+// Labels returns the Labels of the given Item.
+func (c StaticLabels) Labels(itemID int) (Labels, error) {
+	if labels, ok := c[itemID]; ok {
+		return labels, nil
+	}
+
+	return Labels{ItemID: itemID}, nil
+}
+
+// Resolver is a Labeler which resolves the Labels of Items through the Zabbix
+// API. Labels are cached, so each Item and Host is only queried once.
+type Resolver struct {
+	session *zabbix.Session
+	labels  map[int]Labels
+	hosts   map[int]string
+}
+
+// NewResolver returns a new Resolver which queries the given Session.
+func NewResolver(session *zabbix.Session) *Resolver {
+	return &Resolver{
+		session: session,
+		labels:  make(map[int]Labels),
+		hosts:   make(map[int]string),
+	}
+}
+
+// Resolve queries the Zabbix API for the Labels of all given Items which are
+// not already cached. Calling Resolve with all Items of an export avoids a
+// query for each Item.
+//
+// An error is returned if a transport, parsing or API error occurs.
+func (c *Resolver) Resolve(itemIDs ...int) error {
+	ids := make([]string, 0, len(itemIDs))
+	for _, itemID := range itemIDs {
+		if _, ok := c.labels[itemID]; !ok {
+			ids = append(ids, strconv.Itoa(itemID))
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	items, err := c.session.GetItems(zabbix.ItemGetParams{
+		GetParameters: zabbix.GetParameters{
+			OutputFields: zabbix.SelectFields{"itemid", "hostid", "key_"},
+		},
+		ItemIDs:    ids,
+		WebItems:   true,
+		SelectTags: zabbix.SelectExtendedOutput,
+	})
+	if err != nil && err != zabbix.ErrNotFound {
+		return err
+	}
+
+	// query Hosts which are not already cached
+	hostIDs := make([]string, 0)
+	for _, item := range items {
+		if _, ok := c.hosts[item.HostID]; !ok {
+			hostIDs = append(hostIDs, strconv.Itoa(item.HostID))
+			c.hosts[item.HostID] = ""
+		}
+	}
+
+	if len(hostIDs) > 0 {
+		hosts, err := c.session.GetHosts(zabbix.HostGetParams{
+			GetParameters: zabbix.GetParameters{
+				OutputFields: zabbix.SelectFields{"hostid", "host"},
+			},
+			HostIDs: hostIDs,
+		})
+		if err != nil && err != zabbix.ErrNotFound {
+			return err
+		}
+
+		for _, host := range hosts {
+			hostID, err := strconv.Atoi(host.HostID)
+			if err != nil {
+				return err
+			}
+			c.hosts[hostID] = host.Hostname
+		}
+	}
+
+	for _, item := range items {
+		c.labels[item.ItemID] = Labels{
+			ItemID: item.ItemID,
+			Host:   c.hosts[item.HostID],
+			Key:    item.Key,
+			Tags:   item.Tags,
+		}
+	}
+
+	// cache Items which were not found, so they are not queried again
+	for _, itemID := range itemIDs {
+		if _, ok := c.labels[itemID]; !ok {
+			c.labels[itemID] = Labels{ItemID: itemID}
+		}
+	}
+
+	return nil
+}
+
+// Labels returns the Labels of the given Item, querying the Zabbix API if
+// they are not already cached.
+//
+// An error is returned if a transport, parsing or API error occurs.
+func (c *Resolver) Labels(itemID int) (Labels, error) {
+	if labels, ok := c.labels[itemID]; ok {
+		return labels, nil
+	}
+
+	if err := c.Resolve(itemID); err != nil {
+		return Labels{}, err
+	}
+
+	return c.labels[itemID], nil
+}
+
+const (
+	kindNone = iota
+	kindHistory
+	kindTrend
+)
+
+// writer implements the buffering, labelling and kind checks shared by all
+// Writers.
+type writer struct {
+	w       *bufio.Writer
+	labeler Labeler
+	kind    int
+}
+
+func newWriter(w io.Writer, labeler Labeler) writer {
+	if labeler == nil {
+		labeler = StaticLabels(nil)
+	}
+
+	return writer{w: bufio.NewWriter(w), labeler: labeler}
+}
+
+// begin checks that the Writer is only used for one kind of value and returns
+// the Labels of the given Item. first is true for the first value written.
+func (c *writer) begin(kind, itemID int) (labels Labels, first bool, err error) {
+	if c.kind != kindNone && c.kind != kind {
+		return Labels{}, false, ErrMixedKinds
+	}
+
+	first = c.kind == kindNone
+	c.kind = kind
+	labels, err = c.labeler.Labels(itemID)
+	return labels, first, err
+}
+
+// Close flushes all buffered data.
+func (c *writer) Close() error {
+	return c.w.Flush()
+}
+
+// historyTime returns the time of the given History.
+func historyTime(history *zabbix.History) time.Time {
+	return time.Unix(int64(history.Clock), int64(history.Ns))
+}
+
+// Histories queries the Zabbix API for Histories matching the given search
+// parameters in pages of the given size and writes each History to the given
+// Writer. The Writer is not closed.
+//
+// See zabbix.Session.StreamHistories.
+func Histories(session *zabbix.Session, params zabbix.HistoryGetParams, pageSize int, w Writer) error {
+	return session.StreamHistories(params, pageSize, w.WriteHistory)
+}
+
+// Trends queries the Zabbix API for Trends matching the given search
+// parameters in windows of the given duration and writes each Trend to the
+// given Writer. The Writer is not closed.
+//
+// See zabbix.Session.StreamTrends.
+func Trends(session *zabbix.Session, params zabbix.TrendGetParams, window time.Duration, w Writer) error {
+	return session.StreamTrends(params, window, w.WriteTrend)
+}
+
+// escape replaces each of the given characters in s with the character
+// preceded by a backslash.
+func escape(s, chars string) string {
+	if !strings.ContainsAny(s, chars) {
+		return s
+	}
+
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(chars, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,160 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cavaliercoder/go-zabbix"
+)
+
+var testLabels = StaticLabels{
+	1: {
+		ItemID: 1,
+		Host:   "web 01",
+		Key:    "system.cpu.load[all,avg1]",
+		Tags:   []zabbix.ItemTag{{Tag: "component", Value: "cpu"}},
+	},
+}
+
+var testHistory = []zabbix.History{
+	{ItemID: 1, Clock: 1500000000, Ns: 500000000, ValueType: zabbix.ItemValueTypeFloat, Value: "0.25"},
+	{ItemID: 2, Clock: 1500000060, ValueType: zabbix.ItemValueTypeText, Value: `say "hi"`},
+}
+
+func writeTestHistory(t *testing.T, w Writer, buf *bytes.Buffer) string {
+	for _, history := range testHistory {
+		if err := w.WriteHistory(history); err != nil {
+			t.Fatalf("Error writing History: %v", err)
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("Error closing Writer: %v", err)
+	}
+
+	return buf.String()
+}
+
+func TestCSVWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	out := writeTestHistory(t, NewCSVWriter(buf, testLabels), buf)
+	expect := "itemid,host,key,tags,clock,ns,value\n" +
+		"1,web 01,\"system.cpu.load[all,avg1]\",component=cpu,1500000000,500000000,0.25\n" +
+		"2,,,,1500000060,0,\"say \"\"hi\"\"\"\n"
+	if out != expect {
+		t.Errorf("Unexpected CSV output:\n%s", out)
+	}
+}
+
+func TestNDJSONWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	out := writeTestHistory(t, NewNDJSONWriter(buf, testLabels), buf)
+	expect := `{"itemid":1,"host":"web 01","key":"system.cpu.load[all,avg1]","tags":{"component":"cpu"},"clock":1500000000,"ns":500000000,"value_type":0,"value":0.25}` + "\n" +
+		`{"itemid":2,"clock":1500000060,"ns":0,"value_type":4,"value":"say \"hi\""}` + "\n"
+	if out != expect {
+		t.Errorf("Unexpected NDJSON output:\n%s", out)
+	}
+}
+
+func TestInfluxWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	out := writeTestHistory(t, NewInfluxWriter(buf, testLabels), buf)
+	expect := `zabbix_history,component=cpu,host=web\ 01,itemid=1,key=system.cpu.load[all\,avg1] value=0.25 1500000000500000000` + "\n" +
+		`zabbix_history,itemid=2 value="say \"hi\"" 1500000060000000000` + "\n"
+	if out != expect {
+		t.Errorf("Unexpected line protocol output:\n%s", out)
+	}
+}
+
+func TestInfluxWriterTags(t *testing.T) {
+	buf := &bytes.Buffer{}
+	labels := StaticLabels{
+		1: {
+			ItemID: 1,
+			Host:   "web01",
+			Key:    "agent.ping",
+			Tags: []zabbix.ItemTag{
+				{Tag: "host", Value: "spoofed"},
+				{Tag: "note", Value: "line one\nline two"},
+			},
+		},
+	}
+
+	w := NewInfluxWriter(buf, labels)
+	if err := w.WriteHistory(zabbix.History{ItemID: 1, Clock: 1500000000, ValueType: zabbix.ItemValueTypeUnsigned, Value: "1"}); err != nil {
+		t.Fatalf("Error writing History: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Error closing Writer: %v", err)
+	}
+
+	expect := `zabbix_history,host=web01,itemid=1,key=agent.ping,note=line\ one\ line\ two,tag_host=spoofed value=1i 1500000000000000000` + "\n"
+	if buf.String() != expect {
+		t.Errorf("Unexpected line protocol output:\n%s", buf.String())
+	}
+}
+
+func TestPrometheusWriterTagCollisions(t *testing.T) {
+	buf := &bytes.Buffer{}
+	labels := StaticLabels{
+		1: {
+			ItemID: 1,
+			Host:   "web01",
+			Key:    "agent.ping",
+			Tags: []zabbix.ItemTag{
+				{Tag: "app.name", Value: "b"},
+				{Tag: "app-name", Value: "a"},
+				{Tag: "app_name_2", Value: "c"},
+			},
+		},
+	}
+
+	w := NewPrometheusWriter(buf, labels)
+	if err := w.WriteHistory(zabbix.History{ItemID: 1, Clock: 1500000000, ValueType: zabbix.ItemValueTypeUnsigned, Value: "1"}); err != nil {
+		t.Fatalf("Error writing History: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Error closing Writer: %v", err)
+	}
+
+	expect := "# HELP zabbix_history Zabbix Item history.\n" +
+		"# TYPE zabbix_history gauge\n" +
+		`zabbix_history{host="web01",itemid="1",key="agent.ping",tag_app_name="a",tag_app_name_2="b",tag_app_name_2_2="c"} 1 1500000000000` + "\n"
+	if out := buf.String(); out != expect {
+		t.Errorf("Unexpected Prometheus output:\n%s", out)
+	}
+}
+
+func TestOpenMetricsWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	out := writeTestHistory(t, NewOpenMetricsWriter(buf, testLabels), buf)
+	expect := "# HELP zabbix_history Zabbix Item history.\n" +
+		"# TYPE zabbix_history gauge\n" +
+		`zabbix_history{host="web 01",itemid="1",key="system.cpu.load[all,avg1]",tag_component="cpu"} 0.25 1500000000.5` + "\n" +
+		"# EOF\n"
+	if out != expect {
+		t.Errorf("Unexpected OpenMetrics output:\n%s", out)
+	}
+}
+
+func TestPrometheusWriterTrends(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := NewPrometheusWriter(buf, nil)
+	if err := w.WriteTrend(zabbix.Trend{ItemID: 3, Clock: 3600, Num: 60, ValueMin: 1, ValueAvg: 1.5, ValueMax: 2}); err != nil {
+		t.Fatalf("Error writing Trend: %v", err)
+	}
+
+	if err := w.WriteHistory(testHistory[0]); err != ErrMixedKinds {
+		t.Errorf("Expected ErrMixedKinds, got %v", err)
+	}
+
+	w.Close()
+	expect := "# HELP zabbix_trend Zabbix Item hourly trends.\n" +
+		"# TYPE zabbix_trend gauge\n" +
+		`zabbix_trend{host="",itemid="3",key="",stat="min"} 1 3600000` + "\n" +
+		`zabbix_trend{host="",itemid="3",key="",stat="avg"} 1.5 3600000` + "\n" +
+		`zabbix_trend{host="",itemid="3",key="",stat="max"} 2 3600000` + "\n"
+	if out := buf.String(); out != expect {
+		t.Errorf("Unexpected Prometheus output:\n%s", out)
+	}
+}
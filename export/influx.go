@@ -0,0 +1,122 @@
+package export
+
+import (
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/cavaliercoder/go-zabbix"
+)
+
+// InfluxWriter writes History or Trends in InfluxDB line protocol with
+// nanosecond timestamps.
+//
+// History is written to the HistoryName measurement with a "value" field.
+// Float values are written as floats, unsigned values as integers and all
+// other values as strings. Trends are written to the TrendName measurement
+// with "num", "min", "avg" and "max" fields.
+//
+// Each line is tagged with itemid, host and key, and the Item tags. Item tags
+// named itemid, host or key are prefixed with "tag_". Item tags with empty
+// values are omitted, as they are not valid in line protocol, and line breaks
+// in tags are replaced with spaces.
+type InfluxWriter struct {
+	writer
+}
+
+// NewInfluxWriter returns a new InfluxWriter which writes to w and labels
+// values with the given Labeler. If labeler is nil, values are labelled with
+// their Item ID only.
+func NewInfluxWriter(w io.Writer, labeler Labeler) *InfluxWriter {
+	return &InfluxWriter{writer: newWriter(w, labeler)}
+}
+
+// influxNewlines replaces line breaks, which end a line even when escaped.
+var influxNewlines = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ")
+
+// influxTag escapes a tag key or value.
+func influxTag(s string) string {
+	return escape(influxNewlines.Replace(s), ",= ")
+}
+
+// writeSeries writes the measurement and tag set of a line.
+func (c *InfluxWriter) writeSeries(measurement string, labels *Labels) {
+	c.w.WriteString(escape(influxNewlines.Replace(measurement), ", "))
+
+	tags := make(map[string]string)
+	for name, value := range labels.TagMap() {
+		// Item tags must not replace the built-in tags
+		switch name {
+		case "itemid", "host", "key":
+			name = "tag_" + name
+		}
+		tags[name] = value
+	}
+	tags["itemid"] = strconv.Itoa(labels.ItemID)
+	tags["host"] = labels.Host
+	tags["key"] = labels.Key
+	for _, name := range tagNames(tags) {
+		if tags[name] == "" {
+			continue
+		}
+
+		c.w.WriteByte(',')
+		c.w.WriteString(influxTag(name))
+		c.w.WriteByte('=')
+		c.w.WriteString(influxTag(tags[name]))
+	}
+
+	c.w.WriteByte(' ')
+}
+
+// WriteHistory writes a single History.
+func (c *InfluxWriter) WriteHistory(history zabbix.History) error {
+	labels, _, err := c.begin(kindHistory, history.ItemID)
+	if err != nil {
+		return err
+	}
+
+	c.writeSeries(HistoryName, &labels)
+	c.w.WriteString("value=")
+	switch history.ValueType {
+	case zabbix.ItemValueTypeFloat:
+		c.w.WriteString(history.Value)
+
+	case zabbix.ItemValueTypeUnsigned:
+		// values which overflow a signed integer are written as floats
+		if _, err := strconv.ParseInt(history.Value, 10, 64); err == nil {
+			c.w.WriteString(history.Value + "i")
+		} else {
+			c.w.WriteString(history.Value)
+		}
+
+	default:
+		c.w.WriteString(`"` + escape(history.Value, `"\`) + `"`)
+	}
+
+	c.w.WriteByte(' ')
+	c.w.WriteString(strconv.FormatInt(historyTime(&history).UnixNano(), 10))
+	_, err = c.w.WriteString("\n")
+	return err
+}
+
+// WriteTrend writes a single Trend.
+func (c *InfluxWriter) WriteTrend(trend zabbix.Trend) error {
+	labels, _, err := c.begin(kindTrend, trend.ItemID)
+	if err != nil {
+		return err
+	}
+
+	c.writeSeries(TrendName, &labels)
+	c.w.WriteString(strings.Join([]string{
+		"num=" + strconv.Itoa(trend.Num) + "i",
+		"min=" + formatFloat(trend.ValueMin),
+		"avg=" + formatFloat(trend.ValueAvg),
+		"max=" + formatFloat(trend.ValueMax),
+	}, ","))
+
+	c.w.WriteByte(' ')
+	c.w.WriteString(strconv.FormatInt(trend.Time().UnixNano(), 10))
+	_, err = c.w.WriteString("\n")
+	return err
+}
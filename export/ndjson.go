@@ -0,0 +1,120 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+
+	"github.com/cavaliercoder/go-zabbix"
+)
+
+// NDJSONWriter writes History or Trends as newline-delimited JSON, with one
+// JSON object per line.
+//
+// Numeric History values are written as JSON numbers and all other values as
+// JSON strings. The Windows event log fields are included for log History.
+type NDJSONWriter struct {
+	writer
+	enc *json.Encoder
+}
+
+// NewNDJSONWriter returns a new NDJSONWriter which writes to w and labels
+// values with the given Labeler. If labeler is nil, values are labelled with
+// their Item ID only.
+func NewNDJSONWriter(w io.Writer, labeler Labeler) *NDJSONWriter {
+	c := &NDJSONWriter{writer: newWriter(w, labeler)}
+	c.enc = json.NewEncoder(c.w)
+	c.enc.SetEscapeHTML(false)
+	return c
+}
+
+// ndjsonHistory is the JSON encoding of an exported History.
+type ndjsonHistory struct {
+	ItemID     int               `json:"itemid"`
+	Host       string            `json:"host,omitempty"`
+	Key        string            `json:"key,omitempty"`
+	Tags       map[string]string `json:"tags,omitempty"`
+	Clock      int               `json:"clock"`
+	Ns         int               `json:"ns"`
+	ValueType  int               `json:"value_type"`
+	Value      interface{}       `json:"value"`
+	LogEventID int               `json:"logeventid,omitempty"`
+	Severity   int               `json:"severity,omitempty"`
+	Source     string            `json:"source,omitempty"`
+	Timestamp  string            `json:"timestamp,omitempty"`
+}
+
+// ndjsonTrend is the JSON encoding of an exported Trend.
+type ndjsonTrend struct {
+	ItemID   int               `json:"itemid"`
+	Host     string            `json:"host,omitempty"`
+	Key      string            `json:"key,omitempty"`
+	Tags     map[string]string `json:"tags,omitempty"`
+	Clock    int               `json:"clock"`
+	Num      int               `json:"num"`
+	ValueMin float64           `json:"value_min"`
+	ValueAvg float64           `json:"value_avg"`
+	ValueMax float64           `json:"value_max"`
+}
+
+// WriteHistory writes a single History.
+func (c *NDJSONWriter) WriteHistory(history zabbix.History) error {
+	labels, _, err := c.begin(kindHistory, history.ItemID)
+	if err != nil {
+		return err
+	}
+
+	out := ndjsonHistory{
+		ItemID:    history.ItemID,
+		Host:      labels.Host,
+		Key:       labels.Key,
+		Clock:     history.Clock,
+		Ns:        history.Ns,
+		ValueType: history.ValueType,
+		Value:     history.Value,
+	}
+
+	if len(labels.Tags) > 0 {
+		out.Tags = labels.TagMap()
+	}
+
+	if history.IsNumeric() {
+		if _, err := strconv.ParseFloat(history.Value, 64); err == nil {
+			out.Value = json.Number(history.Value)
+		}
+	}
+
+	if history.ValueType == zabbix.ItemValueTypeLog {
+		out.LogEventID = history.LogEventID
+		out.Severity = history.Severity
+		out.Source = history.Source
+		out.Timestamp = history.Timestamp
+	}
+
+	return c.enc.Encode(out)
+}
+
+// WriteTrend writes a single Trend.
+func (c *NDJSONWriter) WriteTrend(trend zabbix.Trend) error {
+	labels, _, err := c.begin(kindTrend, trend.ItemID)
+	if err != nil {
+		return err
+	}
+
+	out := ndjsonTrend{
+		ItemID:   trend.ItemID,
+		Host:     labels.Host,
+		Key:      labels.Key,
+		Clock:    trend.Clock,
+		Num:      trend.Num,
+		ValueMin: trend.ValueMin,
+		ValueAvg: trend.ValueAvg,
+		ValueMax: trend.ValueMax,
+	}
+
+	if len(labels.Tags) > 0 {
+		out.Tags = labels.TagMap()
+	}
+
+	return c.enc.Encode(out)
+}
@@ -0,0 +1,161 @@
+package export
+
+import (
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/cavaliercoder/go-zabbix"
+)
+
+// PrometheusWriter writes History or Trends as Prometheus text exposition
+// format or OpenMetrics text, with timestamps.
+//
+// History is written as the HistoryName gauge and Trends as the TrendName
+// gauge with a "stat" label of "min", "avg" or "max". Each sample is labelled
+// with itemid, host and key, and the Item tags with names prefixed by "tag_".
+// Characters which are not valid in label names are replaced with
+// underscores. Item tags whose label names are the same after replacement are
+// numbered in order of their tag names, such as "tag_app_name" for "app-name"
+// and "tag_app_name_2" for "app.name".
+//
+// Only numeric History is written; History of other value types is skipped.
+type PrometheusWriter struct {
+	writer
+	openMetrics bool
+}
+
+// NewPrometheusWriter returns a new PrometheusWriter which writes Prometheus
+// text exposition format to w and labels values with the given Labeler. If
+// labeler is nil, values are labelled with their Item ID only.
+func NewPrometheusWriter(w io.Writer, labeler Labeler) *PrometheusWriter {
+	return &PrometheusWriter{writer: newWriter(w, labeler)}
+}
+
+// NewOpenMetricsWriter returns a new PrometheusWriter which writes OpenMetrics
+// text to w and labels values with the given Labeler. If labeler is nil,
+// values are labelled with their Item ID only.
+//
+// Close must be called to write the terminating "# EOF" line.
+func NewOpenMetricsWriter(w io.Writer, labeler Labeler) *PrometheusWriter {
+	return &PrometheusWriter{writer: newWriter(w, labeler), openMetrics: true}
+}
+
+// labelName replaces all characters which are not valid in a Prometheus label
+// name with underscores.
+func labelName(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, s)
+}
+
+// writeSample writes a single sample of the given metric.
+func (c *PrometheusWriter) writeSample(name string, labels *Labels, extra map[string]string, value string, clock, ns int) {
+	tags := make(map[string]string)
+	itemTags := labels.TagMap()
+	for _, name := range tagNames(itemTags) {
+		base := "tag_" + labelName(name)
+		label := base
+		for n := 2; ; n++ {
+			if _, ok := tags[label]; !ok {
+				break
+			}
+			label = base + "_" + strconv.Itoa(n)
+		}
+		tags[label] = itemTags[name]
+	}
+	for name, value := range extra {
+		tags[name] = value
+	}
+	tags["itemid"] = strconv.Itoa(labels.ItemID)
+	tags["host"] = labels.Host
+	tags["key"] = labels.Key
+
+	c.w.WriteString(name + "{")
+	for i, name := range tagNames(tags) {
+		if i > 0 {
+			c.w.WriteByte(',')
+		}
+
+		c.w.WriteString(name + `="`)
+		c.w.WriteString(strings.Replace(escape(tags[name], `"\`), "\n", `\n`, -1))
+		c.w.WriteByte('"')
+	}
+	c.w.WriteString("} " + value + " ")
+
+	// Prometheus timestamps are milliseconds and OpenMetrics are seconds
+	if c.openMetrics {
+		c.w.WriteString(strconv.Itoa(clock))
+		if ns > 0 {
+			c.w.WriteString(strings.TrimRight("."+strconv.FormatInt(int64(ns)+1e9, 10)[1:], "0"))
+		}
+	} else {
+		c.w.WriteString(strconv.FormatInt(int64(clock)*1000+int64(ns)/1e6, 10))
+	}
+
+	c.w.WriteByte('\n')
+}
+
+// writeType writes the metric family header before the first sample.
+func (c *PrometheusWriter) writeType(name, help string) {
+	c.w.WriteString("# HELP " + name + " " + help + "\n")
+	c.w.WriteString("# TYPE " + name + " gauge\n")
+}
+
+// WriteHistory writes a single History. History which is not numeric is
+// skipped.
+func (c *PrometheusWriter) WriteHistory(history zabbix.History) error {
+	if !history.IsNumeric() {
+		return nil
+	}
+
+	labels, first, err := c.begin(kindHistory, history.ItemID)
+	if err != nil {
+		return err
+	}
+
+	if first {
+		c.writeType(HistoryName, "Zabbix Item history.")
+	}
+
+	c.writeSample(HistoryName, &labels, nil, history.Value, history.Clock, history.Ns)
+	return nil
+}
+
+// WriteTrend writes a single Trend.
+func (c *PrometheusWriter) WriteTrend(trend zabbix.Trend) error {
+	labels, first, err := c.begin(kindTrend, trend.ItemID)
+	if err != nil {
+		return err
+	}
+
+	if first {
+		c.writeType(TrendName, "Zabbix Item hourly trends.")
+	}
+
+	for _, stat := range []struct {
+		Name  string
+		Value float64
+	}{
+		{"min", trend.ValueMin},
+		{"avg", trend.ValueAvg},
+		{"max", trend.ValueMax},
+	} {
+		c.writeSample(TrendName, &labels, map[string]string{"stat": stat.Name}, formatFloat(stat.Value), trend.Clock, 0)
+	}
+
+	return nil
+}
+
+// Close writes the OpenMetrics "# EOF" line, if required, and flushes all
+// buffered data.
+func (c *PrometheusWriter) Close() error {
+	if c.openMetrics {
+		c.w.WriteString("# EOF\n")
+	}
+
+	return c.writer.Close()
+}
@@ -0,0 +1,340 @@
+package expression
+
+import (
+	"strings"
+)
+
+// Node is a node of a parsed trigger expression.
+type Node interface {
+	// Pos returns the byte offset of the node in the parsed expression.
+	Pos() int
+
+	// String returns the node formatted as a trigger expression.
+	String() string
+}
+
+// Binary is a binary operation, such as "a > 5" or "a and b".
+type Binary struct {
+	Position int
+
+	// Op is the operator, which is one of "or", "and", "=", "<>", "<", "<=",
+	// ">", ">=", "+", "-", "*" or "/".
+	Op string
+
+	Left, Right Node
+}
+
+// Unary is a unary operation, which is either negation with "-" or logical
+// negation with "not".
+type Unary struct {
+	Position int
+
+	// Op is the operator, which is one of "-" or "not".
+	Op string
+
+	Operand Node
+}
+
+// Number is a numeric constant, which may have a time or size suffix such as
+// "5m" or "1G".
+type Number struct {
+	Position int
+
+	// Raw is the constant as written in the expression.
+	Raw string
+
+	// Value is the value of the constant with any suffix applied, so "5m" is
+	// 300 and "1K" is 1024.
+	Value float64
+}
+
+// String is a quoted string constant, such as "error".
+type String struct {
+	Position int
+
+	// Value is the unquoted value of the string.
+	Value string
+}
+
+// Macro is a user macro such as {$MAX_LOAD}, a low-level discovery macro such
+// as {#FSNAME} or a built-in macro such as {TRIGGER.VALUE}.
+type Macro struct {
+	Position int
+
+	// Name is the macro including braces, as written in the expression.
+	Name string
+}
+
+// FunctionRef is a reference to a trigger function by its ID, such as
+// {12345}, as used in expressions returned by the Zabbix API unless they are
+// expanded.
+type FunctionRef struct {
+	Position int
+
+	// FunctionID is the ID of the referenced trigger function.
+	FunctionID string
+}
+
+// Function is a function call of the Zabbix 5.4+ syntax, such as
+// last(/host/key) or avg(/host/key,5m), or a math function such as
+// abs(last(/host/key)).
+type Function struct {
+	Position int
+
+	// Name is the function name.
+	Name string
+
+	// Args are the function arguments. Arguments are an ItemQuery, a Param
+	// for unquoted parameters such as periods, or any expression.
+	Args []Node
+}
+
+// ItemQuery is an item reference of the Zabbix 5.4+ syntax, such as
+// /host/key[param] or /*/key?[group="Servers"].
+type ItemQuery struct {
+	Position int
+
+	// Host is the technical name of the host, which may be empty for the
+	// host of the trigger or "*" for all hosts.
+	Host string
+
+	// Key is the item key including any parameters.
+	Key string
+
+	// Filter is the filter of an aggregate item query, without the enclosing
+	// "?[" and "]".
+	Filter string
+}
+
+// Param is an unquoted function parameter which is not an expression, such as
+// the period "#5" or "1h:now-1h", or an empty parameter.
+type Param struct {
+	Position int
+
+	// Value is the parameter as written in the expression.
+	Value string
+}
+
+// LegacyFunction is a function of the syntax used before Zabbix 5.4, such as
+// {host:key.last()} or {host:key[param].avg(5m)}.
+type LegacyFunction struct {
+	Position int
+
+	// Host is the technical name of the host.
+	Host string
+
+	// Key is the item key including any parameters.
+	Key string
+
+	// Name is the function name.
+	Name string
+
+	// Params are the function parameters as written in the expression,
+	// including any quotes.
+	Params []string
+}
+
+func (c *Binary) Pos() int         { return c.Position }
+func (c *Unary) Pos() int          { return c.Position }
+func (c *Number) Pos() int         { return c.Position }
+func (c *String) Pos() int         { return c.Position }
+func (c *Macro) Pos() int          { return c.Position }
+func (c *FunctionRef) Pos() int    { return c.Position }
+func (c *Function) Pos() int       { return c.Position }
+func (c *ItemQuery) Pos() int      { return c.Position }
+func (c *Param) Pos() int          { return c.Position }
+func (c *LegacyFunction) Pos() int { return c.Position }
+
+// precedence returns the binding strength of the given operator. Higher values
+// bind tighter.
+func precedence(op string) int {
+	switch op {
+	case "or":
+		return 1
+	case "and":
+		return 2
+	case "=", "<>":
+		return 3
+	case "<", "<=", ">", ">=":
+		return 4
+	case "+", "-":
+		return 5
+	case "*", "/":
+		return 6
+	case "not":
+		return 7
+	}
+
+	return 8
+}
+
+// nodePrecedence returns the binding strength of the given node.
+func nodePrecedence(n Node) int {
+	switch t := n.(type) {
+	case *Binary:
+		return precedence(t.Op)
+	case *Unary:
+		if t.Op == "not" {
+			return precedence("not")
+		}
+		return 8
+	}
+
+	return 9
+}
+
+// wrap formats the given node, with parentheses if it binds weaker than min.
+func wrap(n Node, min int) string {
+	if nodePrecedence(n) < min {
+		return "(" + n.String() + ")"
+	}
+
+	return n.String()
+}
+
+func (c *Binary) String() string {
+	p := precedence(c.Op)
+
+	// operators are left associative, so a right operand of equal
+	// precedence needs parentheses
+	return wrap(c.Left, p) + " " + c.Op + " " + wrap(c.Right, p+1)
+}
+
+func (c *Unary) String() string {
+	if c.Op == "not" {
+		return "not " + wrap(c.Operand, precedence("not"))
+	}
+
+	return "-" + wrap(c.Operand, 8)
+}
+
+func (c *Number) String() string {
+	return c.Raw
+}
+
+func (c *String) String() string {
+	return quote(c.Value)
+}
+
+func (c *Macro) String() string {
+	return c.Name
+}
+
+func (c *FunctionRef) String() string {
+	return "{" + c.FunctionID + "}"
+}
+
+func (c *Function) String() string {
+	args := make([]string, len(c.Args))
+	for i, arg := range c.Args {
+		args[i] = arg.String()
+	}
+
+	return c.Name + "(" + strings.Join(args, ",") + ")"
+}
+
+func (c *ItemQuery) String() string {
+	s := "/" + c.Host + "/" + c.Key
+	if c.Filter != "" {
+		s += "?[" + c.Filter + "]"
+	}
+
+	return s
+}
+
+func (c *Param) String() string {
+	return c.Value
+}
+
+func (c *LegacyFunction) String() string {
+	return "{" + c.Host + ":" + c.Key + "." + c.Name + "(" + strings.Join(c.Params, ",") + ")}"
+}
+
+// quote returns s as a quoted string constant.
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// Walk calls fn for the given node and each of its descendants in depth-first
+// order. If fn returns false, the descendants of the node are skipped.
+func Walk(n Node, fn func(Node) bool) {
+	if n == nil || !fn(n) {
+		return
+	}
+
+	switch t := n.(type) {
+	case *Binary:
+		Walk(t.Left, fn)
+		Walk(t.Right, fn)
+	case *Unary:
+		Walk(t.Operand, fn)
+	case *Function:
+		for _, arg := range t.Args {
+			Walk(arg, fn)
+		}
+	}
+}
+
+// ItemRef identifies an item referenced by an expression.
+type ItemRef struct {
+	// Host is the technical name of the host.
+	Host string
+
+	// Key is the item key.
+	Key string
+}
+
+// Items returns each item referenced by the given expression, in order of
+// first appearance. Items referenced by FunctionRef nodes are not included,
+// as they can only be resolved through the Zabbix API.
+func Items(n Node) []ItemRef {
+	out := make([]ItemRef, 0)
+	seen := make(map[ItemRef]bool)
+	add := func(ref ItemRef) {
+		if !seen[ref] {
+			seen[ref] = true
+			out = append(out, ref)
+		}
+	}
+
+	Walk(n, func(n Node) bool {
+		switch t := n.(type) {
+		case *ItemQuery:
+			add(ItemRef{Host: t.Host, Key: t.Key})
+		case *LegacyFunction:
+			add(ItemRef{Host: t.Host, Key: t.Key})
+		}
+		return true
+	})
+
+	return out
+}
+
+// Hosts returns the technical name of each host referenced by the given
+// expression, in order of first appearance.
+func Hosts(n Node) []string {
+	out := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, ref := range Items(n) {
+		if !seen[ref.Host] {
+			seen[ref.Host] = true
+			out = append(out, ref.Host)
+		}
+	}
+
+	return out
+}
+
+// IsLegacy returns true if the given expression uses the syntax of Zabbix
+// versions before 5.4.
+func IsLegacy(n Node) bool {
+	legacy := false
+	Walk(n, func(n Node) bool {
+		if _, ok := n.(*LegacyFunction); ok {
+			legacy = true
+		}
+		return !legacy
+	})
+
+	return legacy
+}
@@ -0,0 +1,450 @@
+package expression
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cavaliercoder/go-zabbix"
+)
+
+// Issue describes a part of a legacy expression which could not be converted
+// exactly to the Zabbix 5.4+ syntax.
+type Issue struct {
+	// Pos is the byte offset of the legacy fragment in the original
+	// expression.
+	Pos int
+
+	// Fragment is the legacy fragment, such as {host:key.func()}.
+	Fragment string
+
+	// Msg describes the issue.
+	Msg string
+}
+
+// String returns the string representation of an Issue.
+func (c Issue) String() string {
+	return fmt.Sprintf("position %d: %s: %s", c.Pos, c.Fragment, c.Msg)
+}
+
+// FunctionsFromTrigger returns the trigger functions of the given Trigger as
+// legacy functions keyed by function ID, for converting expressions which
+// reference functions by ID, such as {12345}.
+//
+// The Trigger must have been queried with TriggerGetParams.SelectFunctions,
+// SelectItems and SelectHosts.
+//
+// An error is returned if a function references an Item or Host which is not
+// in the Trigger.
+func FunctionsFromTrigger(trigger *zabbix.Trigger) (map[string]*LegacyFunction, error) {
+	hosts := make(map[string]string, len(trigger.Hosts))
+	for _, host := range trigger.Hosts {
+		hosts[host.HostID] = host.Hostname
+	}
+
+	items := make(map[string]*zabbix.Item, len(trigger.Items))
+	for i := range trigger.Items {
+		items[strconv.Itoa(trigger.Items[i].ItemID)] = &trigger.Items[i]
+	}
+
+	out := make(map[string]*LegacyFunction, len(trigger.Functions))
+	for _, f := range trigger.Functions {
+		item, ok := items[f.ItemID]
+		if !ok {
+			return nil, fmt.Errorf("Item %s of function %s not found in Trigger %s", f.ItemID, f.FunctionID, trigger.TriggerID)
+		}
+
+		host, ok := hosts[strconv.Itoa(item.HostID)]
+		if !ok {
+			return nil, fmt.Errorf("Host %d of Item %s not found in Trigger %s", item.HostID, f.ItemID, trigger.TriggerID)
+		}
+
+		out[f.FunctionID] = &LegacyFunction{
+			Host:   host,
+			Key:    item.Key,
+			Name:   f.Function,
+			Params: splitParams(f.Parameter),
+		}
+	}
+
+	return out, nil
+}
+
+// splitParams splits comma separated function parameters outside of quotes.
+func splitParams(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	out := make([]string, 0)
+	start, quoted := 0, false
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if quoted {
+				i++
+			}
+		case '"':
+			quoted = !quoted
+		case ',':
+			if !quoted {
+				out = append(out, strings.TrimSpace(s[start:i]))
+				start = i + 1
+			}
+		}
+	}
+
+	return append(out, strings.TrimSpace(s[start:]))
+}
+
+// Convert converts a trigger expression or recovery expression of the syntax
+// used before Zabbix 5.4 to the 5.4+ syntax.
+//
+// Function references such as {12345} are resolved with the given functions,
+// which may be nil if the expression is expanded. See FunctionsFromTrigger.
+//
+// Functions which were renamed or replaced in Zabbix 5.4 are rewritten, for
+// example str() to find() and delta() to max()-min(). Anything which can not
+// be converted exactly is returned as an Issue, with a best effort conversion
+// in the returned expression.
+//
+// A *SyntaxError is returned if the expression is invalid.
+func Convert(expr string, functions map[string]*LegacyFunction) (string, []Issue, error) {
+	n, err := Parse(expr)
+	if err != nil {
+		return "", nil, err
+	}
+
+	c := &converter{expr: expr, functions: functions}
+	out := c.convert(n)
+	c.checkItems(out, n)
+
+	return out.String(), c.issues, nil
+}
+
+// ConvertFormula converts the formula of a calculated Item of the syntax used
+// before Zabbix 5.4, such as last("key")+avg("host:key",300), to the 5.4+
+// syntax, such as last(//key)+avg(/host/key,300).
+//
+// See Convert.
+func ConvertFormula(formula string) (string, []Issue, error) {
+	n, err := Parse(formula)
+	if err != nil {
+		return "", nil, err
+	}
+
+	c := &converter{expr: formula, formula: true}
+	out := c.convert(n)
+	c.checkItems(out, n)
+
+	return out.String(), c.issues, nil
+}
+
+// converter rewrites legacy expressions.
+type converter struct {
+	expr      string
+	functions map[string]*LegacyFunction
+	formula   bool
+	issues    []Issue
+}
+
+func (c *converter) issue(n Node, fragment, format string, a ...interface{}) {
+	c.issues = append(c.issues, Issue{
+		Pos:      n.Pos(),
+		Fragment: fragment,
+		Msg:      fmt.Sprintf(format, a...),
+	})
+}
+
+// checkItems reports converted expressions which do not reference an item, as
+// required since Zabbix 5.4, if the original expression did.
+func (c *converter) checkItems(out, in Node) {
+	if len(Items(out)) > 0 {
+		return
+	}
+
+	refs := false
+	Walk(in, func(n Node) bool {
+		switch n.(type) {
+		case *LegacyFunction, *FunctionRef:
+			refs = true
+		}
+		return !refs
+	})
+
+	if refs {
+		c.issue(in, c.expr, "converted expression does not reference any item")
+	}
+}
+
+// convert returns the given node with all legacy functions converted.
+func (c *converter) convert(n Node) Node {
+	switch t := n.(type) {
+	case *Binary:
+		return &Binary{Position: t.Position, Op: t.Op, Left: c.convert(t.Left), Right: c.convert(t.Right)}
+
+	case *Unary:
+		return &Unary{Position: t.Position, Op: t.Op, Operand: c.convert(t.Operand)}
+
+	case *FunctionRef:
+		f, ok := c.functions[t.FunctionID]
+		if !ok {
+			c.issue(t, t.String(), "unknown function ID")
+			return t
+		}
+
+		legacy := *f
+		legacy.Position = t.Position
+		return c.convertLegacy(&legacy)
+
+	case *LegacyFunction:
+		return c.convertLegacy(t)
+
+	case *Function:
+		// legacy calculated item functions reference the item with a string
+		// such as "host:key" as their first argument
+		if c.formula && len(t.Args) > 0 {
+			if ref, ok := t.Args[0].(*String); ok {
+				return c.convertLegacy(formulaFunction(t, ref))
+			}
+		}
+
+		out := &Function{Position: t.Position, Name: t.Name, Args: make([]Node, len(t.Args))}
+		for i, arg := range t.Args {
+			out.Args[i] = c.convert(arg)
+		}
+		return out
+	}
+
+	return n
+}
+
+// formulaFunction returns the legacy calculated item function f, with the
+// item given by ref, as a LegacyFunction.
+func formulaFunction(f *Function, ref *String) *LegacyFunction {
+	out := &LegacyFunction{Position: f.Position, Name: f.Name, Key: ref.Value}
+
+	// the host is separated by the first colon before any key parameters
+	if i := strings.IndexByte(ref.Value, ':'); i >= 0 {
+		if j := strings.IndexByte(ref.Value, '['); j < 0 || i < j {
+			out.Host = ref.Value[:i]
+			out.Key = ref.Value[i+1:]
+		}
+	}
+
+	for _, arg := range f.Args[1:] {
+		out.Params = append(out.Params, arg.String())
+	}
+
+	return out
+}
+
+// unquote returns the value of a legacy function parameter without quotes.
+func unquote(s string) string {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return s
+	}
+
+	return strings.NewReplacer(`\"`, `"`, `\\`, `\`).Replace(s[1 : len(s)-1])
+}
+
+// param returns the i'th parameter of the given function without quotes, or an
+// empty string.
+func param(f *LegacyFunction, i int) string {
+	if i < len(f.Params) {
+		return unquote(f.Params[i])
+	}
+
+	return ""
+}
+
+// timeShift returns the legacy time shift as a 5.4+ period suffix.
+func timeShift(shift string) string {
+	if shift == "" || shift == "0" {
+		return ""
+	}
+
+	if strings.Trim(shift, "0123456789") == "" {
+		shift += "s"
+	}
+
+	return ":now-" + shift
+}
+
+// period returns the 5.4+ period parameter of a legacy period and time shift,
+// or nil if both are empty.
+func period(pos int, sec, shift string) Node {
+	shift = timeShift(shift)
+	if sec == "0" {
+		sec = ""
+	}
+
+	if sec == "" && shift == "" {
+		return nil
+	}
+
+	if sec == "" {
+		sec = "#1"
+	}
+
+	return &Param{Position: pos, Value: sec + shift}
+}
+
+// stringArg returns a quoted string parameter, or nil if it is empty.
+func stringArg(pos int, s string) Node {
+	if s == "" {
+		return nil
+	}
+
+	return &String{Position: pos, Value: s}
+}
+
+// rawArg returns an unquoted parameter, or nil if it is empty.
+func rawArg(pos int, s string) Node {
+	if s == "" {
+		return nil
+	}
+
+	return &Param{Position: pos, Value: s}
+}
+
+// call returns a function call with the given arguments. Trailing nil
+// arguments are omitted and other nil arguments are empty.
+func call(pos int, name string, args ...Node) *Function {
+	for len(args) > 0 && args[len(args)-1] == nil {
+		args = args[:len(args)-1]
+	}
+
+	for i, arg := range args {
+		if arg == nil {
+			args[i] = &Param{Position: pos}
+		}
+	}
+
+	return &Function{Position: pos, Name: name, Args: args}
+}
+
+// convertLegacy converts a legacy function to the 5.4+ syntax.
+func (c *converter) convertLegacy(f *LegacyFunction) Node {
+	pos := f.Position
+	item := &ItemQuery{Position: pos, Host: f.Host, Key: f.Key}
+	p := func(i int) string { return param(f, i) }
+
+	switch f.Name {
+	case "last":
+		// the legacy period of last() is ignored unless it is a count
+		sec := p(0)
+		if sec == "#1" || !strings.HasPrefix(sec, "#") {
+			sec = ""
+		}
+		return call(pos, "last", item, period(pos, sec, p(1)))
+
+	case "prev":
+		return call(pos, "last", item, rawArg(pos, "#2"))
+
+	case "avg", "min", "max", "sum":
+		return call(pos, f.Name, item, period(pos, p(0), p(1)))
+
+	case "count":
+		// legacy count(sec|#num,<pattern>,<operator>,<time_shift>), of which
+		// the operator defaults to eq for numeric patterns and to like for
+		// string patterns
+		operator := p(2)
+		if operator == "" && p(1) != "" {
+			operator = "like"
+			if _, err := strconv.ParseFloat(p(1), 64); err == nil {
+				operator = "eq"
+			}
+		}
+		return call(pos, "count", item,
+			period(pos, p(0), p(3)),
+			stringArg(pos, operator),
+			stringArg(pos, p(1)))
+
+	case "delta":
+		return &Binary{
+			Position: pos,
+			Op:       "-",
+			Left:     call(pos, "max", item, period(pos, p(0), p(1))),
+			Right:    call(pos, "min", &ItemQuery{Position: pos, Host: f.Host, Key: f.Key}, period(pos, p(0), p(1))),
+		}
+
+	case "change":
+		return call(pos, "change", item)
+
+	case "abschange":
+		return call(pos, "abs", call(pos, "change", item))
+
+	case "diff":
+		return &Binary{
+			Position: pos,
+			Op:       "<>",
+			Left:     call(pos, "change", item),
+			Right:    &Number{Position: pos, Raw: "0"},
+		}
+
+	case "nodata":
+		return call(pos, "nodata", item, rawArg(pos, p(0)), stringArg(pos, p(1)))
+
+	case "str", "regexp", "iregexp":
+		operator := map[string]string{"str": "like", "regexp": "regexp", "iregexp": "iregexp"}[f.Name]
+		return call(pos, "find", item,
+			period(pos, p(1), ""),
+			&String{Position: pos, Value: operator},
+			&String{Position: pos, Value: p(0)})
+
+	case "strlen":
+		return call(pos, "length", call(pos, "last", item, period(pos, p(0), p(1))))
+
+	case "band":
+		// legacy band(sec|#num,mask,<time_shift>)
+		return call(pos, "bitand", call(pos, "last", item, period(pos, p(0), p(2))), rawArg(pos, p(1)))
+
+	case "date", "time", "dayofweek", "dayofmonth", "now":
+		return call(pos, f.Name)
+
+	case "fuzzytime":
+		return call(pos, "fuzzytime", item, rawArg(pos, p(0)))
+
+	case "logeventid", "logsource":
+		return call(pos, f.Name, item, nil, stringArg(pos, p(0)))
+
+	case "logseverity":
+		return call(pos, "logseverity", item)
+
+	case "forecast":
+		// legacy forecast(sec|#num,<time_shift>,time,<fit>,<mode>)
+		return call(pos, "forecast", item,
+			period(pos, p(0), p(1)),
+			rawArg(pos, p(2)),
+			stringArg(pos, p(3)),
+			stringArg(pos, p(4)))
+
+	case "timeleft":
+		// legacy timeleft(sec|#num,<time_shift>,threshold,<fit>)
+		return call(pos, "timeleft", item,
+			period(pos, p(0), p(1)),
+			rawArg(pos, p(2)),
+			stringArg(pos, p(3)))
+
+	case "percentile":
+		// legacy percentile(sec|#num,<time_shift>,percentage)
+		return call(pos, "percentile", item, period(pos, p(0), p(1)), rawArg(pos, p(2)))
+
+	case "trendavg", "trendcount", "trenddelta", "trendmax", "trendmin", "trendsum":
+		// legacy trendavg(period,period_shift)
+		value := p(0)
+		if p(1) != "" {
+			value += ":" + p(1)
+		}
+		return call(pos, f.Name, item, rawArg(pos, value))
+	}
+
+	c.issue(f, f.String(), "unknown function %q was not converted", f.Name)
+	args := []Node{item}
+	for _, param := range f.Params {
+		args = append(args, &Param{Position: pos, Value: param})
+	}
+
+	return call(pos, f.Name, args...)
+}
@@ -0,0 +1,109 @@
+package expression
+
+import (
+	"testing"
+
+	"github.com/cavaliercoder/go-zabbix"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		Expr   string
+		Expect string
+	}{
+		{"{web01:system.cpu.load[all,avg1].last()}>5", "last(/web01/system.cpu.load[all,avg1]) > 5"},
+		{"{web01:system.cpu.load.last(0)}>5", "last(/web01/system.cpu.load) > 5"},
+		{"{web01:system.cpu.load.last(#3,1d)}>5", "last(/web01/system.cpu.load,#3:now-1d) > 5"},
+		{"{web01:net.if.in[eth0].avg(5m)}>1G", "avg(/web01/net.if.in[eth0],5m) > 1G"},
+		{"{web01:net.if.in[eth0].min(300,86400)}=0", "min(/web01/net.if.in[eth0],300:now-86400s) = 0"},
+		{"{web01:agent.ping.nodata(5m)}=1", "nodata(/web01/agent.ping,5m) = 1"},
+		{"{web01:log.str(error)}=1", "find(/web01/log,,\"like\",\"error\") = 1"},
+		{"{web01:log.iregexp(\"^err\",#5)}=1", "find(/web01/log,#5,\"iregexp\",\"^err\") = 1"},
+		{"{web01:log.count(10m,error,like)}>2", "count(/web01/log,10m,\"like\",\"error\") > 2"},
+		{"{web01:log.count(10m,error)}>2", "count(/web01/log,10m,\"like\",\"error\") > 2"},
+		{"{web01:net.tcp.port.count(#5,0)}>2", "count(/web01/net.tcp.port,#5,\"eq\",\"0\") > 2"},
+		{"{web01:net.tcp.port.count(5m)}>2", "count(/web01/net.tcp.port,5m) > 2"},
+		{"{web01:net.if.in.delta(1h)}>100", "max(/web01/net.if.in,1h) - min(/web01/net.if.in,1h) > 100"},
+		{"{web01:app.version.diff()}=1", "change(/web01/app.version) <> 0 = 1"},
+		{"{web01:app.version.strlen()}>0", "length(last(/web01/app.version)) > 0"},
+		{"{web01:flags.band(,12)}=8", "bitand(last(/web01/flags),12) = 8"},
+		{"{web01:agent.ping.last()}=1 and {web01:agent.ping.time()}>090000", "last(/web01/agent.ping) = 1 and time() > 090000"},
+		{"{web01:a.abschange()}>1 or {web01:a.prev()}#{web01:a.last()}", "abs(change(/web01/a)) > 1 or last(/web01/a,#2) <> last(/web01/a)"},
+		{"last(/web01/a)>1", "last(/web01/a) > 1"},
+	}
+
+	for _, test := range tests {
+		out, issues, err := Convert(test.Expr, nil)
+		if err != nil {
+			t.Errorf("Error converting %s: %v", test.Expr, err)
+			continue
+		}
+
+		if len(issues) > 0 {
+			t.Errorf("Unexpected issues converting %s: %v", test.Expr, issues)
+		}
+
+		if out != test.Expect {
+			t.Errorf("Expected %s to convert to %s, got %s", test.Expr, test.Expect, out)
+		}
+
+		if _, err := Parse(out); err != nil {
+			t.Errorf("Error parsing converted expression %s: %v", out, err)
+		}
+	}
+}
+
+func TestConvertIssues(t *testing.T) {
+	_, issues, err := Convert("{web01:a.unknownfunc(1)}>0 or {999}=1", nil)
+	if err != nil {
+		t.Fatalf("Error converting expression: %v", err)
+	}
+
+	if len(issues) != 2 || issues[0].Pos != 0 || issues[1].Pos != 30 {
+		t.Errorf("Unexpected issues: %v", issues)
+	}
+
+	_, issues, err = Convert("{web01:a.now()}>0", nil)
+	if err != nil || len(issues) != 1 {
+		t.Errorf("Expected an issue for an expression with no items, got %v, %v", issues, err)
+	}
+}
+
+func TestConvertFunctionRefs(t *testing.T) {
+	trigger := &zabbix.Trigger{
+		TriggerID: "1",
+		Hosts:     []zabbix.Host{{HostID: "10", Hostname: "web01"}},
+		Items:     []zabbix.Item{{ItemID: 20, HostID: 10, Key: "vfs.fs.size[/,pfree]"}},
+		Functions: []zabbix.TriggerFunction{
+			{FunctionID: "30", ItemID: "20", Function: "avg", Parameter: "5m"},
+			{FunctionID: "31", ItemID: "20", Function: "str", Parameter: "\"a,b\",#2"},
+		},
+	}
+
+	functions, err := FunctionsFromTrigger(trigger)
+	if err != nil {
+		t.Fatalf("Error mapping functions: %v", err)
+	}
+
+	out, issues, err := Convert("{30}<{$MIN} or {31}=1", functions)
+	if err != nil || len(issues) > 0 {
+		t.Fatalf("Error converting expression: %v, %v", err, issues)
+	}
+
+	expect := `avg(/web01/vfs.fs.size[/,pfree],5m) < {$MIN} or find(/web01/vfs.fs.size[/,pfree],#2,"like","a,b") = 1`
+	if out != expect {
+		t.Errorf("Expected %s, got %s", expect, out)
+	}
+}
+
+func TestConvertFormula(t *testing.T) {
+	out, issues, err := ConvertFormula(`100*last("vfs.fs.size[/,free]")/last("web01:vfs.fs.size[/,total]")+avg("net.if.in[eth0]",300)`)
+	if err != nil || len(issues) > 0 {
+		t.Fatalf("Error converting formula: %v, %v", err, issues)
+	}
+
+	expect := "100 * last(//vfs.fs.size[/,free]) / last(/web01/vfs.fs.size[/,total]) + avg(//net.if.in[eth0],300)"
+	if out != expect {
+		t.Errorf("Expected %s, got %s", expect, out)
+	}
+}
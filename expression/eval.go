@@ -0,0 +1,902 @@
+package expression
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cavaliercoder/go-zabbix"
+)
+
+// State is the result of evaluating a trigger expression.
+type State int
+
+const (
+	// StateOK means the expression evaluated to zero.
+	StateOK State = iota
+
+	// StateProblem means the expression evaluated to a non-zero value.
+	StateProblem
+
+	// StateUnknown means the expression could not be evaluated, for example
+	// because an item has no values in the evaluated period.
+	StateUnknown
+)
+
+// String returns the name of the State as shown in the Zabbix frontend.
+func (c State) String() string {
+	switch c {
+	case StateOK:
+		return "OK"
+	case StateProblem:
+		return "PROBLEM"
+	}
+
+	return "UNKNOWN"
+}
+
+// epsilon is the tolerance of numeric equality in trigger expressions.
+const epsilon = 0.000001
+
+// Evaluation is the result of evaluating a trigger expression at a point in
+// time.
+type Evaluation struct {
+	// Time is the time at which the expression was evaluated.
+	Time time.Time
+
+	// State is the trigger state.
+	State State
+
+	// Value is the numeric result of the expression if State is not
+	// StateUnknown.
+	Value float64
+
+	// Err describes why the expression could not be evaluated if State is
+	// StateUnknown.
+	Err error
+}
+
+// Evaluator evaluates trigger expressions against item history, so that
+// triggers may be tested without a Zabbix server.
+//
+// Only the values of each item which were received at or before the time of
+// evaluation are used, so a sequence of evaluations simulates the state of a
+// trigger over time. Legacy expressions are converted with Convert before they
+// are evaluated.
+type Evaluator struct {
+	// History is the history of each item referenced by evaluated
+	// expressions. See HistoryFromTrigger and FetchHistory. History must not
+	// be modified after the first evaluation.
+	History map[ItemRef][]zabbix.History
+
+	// Host is the host of item queries with an empty host, such as
+	// last(//key).
+	Host string
+
+	// Macros are the values of user macros, keyed by the macro including
+	// braces, such as {$MAX_LOAD}.
+	Macros map[string]string
+
+	// Functions resolves function references such as {12345} in legacy
+	// expressions. See FunctionsFromTrigger.
+	Functions map[string]*LegacyFunction
+
+	// Location is the time zone of the date and time functions. If nil, the
+	// local time zone is used.
+	Location *time.Location
+
+	sorted       map[ItemRef][]zabbix.History
+	triggerValue State
+}
+
+// HistoryFromTrigger returns the given Histories keyed by the host and key of
+// their Item, for the Items of the given Trigger.
+//
+// The Trigger must have been queried with TriggerGetParams.SelectItems and
+// SelectHosts. Histories of Items which are not in the Trigger are ignored.
+func HistoryFromTrigger(trigger *zabbix.Trigger, histories []zabbix.History) map[ItemRef][]zabbix.History {
+	hosts := make(map[string]string, len(trigger.Hosts))
+	for _, host := range trigger.Hosts {
+		hosts[host.HostID] = host.Hostname
+	}
+
+	refs := make(map[int]ItemRef, len(trigger.Items))
+	for _, item := range trigger.Items {
+		refs[item.ItemID] = ItemRef{Host: hosts[strconv.Itoa(item.HostID)], Key: item.Key}
+	}
+
+	out := make(map[ItemRef][]zabbix.History)
+	for _, history := range histories {
+		if ref, ok := refs[history.ItemID]; ok {
+			out[ref] = append(out[ref], history)
+		}
+	}
+
+	return out
+}
+
+// FetchHistory queries the Zabbix API for the History of all Items of the
+// given Trigger received between the given times, keyed by the host and key
+// of their Item.
+//
+// The Trigger must have been queried with TriggerGetParams.SelectItems and
+// SelectHosts.
+//
+// An error is returned if a transport, parsing or API error occurs.
+func FetchHistory(session *zabbix.Session, trigger *zabbix.Trigger, from, till time.Time) (map[ItemRef][]zabbix.History, error) {
+	itemIDs := make([]string, len(trigger.Items))
+	for i, item := range trigger.Items {
+		itemIDs[i] = strconv.Itoa(item.ItemID)
+	}
+
+	histories, err := session.GetItemHistories(zabbix.HistoryGetParams{
+		ItemIDs:  itemIDs,
+		TimeFrom: float64(from.Unix()),
+		TimeTill: float64(till.Unix()),
+	})
+	if err != nil && err != zabbix.ErrNotFound {
+		return nil, err
+	}
+
+	return HistoryFromTrigger(trigger, histories), nil
+}
+
+// prepare converts legacy expressions and sorts the history of each item.
+func (c *Evaluator) prepare(n Node) (Node, error) {
+	if c.sorted == nil {
+		c.sorted = make(map[ItemRef][]zabbix.History, len(c.History))
+		for ref, histories := range c.History {
+			sorted := make([]zabbix.History, len(histories))
+			copy(sorted, histories)
+			sort.SliceStable(sorted, func(i, j int) bool {
+				if sorted[i].Clock != sorted[j].Clock {
+					return sorted[i].Clock < sorted[j].Clock
+				}
+				return sorted[i].Ns < sorted[j].Ns
+			})
+			c.sorted[ref] = sorted
+		}
+	}
+
+	legacy := false
+	Walk(n, func(n Node) bool {
+		switch n.(type) {
+		case *LegacyFunction, *FunctionRef:
+			legacy = true
+		}
+		return !legacy
+	})
+
+	if !legacy {
+		return n, nil
+	}
+
+	conv := &converter{functions: c.Functions}
+	out := conv.convert(n)
+	if len(conv.issues) > 0 {
+		return nil, fmt.Errorf("cannot convert legacy expression: %s", conv.issues[0].Msg)
+	}
+
+	return out, nil
+}
+
+// Evaluate evaluates the given expression at the given time.
+func (c *Evaluator) Evaluate(n Node, at time.Time) Evaluation {
+	n, err := c.prepare(n)
+	if err != nil {
+		return Evaluation{Time: at, State: StateUnknown, Err: err}
+	}
+
+	return c.evaluate(n, at)
+}
+
+func (c *Evaluator) evaluate(n Node, at time.Time) Evaluation {
+	v, err := c.eval(n, at)
+	if err == nil && v.isStr {
+		err = errors.New("expression result is not numeric")
+	}
+
+	if err != nil {
+		return Evaluation{Time: at, State: StateUnknown, Err: err}
+	}
+
+	state := StateOK
+	if math.Abs(v.num) >= epsilon {
+		state = StateProblem
+	}
+
+	return Evaluation{Time: at, State: state, Value: v.num}
+}
+
+// Timeline evaluates the given expression at each time a referenced item
+// received a value between from and till, and additionally at each interval of
+// the given step if it is greater than zero, which is required to simulate
+// time based functions such as nodata().
+//
+// The {TRIGGER.VALUE} macro is the state of the previous evaluation which was
+// not StateUnknown, starting with StateOK.
+func (c *Evaluator) Timeline(n Node, from, till time.Time, step time.Duration) []Evaluation {
+	n, err := c.prepare(n)
+	if err != nil {
+		return []Evaluation{{Time: from, State: StateUnknown, Err: err}}
+	}
+
+	// collect evaluation times
+	seen := make(map[int64]bool)
+	times := make([]time.Time, 0)
+	add := func(t time.Time) {
+		if !t.Before(from) && !t.After(till) && !seen[t.UnixNano()] {
+			seen[t.UnixNano()] = true
+			times = append(times, t)
+		}
+	}
+
+	for _, ref := range Items(n) {
+		for _, history := range c.sorted[c.resolve(ref)] {
+			add(history.Time())
+		}
+	}
+
+	if step > 0 {
+		for t := from; !t.After(till); t = t.Add(step) {
+			add(t)
+		}
+	}
+
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+
+	c.triggerValue = StateOK
+	out := make([]Evaluation, len(times))
+	for i, t := range times {
+		out[i] = c.evaluate(n, t)
+		if out[i].State != StateUnknown {
+			c.triggerValue = out[i].State
+		}
+	}
+	c.triggerValue = StateOK
+
+	return out
+}
+
+// resolve returns the given item reference with the default host applied.
+func (c *Evaluator) resolve(ref ItemRef) ItemRef {
+	if ref.Host == "" {
+		ref.Host = c.Host
+	}
+
+	return ref
+}
+
+// value is the result of evaluating a node, which is either a number or a
+// string.
+type value struct {
+	num   float64
+	str   string
+	isStr bool
+}
+
+func number(v float64) value {
+	return value{num: v}
+}
+
+func boolean(b bool) value {
+	if b {
+		return number(1)
+	}
+	return number(0)
+}
+
+// text returns the value as a string.
+func (c value) text() string {
+	if c.isStr {
+		return c.str
+	}
+	return strconv.FormatFloat(c.num, 'f', -1, 64)
+}
+
+// numeric returns the value as a number.
+func (c value) numeric() (float64, error) {
+	if !c.isStr {
+		return c.num, nil
+	}
+
+	return parseValue(c.str)
+}
+
+// parseValue parses a numeric value with an optional suffix.
+func parseValue(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	m := 1.0
+	if len(s) > 1 {
+		if v, ok := suffixes[s[len(s)-1]]; ok {
+			m = v
+			s = s[:len(s)-1]
+		}
+	}
+
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("value %q is not numeric", s)
+	}
+
+	return v * m, nil
+}
+
+// historyValue returns a History value as a number if it is numeric or as a
+// string otherwise.
+func historyValue(history zabbix.History) value {
+	if v, err := strconv.ParseFloat(history.Value, 64); err == nil {
+		return number(v)
+	}
+
+	return value{str: history.Value, isStr: true}
+}
+
+// eval evaluates a node at the given time.
+func (c *Evaluator) eval(n Node, at time.Time) (value, error) {
+	switch t := n.(type) {
+	case *Number:
+		return number(t.Value), nil
+
+	case *String:
+		return value{str: t.Value, isStr: true}, nil
+
+	case *Param:
+		return value{str: t.Value, isStr: true}, nil
+
+	case *Macro:
+		return c.macro(t)
+
+	case *Unary:
+		v, err := c.eval(t.Operand, at)
+		if err != nil {
+			return value{}, err
+		}
+
+		f, err := v.numeric()
+		if err != nil {
+			return value{}, err
+		}
+
+		if t.Op == "not" {
+			return boolean(math.Abs(f) < epsilon), nil
+		}
+		return number(-f), nil
+
+	case *Binary:
+		return c.binary(t, at)
+
+	case *Function:
+		return c.function(t, at)
+	}
+
+	return value{}, fmt.Errorf("cannot evaluate %s", n)
+}
+
+// macro returns the value of a macro.
+func (c *Evaluator) macro(m *Macro) (value, error) {
+	if m.Name == "{TRIGGER.VALUE}" {
+		return number(float64(c.triggerValue)), nil
+	}
+
+	s, ok := c.Macros[m.Name]
+	if !ok {
+		return value{}, fmt.Errorf("macro %s is not defined", m.Name)
+	}
+
+	if v, err := parseValue(s); err == nil {
+		return number(v), nil
+	}
+
+	return value{str: s, isStr: true}, nil
+}
+
+// binary evaluates a binary operation.
+func (c *Evaluator) binary(b *Binary, at time.Time) (value, error) {
+	left, lerr := c.eval(b.Left, at)
+	right, rerr := c.eval(b.Right, at)
+
+	// logical operators tolerate an unknown operand if the other operand
+	// determines the result
+	if b.Op == "and" || b.Op == "or" {
+		l, lok := truth(left, lerr)
+		r, rok := truth(right, rerr)
+		if b.Op == "or" && ((lok && l) || (rok && r)) {
+			return number(1), nil
+		}
+		if b.Op == "and" && ((lok && !l) || (rok && !r)) {
+			return number(0), nil
+		}
+
+		if lerr != nil {
+			return value{}, lerr
+		}
+		if rerr != nil {
+			return value{}, rerr
+		}
+		if !lok || !rok {
+			return value{}, errors.New("logical operand is not numeric")
+		}
+
+		return boolean(l && r || (b.Op == "or" && (l || r))), nil
+	}
+
+	if lerr != nil {
+		return value{}, lerr
+	}
+	if rerr != nil {
+		return value{}, rerr
+	}
+
+	// strings may only be compared for equality
+	if (left.isStr || right.isStr) && (b.Op == "=" || b.Op == "<>") {
+		lf, lnerr := left.numeric()
+		rf, rnerr := right.numeric()
+		if lnerr != nil || rnerr != nil {
+			equal := left.text() == right.text()
+			return boolean(equal == (b.Op == "=")), nil
+		}
+		left, right = number(lf), number(rf)
+	}
+
+	l, err := left.numeric()
+	if err != nil {
+		return value{}, err
+	}
+
+	r, err := right.numeric()
+	if err != nil {
+		return value{}, err
+	}
+
+	switch b.Op {
+	case "=":
+		return boolean(math.Abs(l-r) < epsilon), nil
+	case "<>":
+		return boolean(math.Abs(l-r) >= epsilon), nil
+	case "<":
+		return boolean(l < r), nil
+	case "<=":
+		return boolean(l <= r), nil
+	case ">":
+		return boolean(l > r), nil
+	case ">=":
+		return boolean(l >= r), nil
+	case "+":
+		return number(l + r), nil
+	case "-":
+		return number(l - r), nil
+	case "*":
+		return number(l * r), nil
+	case "/":
+		if r == 0 {
+			return value{}, errors.New("division by zero")
+		}
+		return number(l / r), nil
+	}
+
+	return value{}, fmt.Errorf("unsupported operator %q", b.Op)
+}
+
+// truth returns the truth of a logical operand and whether it is known.
+func truth(v value, err error) (bool, bool) {
+	if err != nil {
+		return false, false
+	}
+
+	f, err := v.numeric()
+	if err != nil {
+		return false, false
+	}
+
+	return math.Abs(f) >= epsilon, true
+}
+
+// evalPeriod is a parsed function period such as "5m", "#3" or "1h:now-1d".
+type evalPeriod struct {
+	count    int
+	duration time.Duration
+	shift    time.Duration
+}
+
+// parsePeriod parses a function period. An empty period selects the last
+// value.
+func parsePeriod(s string) (evalPeriod, error) {
+	p := evalPeriod{}
+	if i := strings.Index(s, ":"); i >= 0 {
+		shift := s[i+1:]
+		s = s[:i]
+		if shift != "now" {
+			if !strings.HasPrefix(shift, "now-") {
+				return p, fmt.Errorf("unsupported time shift %q", shift)
+			}
+
+			v, err := parseValue(shift[4:])
+			if err != nil {
+				return p, fmt.Errorf("invalid time shift %q", shift)
+			}
+			p.shift = time.Duration(v * float64(time.Second))
+		}
+	}
+
+	switch {
+	case s == "":
+		p.count = 1
+
+	case strings.HasPrefix(s, "#"):
+		n, err := strconv.Atoi(s[1:])
+		if err != nil || n < 1 {
+			return p, fmt.Errorf("invalid value count %q", s)
+		}
+		p.count = n
+
+	default:
+		v, err := parseValue(s)
+		if err != nil || v <= 0 {
+			return p, fmt.Errorf("invalid period %q", s)
+		}
+		p.duration = time.Duration(v * float64(time.Second))
+	}
+
+	return p, nil
+}
+
+// values returns the values of the given item in the given period before
+// the given time, in order of time.
+func (c *Evaluator) values(q *ItemQuery, period string, at time.Time) ([]zabbix.History, error) {
+	p, err := parsePeriod(period)
+	if err != nil {
+		return nil, err
+	}
+
+	ref := c.resolve(ItemRef{Host: q.Host, Key: q.Key})
+	histories, ok := c.sorted[ref]
+	if !ok {
+		return nil, fmt.Errorf("no history for item /%s/%s", ref.Host, ref.Key)
+	}
+
+	at = at.Add(-p.shift)
+	end := sort.Search(len(histories), func(i int) bool {
+		return histories[i].Time().After(at)
+	})
+
+	if p.count > 0 {
+		start := end - p.count
+		if start < 0 {
+			start = 0
+		}
+		return histories[start:end], nil
+	}
+
+	since := at.Add(-p.duration)
+	start := sort.Search(end, func(i int) bool {
+		return histories[i].Time().After(since)
+	})
+
+	return histories[start:end], nil
+}
+
+// numericValues returns the given History values as numbers.
+func numericValues(histories []zabbix.History) ([]float64, error) {
+	out := make([]float64, len(histories))
+	for i, history := range histories {
+		v, err := strconv.ParseFloat(history.Value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("value %q is not numeric", history.Value)
+		}
+		out[i] = v
+	}
+
+	return out, nil
+}
+
+// function evaluates a function call.
+func (c *Evaluator) function(f *Function, at time.Time) (value, error) {
+	args := make([]value, len(f.Args))
+	var query *ItemQuery
+	for i, arg := range f.Args {
+		if q, ok := arg.(*ItemQuery); ok && i == 0 {
+			query = q
+			continue
+		}
+
+		// periods are passed as written
+		if n, ok := arg.(*Number); ok && query != nil && i == 1 {
+			args[i] = value{str: n.Raw, isStr: true}
+			continue
+		}
+
+		v, err := c.eval(arg, at)
+		if err != nil {
+			return value{}, err
+		}
+		args[i] = v
+	}
+
+	arg := func(i int) string {
+		if i < len(args) {
+			return args[i].text()
+		}
+		return ""
+	}
+
+	if query == nil {
+		return c.mathFunction(f.Name, args, at)
+	}
+
+	// nodata() is the only function which does not require values
+	if f.Name == "nodata" {
+		if arg(1) == "" {
+			return value{}, errors.New("nodata() requires a period")
+		}
+
+		histories, err := c.values(query, arg(1), at)
+		if err != nil {
+			return value{}, err
+		}
+		return boolean(len(histories) == 0), nil
+	}
+
+	histories, err := c.values(query, arg(1), at)
+	if err != nil {
+		return value{}, err
+	}
+
+	if f.Name == "count" || f.Name == "find" {
+		matched := 0
+		for _, history := range histories {
+			ok, err := matchValue(history.Value, arg(2), arg(3), len(args) > 3)
+			if err != nil {
+				return value{}, err
+			}
+			if ok {
+				matched++
+			}
+		}
+
+		if f.Name == "find" {
+			return boolean(matched > 0), nil
+		}
+		return number(float64(matched)), nil
+	}
+
+	if len(histories) == 0 {
+		return value{}, fmt.Errorf("no values of %s in the evaluated period", query)
+	}
+
+	switch f.Name {
+	case "last":
+		// last(/host/key,#n) returns the n'th most recent value
+		p, _ := parsePeriod(arg(1))
+		if p.count == 0 {
+			return historyValue(histories[len(histories)-1]), nil
+		}
+		if len(histories) < p.count {
+			return value{}, fmt.Errorf("not enough values of %s", query)
+		}
+		return historyValue(histories[0]), nil
+
+	case "change":
+		// change() compares the last two values before any time shift
+		period := "#2"
+		if i := strings.Index(arg(1), ":"); i >= 0 {
+			period += arg(1)[i:]
+		}
+
+		all, err := c.values(query, period, at)
+		if err != nil {
+			return value{}, err
+		}
+		if len(all) < 2 {
+			return value{}, fmt.Errorf("not enough values of %s", query)
+		}
+
+		prev, last := historyValue(all[0]), historyValue(all[1])
+		if prev.isStr || last.isStr {
+			return boolean(prev.text() != last.text()), nil
+		}
+		return number(last.num - prev.num), nil
+	}
+
+	values, err := numericValues(histories)
+	if err != nil {
+		return value{}, err
+	}
+
+	switch f.Name {
+	case "min":
+		return number(aggregate(values, math.Min)), nil
+	case "max":
+		return number(aggregate(values, math.Max)), nil
+	case "sum", "avg":
+		sum := 0.0
+		for _, v := range values {
+			sum += v
+		}
+		if f.Name == "avg" {
+			return number(sum / float64(len(values))), nil
+		}
+		return number(sum), nil
+
+	case "percentile":
+		pct, err := strconv.ParseFloat(arg(2), 64)
+		if err != nil || pct < 0 || pct > 100 {
+			return value{}, fmt.Errorf("invalid percentage %q", arg(2))
+		}
+
+		// nearest rank
+		sort.Float64s(values)
+		rank := int(math.Ceil(pct / 100 * float64(len(values))))
+		if rank < 1 {
+			rank = 1
+		}
+		return number(values[rank-1]), nil
+	}
+
+	return value{}, fmt.Errorf("unsupported function %s()", f.Name)
+}
+
+// aggregate reduces the given values with fn.
+func aggregate(values []float64, fn func(a, b float64) float64) float64 {
+	out := values[0]
+	for _, v := range values[1:] {
+		out = fn(out, v)
+	}
+
+	return out
+}
+
+// matchValue matches a History value with the given operator and pattern of
+// count() and find(). Without a pattern, all values match.
+func matchValue(v, operator, pattern string, hasPattern bool) (bool, error) {
+	if !hasPattern {
+		return true, nil
+	}
+
+	if operator == "" {
+		operator = "eq"
+	}
+
+	switch operator {
+	case "like":
+		return strings.Contains(v, pattern), nil
+
+	case "regexp", "iregexp":
+		if operator == "iregexp" {
+			pattern = "(?i)" + pattern
+		}
+
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return false, fmt.Errorf("invalid regular expression %q: %v", pattern, err)
+		}
+		return re.MatchString(v), nil
+
+	case "bitand":
+		mask := pattern
+		want := pattern
+		if i := strings.Index(pattern, "/"); i >= 0 {
+			want, mask = pattern[:i], pattern[i+1:]
+		}
+
+		n, err1 := strconv.ParseUint(v, 10, 64)
+		m, err2 := strconv.ParseUint(mask, 10, 64)
+		w, err3 := strconv.ParseUint(want, 10, 64)
+		if err1 != nil || err2 != nil || err3 != nil {
+			return false, fmt.Errorf("invalid bitand operand")
+		}
+		return n&m == w, nil
+	}
+
+	// numeric comparison, or string equality for non-numeric values
+	a, err1 := strconv.ParseFloat(v, 64)
+	b, err2 := parseValue(pattern)
+	if err1 != nil || err2 != nil {
+		switch operator {
+		case "eq":
+			return v == pattern, nil
+		case "ne":
+			return v != pattern, nil
+		}
+		return false, fmt.Errorf("value %q is not numeric", v)
+	}
+
+	switch operator {
+	case "eq":
+		return math.Abs(a-b) < epsilon, nil
+	case "ne":
+		return math.Abs(a-b) >= epsilon, nil
+	case "gt":
+		return a > b, nil
+	case "ge":
+		return a >= b, nil
+	case "lt":
+		return a < b, nil
+	case "le":
+		return a <= b, nil
+	}
+
+	return false, fmt.Errorf("unsupported operator %q", operator)
+}
+
+// mathFunction evaluates a function which does not reference an item.
+func (c *Evaluator) mathFunction(name string, args []value, at time.Time) (value, error) {
+	loc := c.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	local := at.In(loc)
+
+	switch name {
+	case "date":
+		return number(float64(local.Year()*10000 + int(local.Month())*100 + local.Day())), nil
+	case "time":
+		return number(float64(local.Hour()*10000 + local.Minute()*100 + local.Second())), nil
+	case "dayofweek":
+		day := int(local.Weekday())
+		if day == 0 {
+			day = 7
+		}
+		return number(float64(day)), nil
+	case "dayofmonth":
+		return number(float64(local.Day())), nil
+	case "now":
+		return number(float64(at.Unix())), nil
+	case "length":
+		if len(args) != 1 {
+			return value{}, errors.New("length() requires one argument")
+		}
+		return number(float64(len([]rune(args[0].text())))), nil
+	}
+
+	values := make([]float64, len(args))
+	for i, arg := range args {
+		v, err := arg.numeric()
+		if err != nil {
+			return value{}, err
+		}
+		values[i] = v
+	}
+
+	switch name {
+	case "abs":
+		if len(values) != 1 {
+			return value{}, errors.New("abs() requires one argument")
+		}
+		return number(math.Abs(values[0])), nil
+
+	case "bitand":
+		if len(values) != 2 {
+			return value{}, errors.New("bitand() requires two arguments")
+		}
+		return number(float64(uint64(values[0]) & uint64(values[1]))), nil
+
+	case "min", "max", "sum", "avg":
+		if len(values) == 0 {
+			return value{}, fmt.Errorf("%s() requires at least one argument", name)
+		}
+
+		switch name {
+		case "min":
+			return number(aggregate(values, math.Min)), nil
+		case "max":
+			return number(aggregate(values, math.Max)), nil
+		}
+
+		sum := 0.0
+		for _, v := range values {
+			sum += v
+		}
+		if name == "avg" {
+			sum /= float64(len(values))
+		}
+		return number(sum), nil
+	}
+
+	return value{}, fmt.Errorf("unsupported function %s()", name)
+}
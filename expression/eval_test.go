@@ -0,0 +1,150 @@
+package expression
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cavaliercoder/go-zabbix"
+)
+
+// testHistory returns History of the given values, one per minute from the
+// Unix epoch.
+func testHistory(values ...string) []zabbix.History {
+	out := make([]zabbix.History, len(values))
+	for i, v := range values {
+		out[i] = zabbix.History{ItemID: 1, Clock: i * 60, Value: v}
+	}
+
+	return out
+}
+
+func TestEvaluate(t *testing.T) {
+	c := &Evaluator{
+		History: map[ItemRef][]zabbix.History{
+			{Host: "web01", Key: "load"}: testHistory("1", "4", "2", "8", "5"),
+			{Host: "web01", Key: "log"}:  testHistory("ok", "error: disk", "ok"),
+		},
+		Host:   "web01",
+		Macros: map[string]string{"{$MAX}": "6", "{$NAME}": "ok"},
+	}
+
+	at := time.Unix(240, 0)
+	tests := []struct {
+		Expr   string
+		Expect State
+		Value  float64
+	}{
+		{"last(/web01/load)", StateProblem, 5},
+		{"last(/web01/load,#2)", StateProblem, 8},
+		{"last(/web01/load,#1:now-2m)", StateProblem, 2},
+		{"min(/web01/load,3m)", StateProblem, 2},
+		{"max(/web01/load,#3)", StateProblem, 8},
+		{"avg(/web01/load,5m)", StateProblem, 4},
+		{"sum(//load,2m)", StateProblem, 13},
+		{"count(/web01/load,5m)", StateProblem, 5},
+		{"count(/web01/load,5m,\"gt\",4)", StateProblem, 2},
+		{"change(/web01/load)", StateProblem, -3},
+		{"change(/web01/log)", StateProblem, 1},
+		{"percentile(/web01/load,5m,50)", StateProblem, 4},
+		{"nodata(/web01/load,30s)", StateOK, 0},
+		{"find(/web01/log,5m,\"like\",\"error\")", StateProblem, 1},
+		{"find(/web01/log,,\"regexp\",\"^err\")", StateOK, 0},
+		{"last(/web01/load) > {$MAX}", StateOK, 0},
+		{"last(/web01/log) = {$NAME}", StateProblem, 1},
+		{"abs(change(/web01/load)) > 2 and not nodata(/web01/load,1m)", StateProblem, 1},
+		{"max(/web01/load,5m) - min(/web01/load,5m) >= 7", StateProblem, 1},
+		{"{web01:load.prev()} > {web01:load.last()}", StateProblem, 1},
+		{"{web01:load.last()} > 1K", StateOK, 0},
+	}
+
+	for _, test := range tests {
+		n, err := Parse(test.Expr)
+		if err != nil {
+			t.Errorf("Error parsing %s: %v", test.Expr, err)
+			continue
+		}
+
+		result := c.Evaluate(n, at)
+		if result.Err != nil {
+			t.Errorf("Error evaluating %s: %v", test.Expr, result.Err)
+			continue
+		}
+
+		if result.State != test.Expect || result.Value != test.Value {
+			t.Errorf("Expected %s to be %v (%v), got %v (%v)", test.Expr, test.Expect, test.Value, result.State, result.Value)
+		}
+	}
+}
+
+func TestEvaluateUnknown(t *testing.T) {
+	c := &Evaluator{
+		History: map[ItemRef][]zabbix.History{
+			{Host: "web01", Key: "load"}: testHistory("1", "2"),
+		},
+	}
+
+	tests := []struct {
+		Expr   string
+		Expect State
+	}{
+		{"last(/web01/missing) > 1", StateUnknown},
+		{"last(/web01/load,#3) > 1", StateUnknown},
+		{"avg(/web01/load,1m:now-1h) > 1", StateUnknown},
+		{"last(/web01/load) > {$UNDEFINED}", StateUnknown},
+		{"last(/web01/load) / 0", StateUnknown},
+
+		// logical operators tolerate an unknown operand
+		{"last(/web01/missing) > 1 or last(/web01/load) = 2", StateProblem},
+		{"last(/web01/missing) > 1 and last(/web01/load) = 1", StateOK},
+	}
+
+	for _, test := range tests {
+		result := c.Evaluate(MustParse(test.Expr), time.Unix(60, 0))
+		if result.State != test.Expect {
+			t.Errorf("Expected %s to be %v, got %v (%v)", test.Expr, test.Expect, result.State, result.Err)
+		}
+	}
+}
+
+func TestTimeline(t *testing.T) {
+	history := testHistory("1", "9", "9", "2")
+	history = append(history, zabbix.History{ItemID: 1, Clock: 600, Value: "1"})
+	c := &Evaluator{
+		History: map[ItemRef][]zabbix.History{
+			{Host: "web01", Key: "load"}: history,
+		},
+	}
+
+	// hysteresis: raise above 5, recover below 3
+	n := MustParse("({TRIGGER.VALUE}=0 and last(/web01/load)>5) or ({TRIGGER.VALUE}=1 and last(/web01/load)>=3) or nodata(/web01/load,5m)=1")
+	timeline := c.Timeline(n, time.Unix(0, 0), time.Unix(600, 0), 5*time.Minute)
+
+	expect := []struct {
+		Clock int64
+		State State
+	}{
+		{0, StateOK},
+		{60, StateProblem},
+		{120, StateProblem},
+		{180, StateOK},
+		{300, StateOK},
+		{600, StateOK},
+	}
+
+	if len(timeline) != len(expect) {
+		t.Fatalf("Expected %d evaluations, got %d", len(expect), len(timeline))
+	}
+
+	for i, e := range expect {
+		if timeline[i].Time.Unix() != e.Clock || timeline[i].State != e.State {
+			t.Errorf("Expected %v at %d, got %v at %d (%v)", e.State, e.Clock, timeline[i].State, timeline[i].Time.Unix(), timeline[i].Err)
+		}
+	}
+
+	// no data for more than 5 minutes before 600
+	c = &Evaluator{History: c.History}
+	result := c.Evaluate(MustParse("nodata(/web01/load,5m)=1"), time.Unix(599, 0))
+	if result.State != StateProblem {
+		t.Errorf("Expected nodata to be PROBLEM, got %v (%v)", result.State, result.Err)
+	}
+}
@@ -0,0 +1,116 @@
+package expression
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		Expr   string
+		Expect string
+	}{
+		{"last(/web01/system.cpu.load[all,avg1])>5", "last(/web01/system.cpu.load[all,avg1]) > 5"},
+		{"avg(/web01/net.if.in[\"eth0\",bytes],5m)>1G or {$ALWAYS}=1", "avg(/web01/net.if.in[\"eth0\",bytes],5m) > 1G or {$ALWAYS} = 1"},
+		{"count(/web01/log,#10:now-1h,\"like\",\"error\")>=2", "count(/web01/log,#10:now-1h,\"like\",\"error\") >= 2"},
+		{"sum(last_foreach(/*/vfs.fs.size[*,free]?[group=\"Servers\"]))<10G", "sum(last_foreach(/*/vfs.fs.size[*,free]?[group=\"Servers\"])) < 10G"},
+		{"abs(last(/h/k)-last(/h/k,#2))>(1+2)*3", "abs(last(/h/k) - last(/h/k,#2)) > (1 + 2) * 3"},
+		{"not (a() and b())", "not (a() and b())"},
+		{"1-(2-3)", "1 - (2 - 3)"},
+		{"-{12345}<0", "-{12345} < 0"},
+		{"{web01:system.cpu.load[all,avg1].last()}>5", "{web01:system.cpu.load[all,avg1].last()} > 5"},
+		{"{Zabbix server:vfs.fs.size[/,pfree].avg(5m, 1h)}#{$MIN}&{TRIGGER.VALUE}=0", ""},
+		{"{web01:log[/var/log/app.log].str(\"a,b\")}=1", "{web01:log[/var/log/app.log].str(\"a,b\")} = 1"},
+		{"{#FSNAME}=\"/\"", "{#FSNAME} = \"/\""},
+	}
+
+	for _, test := range tests {
+		n, err := Parse(test.Expr)
+		if test.Expect == "" {
+			if err == nil {
+				t.Errorf("Expected a syntax error parsing %s", test.Expr)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("Error parsing %s: %v", test.Expr, err)
+			continue
+		}
+
+		if s := n.String(); s != test.Expect {
+			t.Errorf("Expected %s to format as %s, got %s", test.Expr, test.Expect, s)
+		}
+
+		// formatted expressions must parse to the same tree
+		if again, err := Parse(n.String()); err != nil || again.String() != n.String() {
+			t.Errorf("Expected %s to round trip, got %v", n, err)
+		}
+	}
+}
+
+func TestParseValues(t *testing.T) {
+	n := MustParse("last(/h/k)>5m and last(/h/k)<1K")
+	b := n.(*Binary)
+	if b.Op != "and" {
+		t.Fatalf("Expected 'and' at the root, got %s", b.Op)
+	}
+
+	if v := b.Left.(*Binary).Right.(*Number).Value; v != 300 {
+		t.Errorf("Expected 5m to be 300, got %v", v)
+	}
+
+	if v := b.Right.(*Binary).Right.(*Number).Value; v != 1024 {
+		t.Errorf("Expected 1K to be 1024, got %v", v)
+	}
+
+	legacy := MustParse("{web01:agent.ping.nodata(5m)}=1").(*Binary).Left.(*LegacyFunction)
+	expect := &LegacyFunction{Position: 0, Host: "web01", Key: "agent.ping", Name: "nodata", Params: []string{"5m"}}
+	if !reflect.DeepEqual(legacy, expect) {
+		t.Errorf("Unexpected legacy function: %+v", legacy)
+	}
+}
+
+func TestItemsAndHosts(t *testing.T) {
+	n := MustParse("last(/web01/a)>1 and {web02:b[x].last()}>1 or min(/web01/a,5m)>0")
+	items := Items(n)
+	expect := []ItemRef{{"web01", "a"}, {"web02", "b[x]"}}
+	if !reflect.DeepEqual(items, expect) {
+		t.Errorf("Unexpected items: %+v", items)
+	}
+
+	if hosts := Hosts(n); !reflect.DeepEqual(hosts, []string{"web01", "web02"}) {
+		t.Errorf("Unexpected hosts: %v", hosts)
+	}
+
+	if !IsLegacy(n) {
+		t.Errorf("Expected expression to be legacy")
+	}
+}
+
+func TestSyntaxErrors(t *testing.T) {
+	tests := []struct {
+		Expr string
+		Pos  int
+	}{
+		{"last(/h/k)>", 11},
+		{"last(/h/k) > 5 )", 15},
+		{"(last(/h/k) > 5", 15},
+		{"last(/h/k,\"x) > 5", 10},
+		{"{web01:agent.ping} = 1", 7},
+		{"5x > 1", 1},
+	}
+
+	for _, test := range tests {
+		_, err := Parse(test.Expr)
+		serr, ok := err.(*SyntaxError)
+		if !ok {
+			t.Errorf("Expected a SyntaxError parsing %s, got %v", test.Expr, err)
+			continue
+		}
+
+		if serr.Pos != test.Pos {
+			t.Errorf("Expected error parsing %s at position %d, got %v", test.Expr, test.Pos, serr)
+		}
+	}
+}
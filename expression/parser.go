@@ -0,0 +1,608 @@
+/*
+Package expression parses Zabbix trigger expressions into an abstract syntax
+tree, so that triggers can be inspected, linted and rewritten in code.
+
+Both the syntax of Zabbix 5.4 and later:
+
+	last(/web01/system.cpu.load[all,avg1])>5 and avg(/web01/net.if.in[eth0],5m)>1G
+
+and the legacy syntax of earlier versions are supported:
+
+	{web01:system.cpu.load[all,avg1].last()}>5 and {web01:net.if.in[eth0].avg(5m)}>1G
+
+as are the function references, such as {12345}, of expressions returned by
+the Zabbix API.
+*/
+package expression
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SyntaxError describes a syntax error in a trigger expression.
+type SyntaxError struct {
+	// Pos is the byte offset of the error in the expression.
+	Pos int
+
+	// Msg describes the error.
+	Msg string
+}
+
+// Error returns the string representation of a SyntaxError.
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at position %d: %s", e.Pos, e.Msg)
+}
+
+// suffixes are the multipliers of the time and size suffixes of numeric
+// constants.
+var suffixes = map[byte]float64{
+	's': 1,
+	'm': 60,
+	'h': 3600,
+	'd': 86400,
+	'w': 604800,
+	'K': 1 << 10,
+	'M': 1 << 20,
+	'G': 1 << 30,
+	'T': 1 << 40,
+}
+
+// Parse parses the given trigger expression.
+//
+// A *SyntaxError is returned if the expression is invalid.
+func Parse(expr string) (Node, error) {
+	p := &parser{s: expr}
+	n, err := p.parseExpr(1)
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpace()
+	if p.pos < len(p.s) {
+		return nil, p.errorf("unexpected %q", p.s[p.pos])
+	}
+
+	return n, nil
+}
+
+// MustParse is like Parse but panics if the expression is invalid.
+func MustParse(expr string) Node {
+	n, err := Parse(expr)
+	if err != nil {
+		panic(err)
+	}
+
+	return n
+}
+
+type parser struct {
+	s   string
+	pos int
+}
+
+func (p *parser) errorf(format string, a ...interface{}) error {
+	return &SyntaxError{Pos: p.pos, Msg: fmt.Sprintf(format, a...)}
+}
+
+func (p *parser) peek() byte {
+	if p.pos < len(p.s) {
+		return p.s[p.pos]
+	}
+	return 0
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.s) && strings.IndexByte(" \t\r\n", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+// isIdent returns true if the given character may be part of a function name
+// or keyword.
+func isIdent(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// keyword returns true if the given keyword is at the current position and is
+// not the prefix of a longer identifier.
+func (p *parser) keyword(word string) bool {
+	if !strings.HasPrefix(p.s[p.pos:], word) {
+		return false
+	}
+
+	end := p.pos + len(word)
+	return end >= len(p.s) || !isIdent(p.s[end])
+}
+
+// operator returns the binary operator at the current position, if any.
+func (p *parser) operator() string {
+	for _, word := range []string{"and", "or"} {
+		if p.keyword(word) {
+			return word
+		}
+	}
+
+	for _, op := range []string{"<>", "<=", ">=", "=", "<", ">", "+", "-", "*", "/", "#"} {
+		if strings.HasPrefix(p.s[p.pos:], op) {
+			return op
+		}
+	}
+
+	return ""
+}
+
+// parseExpr parses binary operations which bind at least as tight as min.
+func (p *parser) parseExpr(min int) (Node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		p.skipSpace()
+		start := p.pos
+		op := p.operator()
+		if op == "" {
+			return left, nil
+		}
+
+		// "#" is the legacy not equal operator
+		normalized := op
+		if op == "#" {
+			normalized = "<>"
+		}
+
+		prec := precedence(normalized)
+		if prec < min {
+			return left, nil
+		}
+		p.pos += len(op)
+
+		right, err := p.parseExpr(prec + 1)
+		if err != nil {
+			return nil, err
+		}
+
+		left = &Binary{Position: start, Op: normalized, Left: left, Right: right}
+	}
+}
+
+// parseUnary parses a unary operation or a primary expression.
+func (p *parser) parseUnary() (Node, error) {
+	p.skipSpace()
+	start := p.pos
+	switch {
+	case p.peek() == '-':
+		p.pos++
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &Unary{Position: start, Op: "-", Operand: operand}, nil
+
+	case p.keyword("not"):
+		p.pos += 3
+		operand, err := p.parseExpr(precedence("not"))
+		if err != nil {
+			return nil, err
+		}
+		return &Unary{Position: start, Op: "not", Operand: operand}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+// parsePrimary parses a constant, macro, function or parenthesized
+// expression.
+func (p *parser) parsePrimary() (Node, error) {
+	p.skipSpace()
+	start := p.pos
+	c := p.peek()
+	switch {
+	case c == 0:
+		return nil, p.errorf("unexpected end of expression")
+
+	case c == '(':
+		p.pos++
+		n, err := p.parseExpr(1)
+		if err != nil {
+			return nil, err
+		}
+
+		p.skipSpace()
+		if p.peek() != ')' {
+			return nil, p.errorf("expected ')'")
+		}
+		p.pos++
+		return n, nil
+
+	case c == '"':
+		s, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return &String{Position: start, Value: s}, nil
+
+	case c == '{':
+		return p.parseBrace()
+
+	case c == '.' || (c >= '0' && c <= '9'):
+		return p.parseNumber()
+
+	case isIdent(c):
+		end := p.pos
+		for end < len(p.s) && isIdent(p.s[end]) {
+			end++
+		}
+
+		if end < len(p.s) && p.s[end] == '(' {
+			return p.parseFunction()
+		}
+	}
+
+	return nil, p.errorf("unexpected %q", c)
+}
+
+// parseString parses a quoted string with backslash escapes.
+func (p *parser) parseString() (string, error) {
+	start := p.pos
+	p.pos++
+
+	var b strings.Builder
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		p.pos++
+		switch c {
+		case '\\':
+			if p.pos >= len(p.s) {
+				break
+			}
+			b.WriteByte(p.s[p.pos])
+			p.pos++
+		case '"':
+			return b.String(), nil
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	p.pos = start
+	return "", p.errorf("unterminated string")
+}
+
+// parseNumber parses a numeric constant with an optional suffix.
+func (p *parser) parseNumber() (Node, error) {
+	start := p.pos
+	for p.pos < len(p.s) && (p.s[p.pos] == '.' || (p.s[p.pos] >= '0' && p.s[p.pos] <= '9')) {
+		p.pos++
+	}
+
+	// scientific notation
+	if p.pos < len(p.s) && (p.s[p.pos] == 'e' || p.s[p.pos] == 'E') {
+		end := p.pos + 1
+		if end < len(p.s) && (p.s[end] == '+' || p.s[end] == '-') {
+			end++
+		}
+		if end < len(p.s) && p.s[end] >= '0' && p.s[end] <= '9' {
+			p.pos = end
+			for p.pos < len(p.s) && p.s[p.pos] >= '0' && p.s[p.pos] <= '9' {
+				p.pos++
+			}
+		}
+	}
+
+	raw := p.s[start:p.pos]
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		p.pos = start
+		return nil, p.errorf("invalid number %q", raw)
+	}
+
+	if p.pos < len(p.s) {
+		if m, ok := suffixes[p.s[p.pos]]; ok {
+			v *= m
+			p.pos++
+		}
+	}
+
+	if p.pos < len(p.s) && isIdent(p.s[p.pos]) {
+		return nil, p.errorf("invalid suffix %q", p.s[p.pos])
+	}
+
+	return &Number{Position: start, Raw: p.s[start:p.pos], Value: v}, nil
+}
+
+// scanBraces returns the offset after the '}' which closes the '{' at the
+// given offset, skipping quoted strings.
+func (p *parser) scanBraces(start int) (int, error) {
+	depth := 0
+	for i := start; i < len(p.s); i++ {
+		switch p.s[i] {
+		case '"':
+			i++
+			for i < len(p.s) && p.s[i] != '"' {
+				if p.s[i] == '\\' {
+					i++
+				}
+				i++
+			}
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i + 1, nil
+			}
+		}
+	}
+
+	p.pos = start
+	return 0, p.errorf("unterminated '{'")
+}
+
+// parseBrace parses a macro, function reference or legacy function, which all
+// start with '{'.
+func (p *parser) parseBrace() (Node, error) {
+	start := p.pos
+	end, err := p.scanBraces(start)
+	if err != nil {
+		return nil, err
+	}
+
+	body := p.s[start+1 : end-1]
+	switch {
+	case strings.HasPrefix(body, "$"), strings.HasPrefix(body, "#"), strings.HasPrefix(body, "{"):
+		p.pos = end
+		return &Macro{Position: start, Name: p.s[start:end]}, nil
+
+	case body != "" && strings.Trim(body, "0123456789") == "":
+		p.pos = end
+		return &FunctionRef{Position: start, FunctionID: body}, nil
+
+	case body != "" && strings.Trim(body, "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789._") == "":
+		p.pos = end
+		return &Macro{Position: start, Name: p.s[start:end]}, nil
+	}
+
+	return p.parseLegacyFunction(start, end)
+}
+
+// scanKey returns the offset after the item key at the given offset. The key
+// ends at the first of the given characters which is not within the key
+// parameters.
+func (p *parser) scanKey(start int, stop string) (int, error) {
+	i := start
+	for i < len(p.s) && strings.IndexByte(stop, p.s[i]) < 0 {
+		if p.s[i] != '[' {
+			i++
+			continue
+		}
+
+		// key parameters may be quoted and nested one level
+		depth := 0
+		for ; i < len(p.s); i++ {
+			switch p.s[i] {
+			case '"':
+				i++
+				for i < len(p.s) && p.s[i] != '"' {
+					if p.s[i] == '\\' {
+						i++
+					}
+					i++
+				}
+			case '[':
+				depth++
+			case ']':
+				depth--
+			}
+
+			if depth == 0 {
+				break
+			}
+		}
+
+		if i >= len(p.s) {
+			p.pos = start
+			return 0, p.errorf("unterminated item key parameters")
+		}
+		i++
+	}
+
+	return i, nil
+}
+
+// parseLegacyFunction parses a legacy function {host:key.func(params)} which
+// starts at start and ends at end.
+func (p *parser) parseLegacyFunction(start, end int) (Node, error) {
+	n := &LegacyFunction{Position: start}
+
+	colon := strings.IndexByte(p.s[start:end], ':')
+	if colon < 0 {
+		p.pos = start
+		return nil, p.errorf("invalid function or macro %q", p.s[start:end])
+	}
+	n.Host = p.s[start+1 : start+colon]
+	if n.Host == "" {
+		p.pos = start + 1
+		return nil, p.errorf("missing host")
+	}
+
+	// the key and function name are separated by the last '.' before the
+	// function parameters
+	keyStart := start + colon + 1
+	paren, err := p.scanKey(keyStart, "(}")
+	if err != nil {
+		return nil, err
+	}
+
+	if paren >= end || p.s[paren] != '(' {
+		p.pos = keyStart
+		return nil, p.errorf("missing function")
+	}
+
+	dot := strings.LastIndexByte(p.s[keyStart:paren], '.')
+	if dot <= 0 {
+		p.pos = keyStart
+		return nil, p.errorf("missing item key or function name")
+	}
+
+	n.Key = p.s[keyStart : keyStart+dot]
+	n.Name = p.s[keyStart+dot+1 : paren]
+	if n.Name == "" {
+		p.pos = keyStart + dot + 1
+		return nil, p.errorf("missing function name")
+	}
+
+	// function parameters are separated by commas outside of quotes
+	p.pos = paren + 1
+	param := p.pos
+	for {
+		if p.pos >= end-1 {
+			p.pos = paren
+			return nil, p.errorf("unterminated function parameters")
+		}
+
+		switch p.s[p.pos] {
+		case '"':
+			if _, err := p.parseString(); err != nil {
+				return nil, err
+			}
+			continue
+
+		case ',':
+			n.Params = append(n.Params, strings.TrimSpace(p.s[param:p.pos]))
+			param = p.pos + 1
+
+		case ')':
+			if last := strings.TrimSpace(p.s[param:p.pos]); last != "" || len(n.Params) > 0 {
+				n.Params = append(n.Params, last)
+			}
+
+			p.pos++
+			if p.pos != end-1 {
+				return nil, p.errorf("expected '}'")
+			}
+			p.pos = end
+			return n, nil
+		}
+		p.pos++
+	}
+}
+
+// parseFunction parses a function call of the Zabbix 5.4+ syntax.
+func (p *parser) parseFunction() (Node, error) {
+	n := &Function{Position: p.pos}
+	for p.pos < len(p.s) && isIdent(p.s[p.pos]) {
+		p.pos++
+	}
+	n.Name = p.s[n.Position:p.pos]
+	p.pos++ // '('
+
+	p.skipSpace()
+	if p.peek() == ')' {
+		p.pos++
+		return n, nil
+	}
+
+	for {
+		arg, err := p.parseArg()
+		if err != nil {
+			return nil, err
+		}
+		n.Args = append(n.Args, arg)
+
+		p.skipSpace()
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ')':
+			p.pos++
+			return n, nil
+		default:
+			return nil, p.errorf("expected ',' or ')' in arguments of %s()", n.Name)
+		}
+	}
+}
+
+// scanParam returns the offset of the end of the unquoted function parameter
+// at the current position.
+func (p *parser) scanParam() int {
+	depth := 0
+	for i := p.pos; i < len(p.s); i++ {
+		switch p.s[i] {
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		case ',':
+			if depth == 0 {
+				return i
+			}
+		case '"':
+			return len(p.s) // quoted parameters are expressions
+		}
+	}
+
+	return len(p.s)
+}
+
+// parseArg parses a function argument.
+func (p *parser) parseArg() (Node, error) {
+	p.skipSpace()
+	start := p.pos
+	if p.peek() == '/' {
+		return p.parseItemQuery()
+	}
+
+	// unquoted parameters which are not expressions, such as "#5",
+	// "1h:now-1h" or an empty parameter
+	end := p.scanParam()
+	raw := strings.TrimSpace(p.s[start:end])
+	if raw == "" || raw[0] == '#' || (strings.Contains(raw, ":") && !strings.ContainsAny(raw, `{"(`)) {
+		p.pos = end
+		return &Param{Position: start, Value: raw}, nil
+	}
+
+	return p.parseExpr(1)
+}
+
+// parseItemQuery parses an item query /host/key, with an optional filter.
+func (p *parser) parseItemQuery() (Node, error) {
+	n := &ItemQuery{Position: p.pos}
+	p.pos++
+
+	slash := strings.IndexByte(p.s[p.pos:], '/')
+	if slash < 0 {
+		return nil, p.errorf("missing item key in item query")
+	}
+	n.Host = p.s[p.pos : p.pos+slash]
+	p.pos += slash + 1
+
+	end, err := p.scanKey(p.pos, ",)? ")
+	if err != nil {
+		return nil, err
+	}
+
+	n.Key = p.s[p.pos:end]
+	if n.Key == "" {
+		return nil, p.errorf("missing item key in item query")
+	}
+	p.pos = end
+
+	if strings.HasPrefix(p.s[p.pos:], "?[") {
+		filterEnd, err := p.scanKey(p.pos+1, ",)")
+		if err != nil {
+			return nil, err
+		}
+
+		n.Filter = p.s[p.pos+2 : filterEnd-1]
+		p.pos = filterEnd
+	}
+
+	return n, nil
+}
@@ -2,6 +2,10 @@ package zabbix
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // History represents a Zabbix History returned from the Zabbix API.
@@ -17,30 +21,78 @@ type History struct {
 	// Ns is the nanoseconds when the value was received.
 	Ns int
 
+	// ValueType is the type of the History value and must be one of the
+	// ItemValueType constants. It is the History type that was requested in
+	// the HistoryGetParams which returned this History.
+	ValueType int
+
 	// Value is the received value.
-	// Possible types: 0 - float; 1 - character; 2 - log; 3 - int; 4 - text;
+	//
+	// Use Float or Unsigned to parse numeric values according to ValueType.
 	Value string
 
 	// LogEventID is the Windows event log entry ID.
+	//
+	// LogEventID is only populated if ValueType is ItemValueTypeLog.
 	LogEventID int
 
 	// Severity is the Windows event log entry level.
+	//
+	// Severity is only populated if ValueType is ItemValueTypeLog.
 	Severity int
 
 	// Source is the Windows event log entry source.
+	//
+	// Source is only populated if ValueType is ItemValueTypeLog.
 	Source string
 
 	// Timestamp is the Windows event log entry time.
+	//
+	// Timestamp is only populated if ValueType is ItemValueTypeLog.
 	Timestamp string
 }
 
+// Time returns the time when the value was received.
+func (c *History) Time() time.Time {
+	return time.Unix(int64(c.Clock), int64(c.Ns))
+}
+
+// IsNumeric returns true if the History value is of type ItemValueTypeFloat
+// or ItemValueTypeUnsigned.
+func (c *History) IsNumeric() bool {
+	return c.ValueType == ItemValueTypeFloat || c.ValueType == ItemValueTypeUnsigned
+}
+
+// Float returns the History value as a float. Unsigned values are converted.
+//
+// An error is returned if the History value is not numeric.
+func (c *History) Float() (float64, error) {
+	if !c.IsNumeric() {
+		return 0, fmt.Errorf("History value type %d is not numeric", c.ValueType)
+	}
+
+	return strconv.ParseFloat(c.Value, 64)
+}
+
+// Unsigned returns the History value as an unsigned integer.
+//
+// An error is returned if the History value type is not
+// ItemValueTypeUnsigned.
+func (c *History) Unsigned() (uint64, error) {
+	if c.ValueType != ItemValueTypeUnsigned {
+		return 0, fmt.Errorf("History value type %d is not unsigned", c.ValueType)
+	}
+
+	return strconv.ParseUint(c.Value, 10, 64)
+}
+
 type HistoryGetParams struct {
 	GetParameters
 
 	// History object types to return
-	// Possible values: 0 - numeric float, 1 - character, 2 - log,
-	// 3 - numeric signed, 4, text
-	// Default: 3
+	//
+	// History must be one of the ItemValueType constants. Use
+	// GetItemHistories to select the type of each Item automatically.
 	History int `json:"history"`
 
 	// HistoryIDs filters search results to histories with the given History ID's.
@@ -78,8 +130,182 @@ func (c *Session) GetHistories(params HistoryGetParams) ([]History, error) {
 		if err != nil {
 			return nil, fmt.Errorf("Error mapping History %d in response: %v", i, err)
 		}
+		history.ValueType = params.History
 		out[i] = *history
 	}
 
 	return out, nil
 }
+
+// itemValueTypes queries the Zabbix API for the value type of the given
+// Items and returns the Item IDs grouped by value type.
+func (c *Session) itemValueTypes(itemIDs []string) (map[int][]string, error) {
+	items, err := c.GetItems(ItemGetParams{
+		GetParameters: GetParameters{
+			OutputFields: SelectFields{"itemid", "value_type"},
+		},
+		ItemIDs:  itemIDs,
+		WebItems: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[int][]string)
+	for _, item := range items {
+		out[item.LastValueType] = append(out[item.LastValueType], strconv.Itoa(item.ItemID))
+	}
+
+	return out, nil
+}
+
+// GetItemHistories queries the Zabbix API for Histories of the Items given in
+// HistoryGetParams.ItemIDs. The History type of each Item is selected from
+// the Item's value type, so the given Items may be of mixed types and
+// HistoryGetParams.History is ignored.
+//
+// If the Items are of more than one type, one query is made for each type
+// and the results are merged in order of their Clock and Ns, according to
+// HistoryGetParams.SortOrder. HistoryGetParams.ResultLimit applies to the
+// merged results.
+//
+// ErrNotFound is returned if the given Items are not found or the search
+// result set is empty.
+// An error is returned if a transport, parsing or API error occurs.
+func (c *Session) GetItemHistories(params HistoryGetParams) ([]History, error) {
+	if len(params.ItemIDs) == 0 {
+		return nil, fmt.Errorf("No Item IDs given")
+	}
+
+	types, err := c.itemValueTypes(params.ItemIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]History, 0)
+	for _, valueType := range []int{
+		ItemValueTypeFloat,
+		ItemValueTypeCharacter,
+		ItemValueTypeLog,
+		ItemValueTypeUnsigned,
+		ItemValueTypeText,
+	} {
+		if len(types[valueType]) == 0 {
+			continue
+		}
+
+		p := params
+		p.History = valueType
+		p.ItemIDs = types[valueType]
+		histories, err := c.GetHistories(p)
+		if err == ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, histories...)
+	}
+
+	if len(out) == 0 {
+		return nil, ErrNotFound
+	}
+
+	if len(types) > 1 {
+		descending := strings.ToUpper(params.SortOrder) == SortOrderDescending
+		sort.SliceStable(out, func(i, j int) bool {
+			a, b := out[i], out[j]
+			if descending {
+				a, b = b, a
+			}
+
+			if a.Clock != b.Clock {
+				return a.Clock < b.Clock
+			}
+			return a.Ns < b.Ns
+		})
+
+		if params.ResultLimit > 0 && len(out) > params.ResultLimit {
+			out = out[:params.ResultLimit]
+		}
+	}
+
+	return out, nil
+}
+
+// StreamHistories queries the Zabbix API for Histories matching the given
+// search parameters in pages of the given size and calls fn for each History
+// in order of time, so that long time ranges may be processed without holding
+// all Histories in memory.
+//
+// Pages are requested in ascending order of clock, starting each page at the
+// clock of the last History of the previous page. The given SortField,
+// SortOrder and ResultLimit are ignored.
+//
+// If fn returns an error, streaming stops and the error is returned.
+// An error is returned if a transport, parsing or API error occurs, or if more
+// Histories than the page size were received in a single second.
+func (c *Session) StreamHistories(params HistoryGetParams, pageSize int, fn func(History) error) error {
+	if pageSize <= 0 {
+		return fmt.Errorf("Invalid History page size: %d", pageSize)
+	}
+
+	type historyKey struct{ ItemID, Clock, Ns int }
+
+	params.SortField = []string{"clock"}
+	params.SortOrder = SortOrderAscending
+	params.ResultLimit = pageSize
+
+	// seen holds the Histories of the last clock of the previous page, which
+	// are returned again at the start of the next page
+	seen := make(map[historyKey]bool)
+	for {
+		histories, err := c.GetHistories(params)
+		if err == ErrNotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		last := histories[len(histories)-1].Clock
+		next := make(map[historyKey]bool)
+		sent := 0
+		for _, history := range histories {
+			key := historyKey{history.ItemID, history.Clock, history.Ns}
+			if history.Clock == last {
+				next[key] = true
+			}
+
+			if seen[key] {
+				continue
+			}
+
+			if err := fn(history); err != nil {
+				return err
+			}
+			sent++
+		}
+
+		if len(histories) < pageSize {
+			return nil
+		}
+
+		if sent == 0 {
+			return fmt.Errorf("More than %d Histories received at %d; increase the page size", pageSize, last)
+		}
+
+		seen = next
+		params.TimeFrom = float64(last)
+	}
+}
+
+// CountHistories queries the Zabbix API for the number of Histories matching
+// the given search parameters.
+//
+// An error is returned if a transport, parsing or API error occurs.
+func (c *Session) CountHistories(params HistoryGetParams) (int, error) {
+	params.CountOutput = true
+	return c.count("history.get", params)
+}
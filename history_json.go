@@ -48,7 +48,7 @@ func (c *jHistory) History() (*History, error) {
 	}
 
 	if c.Severity != "" {
-		history.LogEventID, err = strconv.Atoi(c.Severity)
+		history.Severity, err = strconv.Atoi(c.Severity)
 		if err != nil {
 			return nil, fmt.Errorf("Error parsing History Severity: %v", err)
 		}
@@ -0,0 +1,47 @@
+package zabbix
+
+import (
+	"testing"
+)
+
+func TestHistoryLogFields(t *testing.T) {
+	jhistory := jHistory{
+		ItemID:     "1",
+		Clock:      "1500000000",
+		Ns:         "0",
+		Value:      "Service started",
+		LogEventID: "7036",
+		Severity:   "1",
+		Source:     "Service Control Manager",
+	}
+
+	history, err := jhistory.History()
+	if err != nil {
+		t.Fatalf("Error mapping History: %v", err)
+	}
+
+	if history.LogEventID != 7036 || history.Severity != 1 {
+		t.Errorf("Expected LogEventID 7036 and Severity 1, got %d and %d", history.LogEventID, history.Severity)
+	}
+}
+
+func TestHistoryValues(t *testing.T) {
+	history := History{ValueType: ItemValueTypeUnsigned, Value: "18446744073709551615"}
+	if v, err := history.Unsigned(); err != nil || v != 18446744073709551615 {
+		t.Errorf("Expected maximum unsigned value, got %d, %v", v, err)
+	}
+
+	history = History{ValueType: ItemValueTypeFloat, Value: "0.25"}
+	if v, err := history.Float(); err != nil || v != 0.25 {
+		t.Errorf("Expected 0.25, got %v, %v", v, err)
+	}
+
+	if _, err := history.Unsigned(); err == nil {
+		t.Errorf("Expected an error reading a float History as unsigned")
+	}
+
+	history = History{ValueType: ItemValueTypeText, Value: "text"}
+	if _, err := history.Float(); err == nil {
+		t.Errorf("Expected an error reading a text History as a float")
+	}
+}
@@ -1,5 +1,9 @@
 package zabbix
 
+import (
+	"encoding/json"
+)
+
 const (
 	// HostSourceDefault indicates that a Host was created in the normal way.
 	HostSourceDefault = 0
@@ -81,7 +85,7 @@ type Host struct {
 	Description string `json:"description"`
 
 	// Inventory mode
-	InventoryMode int `json:"inventory_mode"`
+	InventoryMode int `json:"inventory_mode,string,omitempty"`
 
 	// HostID of the proxy managing this host
 	ProxyHostID string `json:"proxy_hostid"`
@@ -96,6 +100,62 @@ type Host struct {
 	TLSSubject     string `json:"tls_subject"`
 	TLSPSKIdentity string `json:"tls_psk_identity"`
 	TLSPSK         string `json:"tls_psk"`
+
+	// Interfaces contains all interfaces of the Host.
+	//
+	// Interfaces is only populated if HostGetParams.SelectInterfaces is given
+	// in the query parameters that returned this Host.
+	Interfaces []HostInterface `json:"interfaces,omitempty"`
+
+	// ParentTemplates contains the Templates linked directly to the Host.
+	//
+	// ParentTemplates is only populated if HostGetParams.SelectParentTemplates
+	// is given in the query parameters that returned this Host.
+	ParentTemplates []Template `json:"parentTemplates,omitempty"`
+
+	// Tags contains all tags assigned to the Host.
+	//
+	// Tags is only populated if HostGetParams.SelectTags is given in the query
+	// parameters that returned this Host.
+	Tags []HostTag `json:"tags,omitempty"`
+
+	// Inventory contains the inventory fields of the Host.
+	//
+	// Inventory is only populated if HostGetParams.SelectInventory is given in
+	// the query parameters that returned this Host.
+	Inventory HostInventory `json:"inventory,omitempty"`
+}
+
+// HostTag represents a tag assigned to a Host.
+type HostTag struct {
+	// Tag is the name of the tag.
+	Tag string `json:"tag"`
+
+	// Value is the value of the tag.
+	Value string `json:"value"`
+}
+
+// HostInventory contains the inventory fields of a Host, keyed by the name of
+// each inventory property (e.g. "os" or "serialno_a").
+//
+// See: https://www.zabbix.com/documentation/current/manual/api/reference/host/object#host_inventory
+type HostInventory map[string]string
+
+// UnmarshalJSON decodes a HostInventory, accepting the empty JSON array returned
+// by the Zabbix API for hosts with inventory disabled.
+func (c *HostInventory) UnmarshalJSON(data []byte) error {
+	if string(data) == "[]" {
+		*c = nil
+		return nil
+	}
+
+	inventory := make(map[string]string)
+	if err := json.Unmarshal(data, &inventory); err != nil {
+		return err
+	}
+
+	*c = inventory
+	return nil
 }
 
 // HostGetParams represent the parameters for a `host.get` API call.
@@ -184,6 +244,10 @@ type HostGetParams struct {
 	SelectParentTemplates SelectQuery `json:"selectParentTemplates,omitempty"`
 	SelectScreens         SelectQuery `json:"selectScreens,omitempty"`
 	SelectTriggers        SelectQuery `json:"selectTriggers,omitempty"`
+
+	// SelectTags causes the tags assigned to each Host to be attached in the
+	// search results.
+	SelectTags SelectQuery `json:"selectTags,omitempty"`
 }
 
 // GetHosts queries the Zabbix API for Hosts matching the given search
@@ -204,3 +268,273 @@ func (c *Session) GetHosts(params HostGetParams) ([]Host, error) {
 
 	return hosts, nil
 }
+
+// CountHosts queries the Zabbix API for the number of Hosts matching the given
+// search parameters.
+//
+// An error is returned if a transport, parsing or API error occurs.
+func (c *Session) CountHosts(params HostGetParams) (int, error) {
+	params.CountOutput = true
+	return c.count("host.get", params)
+}
+
+// HostResponse represent host action response body
+type HostResponse struct {
+	HostIDs []string `json:"hostids"`
+}
+
+// HostCreateParams represent the parameters for a `host.create` API call.
+//
+// See: https://www.zabbix.com/documentation/current/manual/api/reference/host/create
+type HostCreateParams struct {
+	// Hostname is the technical name of the Host.
+	Hostname string `json:"host"`
+
+	// DisplayName is the visible name of the Host.
+	DisplayName string `json:"name,omitempty"`
+
+	// Description of the Host.
+	Description string `json:"description,omitempty"`
+
+	// Status of the Host. Must be one of the HostStatus constants.
+	Status int `json:"status"`
+
+	// ProxyHostID is the ID of the proxy that is used to monitor the Host.
+	ProxyHostID string `json:"proxy_hostid,omitempty"`
+
+	// Groups are the Host Groups to add the Host to. Only GroupID must be set
+	// for each group.
+	Groups []Hostgroup `json:"groups"`
+
+	// Templates are the Templates to link to the Host. Only TemplateID must be
+	// set for each template.
+	Templates []Template `json:"templates,omitempty"`
+
+	// Interfaces are the interfaces to create for the Host.
+	Interfaces []HostInterface `json:"interfaces,omitempty"`
+
+	// Macros are the user macros to create for the Host.
+	Macros []HostMacro `json:"macros,omitempty"`
+
+	// Tags are the tags to assign to the Host.
+	Tags []HostTag `json:"tags,omitempty"`
+
+	// InventoryMode is the inventory mode of the Host and must be one of the
+	// HostInventoryMode constants. Defaults to HostInventoryModeDisabled if
+	// nil.
+	InventoryMode *int `json:"inventory_mode,omitempty"`
+
+	// Inventory are the inventory properties of the Host.
+	Inventory HostInventory `json:"inventory,omitempty"`
+
+	// TLSConnect determines how the server or proxy connects to the Host and
+	// must be one of the HostTLSConnect constants.
+	TLSConnect int `json:"tls_connect,omitempty"`
+
+	// TLSAccept determines which connections are accepted from the Host and is
+	// a bitmask of the HostTLSConnect constants.
+	TLSAccept int `json:"tls_accept,omitempty"`
+
+	TLSIssuer      string `json:"tls_issuer,omitempty"`
+	TLSSubject     string `json:"tls_subject,omitempty"`
+	TLSPSKIdentity string `json:"tls_psk_identity,omitempty"`
+	TLSPSK         string `json:"tls_psk,omitempty"`
+}
+
+// HostUpdateParams represent the parameters for a `host.update` API call.
+//
+// Only the fields that are set are updated. Groups, Templates, Interfaces,
+// Macros and Tags replace the existing values of the Host when given.
+//
+// See: https://www.zabbix.com/documentation/current/manual/api/reference/host/update
+type HostUpdateParams struct {
+	// HostID is the ID of the Host to update.
+	HostID string `json:"hostid"`
+
+	Hostname    string `json:"host,omitempty"`
+	DisplayName string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	// Status of the Host. Must be one of the HostStatus constants.
+	Status *int `json:"status,omitempty"`
+
+	// ProxyHostID is the ID of the proxy that is used to monitor the Host. Set
+	// to "0" to monitor the Host by the server.
+	ProxyHostID string `json:"proxy_hostid,omitempty"`
+
+	Groups     []Hostgroup     `json:"groups,omitempty"`
+	Templates  []Template      `json:"templates,omitempty"`
+	Interfaces []HostInterface `json:"interfaces,omitempty"`
+	Macros     []HostMacro     `json:"macros,omitempty"`
+	Tags       []HostTag       `json:"tags,omitempty"`
+
+	// TemplatesClear are the Templates to unlink and clear from the Host.
+	TemplatesClear []Template `json:"templates_clear,omitempty"`
+
+	// InventoryMode is the inventory mode of the Host and must be one of the
+	// HostInventoryMode constants.
+	InventoryMode *int `json:"inventory_mode,omitempty"`
+
+	Inventory HostInventory `json:"inventory,omitempty"`
+
+	TLSConnect     int    `json:"tls_connect,omitempty"`
+	TLSAccept      int    `json:"tls_accept,omitempty"`
+	TLSIssuer      string `json:"tls_issuer,omitempty"`
+	TLSSubject     string `json:"tls_subject,omitempty"`
+	TLSPSKIdentity string `json:"tls_psk_identity,omitempty"`
+	TLSPSK         string `json:"tls_psk,omitempty"`
+}
+
+// HostMassAddParams represent the parameters for a `host.massadd` API call.
+//
+// See: https://www.zabbix.com/documentation/current/manual/api/reference/host/massadd
+type HostMassAddParams struct {
+	// HostIDs are the IDs of the Hosts to update.
+	HostIDs []string `json:"-"`
+
+	Groups     []Hostgroup     `json:"groups,omitempty"`
+	Templates  []Template      `json:"templates,omitempty"`
+	Interfaces []HostInterface `json:"interfaces,omitempty"`
+	Macros     []HostMacro     `json:"macros,omitempty"`
+}
+
+// MarshalJSON encodes HostMassAddParams as expected by the Zabbix API.
+func (c HostMassAddParams) MarshalJSON() ([]byte, error) {
+	type params HostMassAddParams
+	return json.Marshal(struct {
+		params
+		Hosts []map[string]string `json:"hosts"`
+	}{params(c), objectIDs("hostid", c.HostIDs)})
+}
+
+// HostMassUpdateParams represent the parameters for a `host.massupdate` API
+// call.
+//
+// Groups, Templates, Interfaces and Macros replace the existing values of each
+// Host when given.
+//
+// See: https://www.zabbix.com/documentation/current/manual/api/reference/host/massupdate
+type HostMassUpdateParams struct {
+	// HostIDs are the IDs of the Hosts to update.
+	HostIDs []string `json:"-"`
+
+	Description string `json:"description,omitempty"`
+	Status      *int   `json:"status,omitempty"`
+	ProxyHostID string `json:"proxy_hostid,omitempty"`
+
+	Groups         []Hostgroup     `json:"groups,omitempty"`
+	Templates      []Template      `json:"templates,omitempty"`
+	TemplatesClear []Template      `json:"templates_clear,omitempty"`
+	Interfaces     []HostInterface `json:"interfaces,omitempty"`
+	Macros         []HostMacro     `json:"macros,omitempty"`
+
+	InventoryMode *int          `json:"inventory_mode,omitempty"`
+	Inventory     HostInventory `json:"inventory,omitempty"`
+
+	TLSConnect     int    `json:"tls_connect,omitempty"`
+	TLSAccept      int    `json:"tls_accept,omitempty"`
+	TLSIssuer      string `json:"tls_issuer,omitempty"`
+	TLSSubject     string `json:"tls_subject,omitempty"`
+	TLSPSKIdentity string `json:"tls_psk_identity,omitempty"`
+	TLSPSK         string `json:"tls_psk,omitempty"`
+}
+
+// MarshalJSON encodes HostMassUpdateParams as expected by the Zabbix API.
+func (c HostMassUpdateParams) MarshalJSON() ([]byte, error) {
+	type params HostMassUpdateParams
+	return json.Marshal(struct {
+		params
+		Hosts []map[string]string `json:"hosts"`
+	}{params(c), objectIDs("hostid", c.HostIDs)})
+}
+
+// HostMassRemoveParams represent the parameters for a `host.massremove` API
+// call.
+//
+// See: https://www.zabbix.com/documentation/current/manual/api/reference/host/massremove
+type HostMassRemoveParams struct {
+	// HostIDs are the IDs of the Hosts to update.
+	HostIDs []string `json:"hostids"`
+
+	// GroupIDs are the IDs of the Host Groups to remove the Hosts from.
+	GroupIDs []string `json:"groupids,omitempty"`
+
+	// Interfaces are the interfaces to remove from the Hosts.
+	Interfaces []HostInterface `json:"interfaces,omitempty"`
+
+	// Macros are the names of the user macros to delete from the Hosts.
+	Macros []string `json:"macros,omitempty"`
+
+	// TemplateIDs are the IDs of the Templates to unlink from the Hosts.
+	TemplateIDs []string `json:"templateids,omitempty"`
+
+	// TemplateIDsClear are the IDs of the Templates to unlink and clear from
+	// the Hosts.
+	TemplateIDsClear []string `json:"templateids_clear,omitempty"`
+}
+
+// hostAction calls the given host write method and returns the affected Host
+// IDs.
+func (c *Session) hostAction(method string, params interface{}) ([]string, error) {
+	var body HostResponse
+
+	if err := c.Get(method, params, &body); err != nil {
+		return nil, err
+	}
+
+	if len(body.HostIDs) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return body.HostIDs, nil
+}
+
+// CreateHosts creates a single or multiple new hosts.
+// Returns a list of host id(s) of created host(s).
+//
+// Zabbix API docs: https://www.zabbix.com/documentation/current/manual/api/reference/host/create
+func (c *Session) CreateHosts(hosts ...HostCreateParams) (hostIDs []string, err error) {
+	return c.hostAction("host.create", hosts)
+}
+
+// UpdateHosts updates a single or multiple existing hosts.
+// Returns a list of updated host id(s).
+//
+// Zabbix API docs: https://www.zabbix.com/documentation/current/manual/api/reference/host/update
+func (c *Session) UpdateHosts(hosts ...HostUpdateParams) (hostIDs []string, err error) {
+	return c.hostAction("host.update", hosts)
+}
+
+// DeleteHosts deletes a single or multiple hosts.
+// Returns a list of deleted host id(s).
+//
+// Zabbix API docs: https://www.zabbix.com/documentation/current/manual/api/reference/host/delete
+func (c *Session) DeleteHosts(hostIDs ...string) ([]string, error) {
+	return c.hostAction("host.delete", hostIDs)
+}
+
+// MassAddHosts adds groups, templates, interfaces and macros to multiple hosts.
+// Returns a list of updated host id(s).
+//
+// Zabbix API docs: https://www.zabbix.com/documentation/current/manual/api/reference/host/massadd
+func (c *Session) MassAddHosts(params HostMassAddParams) (hostIDs []string, err error) {
+	return c.hostAction("host.massadd", params)
+}
+
+// MassUpdateHosts replaces or removes related objects and updates properties
+// on multiple hosts.
+// Returns a list of updated host id(s).
+//
+// Zabbix API docs: https://www.zabbix.com/documentation/current/manual/api/reference/host/massupdate
+func (c *Session) MassUpdateHosts(params HostMassUpdateParams) (hostIDs []string, err error) {
+	return c.hostAction("host.massupdate", params)
+}
+
+// MassRemoveHosts removes groups, templates, interfaces and macros from
+// multiple hosts.
+// Returns a list of updated host id(s).
+//
+// Zabbix API docs: https://www.zabbix.com/documentation/current/manual/api/reference/host/massremove
+func (c *Session) MassRemoveHosts(params HostMassRemoveParams) (hostIDs []string, err error) {
+	return c.hostAction("host.massremove", params)
+}
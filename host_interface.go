@@ -1,5 +1,9 @@
 package zabbix
 
+import (
+	"encoding/json"
+)
+
 const (
 	// HostInterfaceAvailabilityUnknown Unknown availability of host, never has come online
 	HostInterfaceAvailabilityUnknown = 0
@@ -23,7 +27,7 @@ const (
 // See https://www.zabbix.com/documentation/current/manual/api/reference/hostinterface/object#host_interface
 type HostInterface struct {
 	// (readonly) ID of the interface.
-	InterfaceID string `json:"interfaceid"`
+	InterfaceID string `json:"interfaceid,omitempty"`
 
 	// (readonly) Availability of host interface.
 	Available int `json:"available,string,omitempty"`
@@ -41,7 +45,7 @@ type HostInterface struct {
 	ErrorsFrom *UnixTimestamp `json:"errors_from,string,omitempty"`
 
 	// ID of the host the interface belongs to.
-	HostID string `json:"hostid"`
+	HostID string `json:"hostid,omitempty"`
 
 	// Whether the interface is used as default on the host. Only one interface of some type can be set as default on a host.
 	Main ZBXBoolean `json:"main,string"`
@@ -51,6 +55,62 @@ type HostInterface struct {
 
 	// Whether the connection should be made via IP.
 	UseIP ZBXBoolean `json:"useip,string"`
+
+	// Port number used by the interface. Can contain user macros.
+	Port string `json:"port"`
+
+	// Additional details for SNMP interfaces.
+	//
+	// Details is only supported since Zabbix 5.0 and only for interfaces of
+	// type HostInterfaceTypeSNMP.
+	Details *HostInterfaceDetails `json:"details,omitempty"`
+}
+
+// HostInterfaceDetails contains the SNMP settings of a host interface.
+//
+// See https://www.zabbix.com/documentation/current/manual/api/reference/hostinterface/object#details_tag
+type HostInterfaceDetails struct {
+	// SNMP interface version.
+	Version int `json:"version,string,omitempty"`
+
+	// Whether to use bulk SNMP requests.
+	Bulk ZBXBoolean `json:"bulk,string"`
+
+	// SNMP community. Used only by SNMPv1 and SNMPv2 interfaces.
+	Community string `json:"community,omitempty"`
+
+	// SNMPv3 security name.
+	SecurityName string `json:"securityname,omitempty"`
+
+	// SNMPv3 security level.
+	SecurityLevel int `json:"securitylevel,string,omitempty"`
+
+	// SNMPv3 authentication passphrase.
+	AuthPassphrase string `json:"authpassphrase,omitempty"`
+
+	// SNMPv3 privacy passphrase.
+	PrivPassphrase string `json:"privpassphrase,omitempty"`
+
+	// SNMPv3 authentication protocol.
+	AuthProtocol int `json:"authprotocol,string,omitempty"`
+
+	// SNMPv3 privacy protocol.
+	PrivProtocol int `json:"privprotocol,string,omitempty"`
+
+	// SNMPv3 context name.
+	ContextName string `json:"contextname,omitempty"`
+}
+
+// UnmarshalJSON decodes HostInterfaceDetails, accepting the empty JSON array
+// returned by the Zabbix API for interfaces which have no details.
+func (c *HostInterfaceDetails) UnmarshalJSON(data []byte) error {
+	if string(data) == "[]" {
+		*c = HostInterfaceDetails{}
+		return nil
+	}
+
+	type details HostInterfaceDetails
+	return json.Unmarshal(data, (*details)(c))
 }
 
 type HostInterfaceGetParams struct {
@@ -87,3 +147,12 @@ func (c *Session) GetHostInterfaces(params HostInterfaceGetParams) ([]HostInterf
 
 	return hostInterfaces, nil
 }
+
+// CountHostInterfaces queries the Zabbix API for the number of Host interfaces
+// matching the given search parameters.
+//
+// An error is returned if a transport, parsing or API error occurs.
+func (c *Session) CountHostInterfaces(params HostInterfaceGetParams) (int, error) {
+	params.CountOutput = true
+	return c.count("hostinterface.get", params)
+}
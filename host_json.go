@@ -14,6 +14,8 @@ type jHost struct {
 	Name     string      `json:"name,omitempty"`
 	Macros   []HostMacro `json:"macros,omitempty"`
 	Groups   []Hostgroup `json:"groups,omitempty"`
+
+	ParentTemplates []Template `json:"parentTemplates,omitempty"`
 }
 
 // Host returns a native Go Host struct mapped from the given JSON Host data.
@@ -26,6 +28,7 @@ func (c *jHost) Host() (*Host, error) {
 	host.DisplayName = c.Name
 	host.Macros = c.Macros
 	host.Groups = c.Groups
+	host.ParentTemplates = c.ParentTemplates
 	/*
 		host.Source, err = strconv.Atoi(c.Flags)
 		if err != nil {
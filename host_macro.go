@@ -1,16 +1,36 @@
 package zabbix
 
+const (
+	// HostMacroTypeText indicates that a Host Macro has a plain text value.
+	HostMacroTypeText = 0
+
+	// HostMacroTypeSecret indicates that a Host Macro has a secret value which
+	// is not returned by the API.
+	HostMacroTypeSecret = 1
+
+	// HostMacroTypeVault indicates that a Host Macro value is a path to a
+	// secret in a vault.
+	HostMacroTypeVault = 2
+)
+
 // HostMacro represents a Zabbix Host Macro returned from the Zabbix API.
 type HostMacro struct {
 	// HostMacroID is the unique ID of the Host Macro.
-	HostMacroID string `json:"hostmacroid"`
+	HostMacroID string `json:"hostmacroid,omitempty"`
 
 	// HostID is the ID of the Host which owns this Macro.
-	HostID string `json:"hostid"`
+	HostID string `json:"hostid,omitempty"`
 
 	// Macro is the name of the Macro (e.g. '{HOST.MACRO}').
 	Macro string `json:"macro"`
 
 	// Value is the value of the Macro.
 	Value string `json:"value"`
+
+	// Type is the type of the Macro and must be one of the HostMacroType
+	// constants.
+	Type int `json:"type,string,omitempty"`
+
+	// Description is the description of the Macro.
+	Description string `json:"description,omitempty"`
 }
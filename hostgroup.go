@@ -1,7 +1,9 @@
 package zabbix
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
 )
 
 const (
@@ -20,11 +22,17 @@ const (
 
 // Hostgroup represents a Zabbix Hostgroup Object returned from the Zabbix API (see zabbix documentation).
 type Hostgroup struct {
-	GroupID  string `json:"groupid"`
-	Name     string `json:"name"`
-	Flags    string `json:"flags"`
-	Internal string `json:"internal"`
+	GroupID  string `json:"groupid,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Flags    string `json:"flags,omitempty"`
+	Internal string `json:"internal,omitempty"`
 	Hosts    []Host `json:"hosts,omitempty"`
+
+	// Templates contains the Templates that belong to the Hostgroup.
+	//
+	// Since Zabbix 6.2, Templates are members of a TemplateGroup instead of a
+	// Hostgroup, and GetTemplateGroups must be used to select them.
+	Templates []Template `json:"templates,omitempty"`
 }
 
 // HostgroupGetParams represent the parameters for a `hostgroup.get` API call (see zabbix documentation).
@@ -50,9 +58,15 @@ type HostgroupGetParams struct {
 	RealHosts int `json:"real_hosts,omitempty"`
 
 	// Return only host groups that contain templates
+	//
+	// Since Zabbix 6.2, GetHostgroups returns the matching template groups
+	// instead.
 	TemplatedHosts int `json:"templated_hosts,omitempty"`
 
 	// Return only host groups that contain the given templates
+	//
+	// Since Zabbix 6.2, GetHostgroups returns the matching template groups
+	// instead.
 	TemplateIDs []string `json:"templateids,omitempty"`
 
 	// Return only host groups that contain hosts or templates with the given triggers
@@ -110,9 +124,26 @@ type HostgroupGetParams struct {
 // GetHostgroups queries the Zabbix API for Hostgroups matching the given search
 // parameters.
 //
+// Since Zabbix 6.2, templates belong to template groups instead of host groups.
+// If TemplateIDs or TemplatedHosts are given for a Zabbix 6.2 server or later,
+// the equivalent `templategroup.get` call is made and mapped into the results.
+// Otherwise, ErrUnsupportedVersion is returned for SelectTemplates, as host
+// groups have no templates; use GetTemplateGroups instead.
+//
 // ErrEventNotFound is returned if the search result set is empty.
 // An error is returned if a transport, parsing or API error occurs.
 func (c *Session) GetHostgroups(params HostgroupGetParams) ([]Hostgroup, error) {
+	if params.TemplatedHosts != 0 || len(params.TemplateIDs) > 0 || params.SelectTemplates != nil {
+		split, err := c.VersionAtLeast(6, 2)
+		if err != nil {
+			return nil, err
+		}
+
+		if split {
+			return c.getHostgroupsWithTemplateGroups(params)
+		}
+	}
+
 	hostgroups := make([]jHostgroup, 0)
 	err := c.Get("hostgroup.get", params, &hostgroups)
 	if err != nil {
@@ -136,3 +167,395 @@ func (c *Session) GetHostgroups(params HostgroupGetParams) ([]Hostgroup, error)
 
 	return out, nil
 }
+
+// hostOnlyHostgroupParam returns the name of the first given parameter of
+// `hostgroup.get` which only applies to hosts, and cannot be mapped to
+// `templategroup.get`, or an empty string if there is none.
+func hostOnlyHostgroupParam(params *HostgroupGetParams) string {
+	for _, p := range []struct {
+		name string
+		set  bool
+	}{
+		{"graphids", len(params.GraphIDs) > 0},
+		{"hostids", len(params.HostIDs) > 0},
+		{"maintenanceids", len(params.MaintenanceIDs) > 0},
+		{"monitored_hosts", params.MonitoredHosts != 0},
+		{"real_hosts", params.RealHosts != 0},
+		{"triggerids", len(params.TriggerIDs) > 0},
+		{"with_applications", params.WithApplications != 0},
+		{"with_graphs", params.WithGraphs != 0},
+		{"with_httptests", params.WithHttptests != 0},
+		{"with_monitored_httptests", params.WithMonitoredHttptests != 0},
+		{"with_monitored_items", params.WithMonitoredItems != 0},
+		{"with_monitored_triggers", params.WithMonitoredTriggers != 0},
+		{"with_simple_graph_items", params.WithSimpleGraphItems != 0},
+		{"with_triggers", params.WithTriggers != 0},
+		{"selectDiscoveryRule", params.SelectDiscoveryRule != nil},
+		{"selectGroupDiscovery", params.SelectGroupDiscovery != nil},
+		{"selectHosts", params.SelectHosts != nil},
+	} {
+		if p.set {
+			return p.name
+		}
+	}
+
+	return ""
+}
+
+// getHostgroupsWithTemplateGroups emulates the template parameters of
+// `hostgroup.get` for Zabbix 6.2 and later using `templategroup.get`.
+func (c *Session) getHostgroupsWithTemplateGroups(params HostgroupGetParams) ([]Hostgroup, error) {
+	// host groups and template groups are independent since v6.2, so only
+	// groups filtered by templates, which are template groups, are emulated
+	if params.TemplatedHosts == 0 && len(params.TemplateIDs) == 0 {
+		return nil, ErrUnsupportedVersion
+	}
+
+	if name := hostOnlyHostgroupParam(&params); name != "" {
+		return nil, fmt.Errorf("Hostgroup parameter %s cannot be combined with template parameters since Zabbix 6.2", name)
+	}
+
+	getParameters := params.GetParameters
+	if len(params.Sortfield) > 0 {
+		getParameters.SortField = params.Sortfield
+	}
+
+	templateGroups, err := c.GetTemplateGroups(TemplateGroupGetParams{
+		GetParameters:   getParameters,
+		GroupIDs:        params.GroupIDs,
+		TemplateIDs:     params.TemplateIDs,
+		WithTemplates:   params.TemplatedHosts != 0 || params.WithHostsAndTemplates != 0,
+		WithItems:       params.WithItems != 0,
+		SelectTemplates: params.SelectTemplates,
+		LimitSelects:    params.LimitSelects,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]Hostgroup, len(templateGroups))
+	for i, templateGroup := range templateGroups {
+		out[i] = templateGroup.Hostgroup()
+	}
+
+	return out, nil
+}
+
+// CountHostgroups queries the Zabbix API for the number of Hostgroups matching
+// the given search parameters.
+//
+// An error is returned if a transport, parsing or API error occurs.
+func (c *Session) CountHostgroups(params HostgroupGetParams) (int, error) {
+	params.CountOutput = true
+	return c.count("hostgroup.get", params)
+}
+
+// HostgroupResponse represent hostgroup action response body
+type HostgroupResponse struct {
+	GroupIDs []string `json:"groupids"`
+}
+
+// HostgroupMassParams represent the parameters for the `hostgroup.massadd` and
+// `hostgroup.massupdate` API calls.
+//
+// For `hostgroup.massupdate`, the given Hosts and Templates replace the
+// existing members of each Hostgroup.
+type HostgroupMassParams struct {
+	// GroupIDs are the IDs of the Hostgroups to update.
+	GroupIDs []string `json:"-"`
+
+	// HostIDs are the IDs of the Hosts to add to the Hostgroups.
+	HostIDs []string `json:"-"`
+
+	// TemplateIDs are the IDs of the Templates to add to the Hostgroups.
+	//
+	// Since Zabbix 6.2, the Templates are added to the Template Groups with
+	// the given GroupIDs instead.
+	TemplateIDs []string `json:"-"`
+}
+
+// MarshalJSON encodes HostgroupMassParams as expected by the Zabbix API.
+func (c HostgroupMassParams) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Groups    []map[string]string `json:"groups"`
+		Hosts     []map[string]string `json:"hosts,omitempty"`
+		Templates []map[string]string `json:"templates,omitempty"`
+	}{
+		objectIDs("groupid", c.GroupIDs),
+		objectIDs("hostid", c.HostIDs),
+		objectIDs("templateid", c.TemplateIDs),
+	})
+}
+
+// HostgroupMassRemoveParams represent the parameters for a
+// `hostgroup.massremove` API call.
+type HostgroupMassRemoveParams struct {
+	// GroupIDs are the IDs of the Hostgroups to update.
+	GroupIDs []string `json:"groupids"`
+
+	// HostIDs are the IDs of the Hosts to remove from the Hostgroups.
+	HostIDs []string `json:"hostids,omitempty"`
+
+	// TemplateIDs are the IDs of the Templates to remove from the Hostgroups.
+	//
+	// Since Zabbix 6.2, the Templates are removed from the Template Groups
+	// with the given GroupIDs instead.
+	TemplateIDs []string `json:"templateids,omitempty"`
+}
+
+// HostgroupPropagateParams represent the parameters for a
+// `hostgroup.propagate` API call.
+type HostgroupPropagateParams struct {
+	// GroupIDs are the IDs of the Hostgroups to propagate to their subgroups.
+	GroupIDs []string `json:"-"`
+
+	// Permissions causes the permissions of each Hostgroup to be applied to
+	// its subgroups.
+	Permissions bool `json:"permissions"`
+
+	// TagFilters causes the tag filters of each Hostgroup to be applied to
+	// its subgroups.
+	TagFilters bool `json:"tag_filters"`
+}
+
+// MarshalJSON encodes HostgroupPropagateParams as expected by the Zabbix API.
+func (c HostgroupPropagateParams) MarshalJSON() ([]byte, error) {
+	type params HostgroupPropagateParams
+	return json.Marshal(struct {
+		params
+		Groups []map[string]string `json:"groups"`
+	}{params(c), objectIDs("groupid", c.GroupIDs)})
+}
+
+// hostgroupAction calls the given hostgroup write method and returns the
+// affected Hostgroup IDs.
+func (c *Session) hostgroupAction(method string, params interface{}) ([]string, error) {
+	var body HostgroupResponse
+
+	if err := c.Get(method, params, &body); err != nil {
+		return nil, err
+	}
+
+	if len(body.GroupIDs) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return body.GroupIDs, nil
+}
+
+// templatesInTemplateGroups returns true if the given templates are to be
+// added to or removed from template groups instead of host groups, as on
+// Zabbix 6.2 or later.
+func (c *Session) templatesInTemplateGroups(templateIDs []string) (bool, error) {
+	if len(templateIDs) == 0 {
+		return false, nil
+	}
+
+	return c.VersionAtLeast(6, 2)
+}
+
+// splitHostgroupAction calls the given mass method of `hostgroup` with the
+// given host parameters, unless they are nil, and of `templategroup` with the
+// given template parameters, and returns the affected group IDs of both.
+func (c *Session) splitHostgroupAction(method string, hostParams, templateParams interface{}) ([]string, error) {
+	groupIDs := make([]string, 0)
+	if hostParams != nil {
+		ids, err := c.hostgroupAction("hostgroup."+method, hostParams)
+		if err != nil {
+			return nil, err
+		}
+		groupIDs = append(groupIDs, ids...)
+	}
+
+	ids, err := c.hostgroupAction("templategroup."+method, templateParams)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(groupIDs))
+	for _, id := range groupIDs {
+		seen[id] = true
+	}
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			groupIDs = append(groupIDs, id)
+		}
+	}
+
+	return groupIDs, nil
+}
+
+// CreateHostgroups creates a single or multiple new host groups. Only the Name
+// of each given Hostgroup is required.
+// Returns a list of group id(s) of created host group(s).
+//
+// Zabbix API docs: https://www.zabbix.com/documentation/current/manual/api/reference/hostgroup/create
+func (c *Session) CreateHostgroups(hostgroups ...Hostgroup) (groupIDs []string, err error) {
+	return c.hostgroupAction("hostgroup.create", hostgroups)
+}
+
+// UpdateHostgroups renames a single or multiple existing host groups. Only the
+// GroupID and Name of each given Hostgroup should be set.
+// Returns a list of updated group id(s).
+//
+// Zabbix API docs: https://www.zabbix.com/documentation/current/manual/api/reference/hostgroup/update
+func (c *Session) UpdateHostgroups(hostgroups ...Hostgroup) (groupIDs []string, err error) {
+	return c.hostgroupAction("hostgroup.update", hostgroups)
+}
+
+// DeleteHostgroups deletes a single or multiple host groups.
+// Returns a list of deleted group id(s).
+//
+// Zabbix API docs: https://www.zabbix.com/documentation/current/manual/api/reference/hostgroup/delete
+func (c *Session) DeleteHostgroups(groupIDs ...string) ([]string, error) {
+	return c.hostgroupAction("hostgroup.delete", groupIDs)
+}
+
+// MassAddHostgroups adds hosts and templates to multiple host groups. Since
+// Zabbix 6.2, templates are added to the template groups with the given IDs.
+// Returns a list of updated group id(s).
+//
+// Zabbix API docs: https://www.zabbix.com/documentation/current/manual/api/reference/hostgroup/massadd
+func (c *Session) MassAddHostgroups(params HostgroupMassParams) (groupIDs []string, err error) {
+	split, err := c.templatesInTemplateGroups(params.TemplateIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if !split {
+		return c.hostgroupAction("hostgroup.massadd", params)
+	}
+
+	var hostParams interface{}
+	if len(params.HostIDs) > 0 {
+		hostParams = HostgroupMassParams{GroupIDs: params.GroupIDs, HostIDs: params.HostIDs}
+	}
+
+	return c.splitHostgroupAction("massadd", hostParams, TemplateGroupMassParams{
+		GroupIDs:    params.GroupIDs,
+		TemplateIDs: params.TemplateIDs,
+	})
+}
+
+// MassUpdateHostgroups replaces the hosts and templates of multiple host
+// groups. Since Zabbix 6.2, templates replace the templates of the template
+// groups with the given IDs.
+// Returns a list of updated group id(s).
+//
+// Zabbix API docs: https://www.zabbix.com/documentation/current/manual/api/reference/hostgroup/massupdate
+func (c *Session) MassUpdateHostgroups(params HostgroupMassParams) (groupIDs []string, err error) {
+	split, err := c.templatesInTemplateGroups(params.TemplateIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if !split {
+		return c.hostgroupAction("hostgroup.massupdate", params)
+	}
+
+	var hostParams interface{}
+	if len(params.HostIDs) > 0 {
+		hostParams = HostgroupMassParams{GroupIDs: params.GroupIDs, HostIDs: params.HostIDs}
+	}
+
+	return c.splitHostgroupAction("massupdate", hostParams, TemplateGroupMassParams{
+		GroupIDs:    params.GroupIDs,
+		TemplateIDs: params.TemplateIDs,
+	})
+}
+
+// MassRemoveHostgroups removes hosts and templates from multiple host groups.
+// Since Zabbix 6.2, templates are removed from the template groups with the
+// given IDs.
+// Returns a list of updated group id(s).
+//
+// Zabbix API docs: https://www.zabbix.com/documentation/current/manual/api/reference/hostgroup/massremove
+func (c *Session) MassRemoveHostgroups(params HostgroupMassRemoveParams) (groupIDs []string, err error) {
+	split, err := c.templatesInTemplateGroups(params.TemplateIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if !split {
+		return c.hostgroupAction("hostgroup.massremove", params)
+	}
+
+	var hostParams interface{}
+	if len(params.HostIDs) > 0 {
+		hostParams = HostgroupMassRemoveParams{GroupIDs: params.GroupIDs, HostIDs: params.HostIDs}
+	}
+
+	return c.splitHostgroupAction("massremove", hostParams, TemplateGroupMassRemoveParams{
+		GroupIDs:    params.GroupIDs,
+		TemplateIDs: params.TemplateIDs,
+	})
+}
+
+// PropagateHostgroups applies the permissions and tag filters of the given host
+// groups to all of their subgroups.
+// Returns a list of updated group id(s).
+//
+// ErrUnsupportedVersion is returned if the connected Zabbix API is older than
+// v6.2.
+//
+// Zabbix API docs: https://www.zabbix.com/documentation/6.2/manual/api/reference/hostgroup/propagate
+func (c *Session) PropagateHostgroups(params HostgroupPropagateParams) (groupIDs []string, err error) {
+	if err := c.requireVersion(6, 2); err != nil {
+		return nil, err
+	}
+
+	return c.hostgroupAction("hostgroup.propagate", params)
+}
+
+// EnsureHostgroupPath ensures that a host group exists for the given nested
+// group path and for each of its parents, creating any which are missing.
+//
+// Zabbix represents nested host groups with names delimited by "/". For
+// example, the path "Linux/Web/Production" ensures that the groups "Linux",
+// "Linux/Web" and "Linux/Web/Production" all exist.
+//
+// Returns the group id of the last group in the path. An error is returned
+// if the path is empty or has an empty segment, such as "a//b" or "/a".
+func (c *Session) EnsureHostgroupPath(path string) (groupID string, err error) {
+	parts := strings.Split(path, "/")
+	names := make([]string, len(parts))
+	for i, part := range parts {
+		if strings.TrimSpace(part) == "" {
+			return "", fmt.Errorf("Invalid Hostgroup path %q: empty group name", path)
+		}
+
+		names[i] = strings.Join(parts[:i+1], "/")
+	}
+
+	existing := make(map[string]string, len(names))
+	hostgroups, err := c.GetHostgroups(HostgroupGetParams{
+		GetParameters: GetParameters{
+			Filter:       map[string]interface{}{"name": names},
+			OutputFields: SelectFields{"groupid", "name"},
+		},
+	})
+	if err != nil && err != ErrNotFound {
+		return "", err
+	}
+
+	for _, hostgroup := range hostgroups {
+		existing[hostgroup.Name] = hostgroup.GroupID
+	}
+
+	for _, name := range names {
+		if id, ok := existing[name]; ok {
+			groupID = id
+			continue
+		}
+
+		groupIDs, err := c.CreateHostgroups(Hostgroup{Name: name})
+		if err != nil {
+			return "", fmt.Errorf("Error creating Hostgroup %q: %v", name, err)
+		}
+
+		groupID = groupIDs[0]
+	}
+
+	return groupID, nil
+}
@@ -11,6 +11,8 @@ type jHostgroup struct {
 	Flags    string `json:"flags"`
 	Internal string `json:"internal"`
 	Hosts    jHosts `json:"hosts,omitempty"`
+
+	Templates jTemplates `json:"templates,omitempty"`
 }
 
 // Hostgroup returns a native Go Hostgroup struct mapped from the given JSON Hostgroup data.
@@ -28,6 +30,14 @@ func (c *jHostgroup) Hostgroup() (*Hostgroup, error) {
 
 	}
 
+	if len(c.Templates) > 0 {
+		templates, err := c.Templates.Templates()
+		if err != nil {
+			return nil, err
+		}
+		hostgroup.Templates = templates
+	}
+
 	return hostgroup, nil
 }
 
@@ -1,6 +1,11 @@
 package zabbix
 
 import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -26,3 +31,150 @@ func TestHostgroups(t *testing.T) {
 
 	t.Logf("Validated %d Hostgroups", len(hostgroups))
 }
+
+func TestHostgroupTemplateParams(t *testing.T) {
+	session := &Session{APIVersion: "6.2.0"}
+	_, err := session.GetHostgroups(HostgroupGetParams{
+		HostIDs:     []string{"10084"},
+		TemplateIDs: []string{"10001"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "hostids") {
+		t.Errorf("Expected an error for host parameters with template parameters, got %v", err)
+	}
+
+	if name := hostOnlyHostgroupParam(&HostgroupGetParams{GroupIDs: []string{"2"}, WithItems: 1}); name != "" {
+		t.Errorf("Expected no host-only parameters, got %s", name)
+	}
+
+	// host groups have no templates since v6.2
+	_, err = session.GetHostgroups(HostgroupGetParams{SelectTemplates: SelectExtendedOutput})
+	if err != ErrUnsupportedVersion {
+		t.Errorf("Expected ErrUnsupportedVersion for selectTemplates, got %v", err)
+	}
+}
+
+func TestMassHostgroupsTemplateGroups(t *testing.T) {
+	calls := make([]string, 0)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     uint64          `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Error decoding request: %v", err)
+		}
+		calls = append(calls, req.Method+" "+string(req.Params))
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","result":{"groupids":["2"]},"id":%d}`, req.ID)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		Version string
+		Call    func(*Session) ([]string, error)
+		Expect  []string
+	}{
+		{
+			"6.2.0",
+			func(c *Session) ([]string, error) {
+				return c.MassAddHostgroups(HostgroupMassParams{GroupIDs: []string{"2"}, HostIDs: []string{"10"}, TemplateIDs: []string{"20"}})
+			},
+			[]string{
+				`hostgroup.massadd {"groups":[{"groupid":"2"}],"hosts":[{"hostid":"10"}]}`,
+				`templategroup.massadd {"groups":[{"groupid":"2"}],"templates":[{"templateid":"20"}]}`,
+			},
+		},
+		{
+			"6.2.0",
+			func(c *Session) ([]string, error) {
+				return c.MassUpdateHostgroups(HostgroupMassParams{GroupIDs: []string{"2"}, TemplateIDs: []string{"20"}})
+			},
+			[]string{`templategroup.massupdate {"groups":[{"groupid":"2"}],"templates":[{"templateid":"20"}]}`},
+		},
+		{
+			"6.2.0",
+			func(c *Session) ([]string, error) {
+				return c.MassRemoveHostgroups(HostgroupMassRemoveParams{GroupIDs: []string{"2"}, HostIDs: []string{"10"}, TemplateIDs: []string{"20"}})
+			},
+			[]string{
+				`hostgroup.massremove {"groupids":["2"],"hostids":["10"]}`,
+				`templategroup.massremove {"groupids":["2"],"templateids":["20"]}`,
+			},
+		},
+		{
+			"6.0.0",
+			func(c *Session) ([]string, error) {
+				return c.MassAddHostgroups(HostgroupMassParams{GroupIDs: []string{"2"}, HostIDs: []string{"10"}, TemplateIDs: []string{"20"}})
+			},
+			[]string{`hostgroup.massadd {"groups":[{"groupid":"2"}],"hosts":[{"hostid":"10"}],"templates":[{"templateid":"20"}]}`},
+		},
+	}
+
+	for i, test := range tests {
+		calls = calls[:0]
+		session := &Session{URL: srv.URL, APIVersion: test.Version, client: srv.Client()}
+		groupIDs, err := test.Call(session)
+		if err != nil {
+			t.Fatalf("Error in test %d: %v", i, err)
+		}
+
+		if len(groupIDs) != 1 || groupIDs[0] != "2" {
+			t.Errorf("Expected group IDs [2] in test %d, got %v", i, groupIDs)
+		}
+
+		if strings.Join(calls, "\n") != strings.Join(test.Expect, "\n") {
+			t.Errorf("Unexpected calls in test %d:\n%s", i, strings.Join(calls, "\n"))
+		}
+	}
+}
+
+func TestEnsureHostgroupPathValidation(t *testing.T) {
+	session := &Session{}
+	for _, path := range []string{"", "/", "a//b", "/a", "a/", "a/ /b"} {
+		if _, err := session.EnsureHostgroupPath(path); err == nil {
+			t.Errorf("Expected an error for Hostgroup path %q", path)
+		}
+	}
+}
+
+func TestEnsureHostgroupPath(t *testing.T) {
+	session := GetTestSession(t)
+
+	groupID, err := session.EnsureHostgroupPath("go-zabbix/test/nested")
+	if err != nil {
+		t.Fatalf("Error ensuring Hostgroup path: %v", err)
+	}
+
+	// ensuring an existing path must return the same group
+	again, err := session.EnsureHostgroupPath("go-zabbix/test/nested")
+	if err != nil {
+		t.Fatalf("Error ensuring existing Hostgroup path: %v", err)
+	}
+
+	if again != groupID {
+		t.Errorf("Expected existing Hostgroup %s, got %s", groupID, again)
+	}
+
+	hostgroups, err := session.GetHostgroups(HostgroupGetParams{
+		GetParameters: GetParameters{
+			TextSearch:        map[string]string{"name": "go-zabbix"},
+			TextSearchByStart: true,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Error getting Hostgroups: %v", err)
+	}
+
+	if len(hostgroups) != 3 {
+		t.Errorf("Expected 3 Hostgroups in path, got %d", len(hostgroups))
+	}
+
+	groupIDs := make([]string, len(hostgroups))
+	for i, hostgroup := range hostgroups {
+		groupIDs[i] = hostgroup.GroupID
+	}
+
+	if _, err := session.DeleteHostgroups(groupIDs...); err != nil {
+		t.Fatalf("Error deleting Hostgroups: %v", err)
+	}
+}
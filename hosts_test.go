@@ -42,3 +42,55 @@ func TestHosts(t *testing.T) {
 
 	t.Logf("Validated %d Hosts", len(hosts))
 }
+
+func TestCountHosts(t *testing.T) {
+	session := GetTestSession(t)
+
+	count, err := session.CountHosts(HostGetParams{})
+	if err != nil {
+		t.Fatalf("Error counting Hosts: %v", err)
+	}
+
+	hosts, err := session.GetHosts(HostGetParams{})
+	if err != nil {
+		t.Fatalf("Error getting Hosts: %v", err)
+	}
+
+	if count != len(hosts) {
+		t.Fatalf("Expected %d Hosts, counted %d", len(hosts), count)
+	}
+}
+
+func TestHostCRUD(t *testing.T) {
+	session := GetTestSession(t)
+
+	hostgroups, err := session.GetHostgroups(HostgroupGetParams{RealHosts: 1})
+	if err != nil {
+		t.Fatalf("Error getting Hostgroups: %v", err)
+	}
+
+	hostIDs, err := session.CreateHosts(HostCreateParams{
+		Hostname: "go-zabbix-test-host",
+		Groups:   []Hostgroup{{GroupID: hostgroups[0].GroupID}},
+		Interfaces: []HostInterface{{
+			Type:  HostInterfaceTypeAgent,
+			Main:  true,
+			UseIP: true,
+			IP:    "127.0.0.1",
+			Port:  "10050",
+		}},
+		Tags: []HostTag{{Tag: "test", Value: "go-zabbix"}},
+	})
+	if err != nil {
+		t.Fatalf("Error creating Host: %v", err)
+	}
+
+	status := HostStatusUnmonitored
+	if _, err := session.UpdateHosts(HostUpdateParams{HostID: hostIDs[0], Status: &status}); err != nil {
+		t.Errorf("Error updating Host: %v", err)
+	}
+
+	if _, err := session.DeleteHosts(hostIDs...); err != nil {
+		t.Fatalf("Error deleting Host: %v", err)
+	}
+}
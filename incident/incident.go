@@ -0,0 +1,412 @@
+/*
+Package incident reconstructs incidents from Zabbix Events for postmortems and
+reporting.
+
+An Incident is a problem Event paired with the Event which resolved it, with the
+acknowledgements and alerts of both in a single timeline:
+
+	incidents, err := incident.Fetch(session, incident.Query{
+		HostIDs: []string{"10084"},
+		From:    time.Now().Add(-7 * 24 * time.Hour),
+		Till:    time.Now(),
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	for _, i := range incidents {
+		tta, _ := i.TimeToAcknowledge()
+		fmt.Println(i.Name, i.Start, i.Duration(), tta, i.AcknowledgedBy())
+	}
+*/
+package incident
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/cavaliercoder/go-zabbix"
+)
+
+// EntryKind is the kind of an Entry in the timeline of an Incident.
+type EntryKind int
+
+const (
+	// EntryProblem is the problem Event which started the Incident.
+	EntryProblem EntryKind = iota
+
+	// EntryAcknowledge is an acknowledgement or other update of the problem.
+	EntryAcknowledge
+
+	// EntryAlert is an Alert sent for the problem or recovery Event.
+	EntryAlert
+
+	// EntryRecovery is the Event which resolved the Incident.
+	EntryRecovery
+)
+
+// String returns the name of the EntryKind.
+func (c EntryKind) String() string {
+	switch c {
+	case EntryProblem:
+		return "problem"
+	case EntryAcknowledge:
+		return "acknowledge"
+	case EntryAlert:
+		return "alert"
+	case EntryRecovery:
+		return "recovery"
+	}
+
+	return "unknown"
+}
+
+// Entry is a single entry in the timeline of an Incident.
+type Entry struct {
+	// Time is the time of the entry.
+	Time time.Time
+
+	// Kind is the kind of the entry.
+	Kind EntryKind
+
+	// Event is the problem or recovery Event of EntryProblem and EntryRecovery
+	// entries.
+	Event *zabbix.Event
+
+	// Acknowledge is the acknowledgement of EntryAcknowledge entries.
+	Acknowledge *zabbix.Acknowledgement
+
+	// Alert is the Alert of EntryAlert entries.
+	Alert *zabbix.Alert
+}
+
+// Incident is a problem and its resolution.
+type Incident struct {
+	// TriggerID is the ID of the Trigger which caused the problem.
+	TriggerID string
+
+	// Name is the name of the problem.
+	Name string
+
+	// Severity is the severity of the problem and must be one of the
+	// TriggerSeverity constants.
+	Severity int
+
+	// Hosts are the Hosts of the problem.
+	Hosts []zabbix.Host
+
+	// Problem is the problem Event.
+	Problem zabbix.Event
+
+	// Recovery is the Event which resolved the problem, or nil if the problem
+	// is unresolved.
+	Recovery *zabbix.Event
+
+	// Start is the time at which the problem started.
+	Start time.Time
+
+	// End is the time at which the problem was resolved, or the zero time if
+	// the problem is unresolved.
+	End time.Time
+
+	// Timeline is the problem, acknowledgements, alerts and recovery of the
+	// Incident in chronological order.
+	Timeline []Entry
+}
+
+// Resolved returns true if the problem was resolved.
+func (c *Incident) Resolved() bool {
+	return c.Recovery != nil
+}
+
+// Duration returns the duration of the problem until it was resolved, or until
+// now if it is unresolved.
+func (c *Incident) Duration() time.Duration {
+	if c.Resolved() {
+		return c.End.Sub(c.Start)
+	}
+
+	return time.Since(c.Start)
+}
+
+// Acknowledges returns the acknowledgements of the problem in chronological
+// order.
+func (c *Incident) Acknowledges() []zabbix.Acknowledgement {
+	out := make([]zabbix.Acknowledgement, 0)
+	for _, entry := range c.Timeline {
+		if entry.Kind == EntryAcknowledge {
+			out = append(out, *entry.Acknowledge)
+		}
+	}
+
+	return out
+}
+
+// Alerts returns the Alerts sent for the problem and recovery in chronological
+// order.
+func (c *Incident) Alerts() []zabbix.Alert {
+	out := make([]zabbix.Alert, 0)
+	for _, entry := range c.Timeline {
+		if entry.Kind == EntryAlert {
+			out = append(out, *entry.Alert)
+		}
+	}
+
+	return out
+}
+
+// firstAcknowledge returns the first update of the problem which acknowledged
+// it.
+func (c *Incident) firstAcknowledge() *zabbix.Acknowledgement {
+	for _, entry := range c.Timeline {
+		if entry.Kind == EntryAcknowledge && entry.Acknowledge.Action.Has(zabbix.AcknowledgeActionAcknowledge) {
+			return entry.Acknowledge
+		}
+	}
+
+	return nil
+}
+
+// TimeToAcknowledge returns the time between the start of the problem and its
+// first acknowledgement, and false if the problem was not acknowledged.
+func (c *Incident) TimeToAcknowledge() (time.Duration, bool) {
+	ack := c.firstAcknowledge()
+	if ack == nil {
+		return 0, false
+	}
+
+	return ack.Timestamp.Sub(c.Start), true
+}
+
+// AcknowledgedBy returns the usernames, or user IDs if usernames are not
+// known, of each user who acknowledged the problem, in order of their first
+// acknowledgement.
+func (c *Incident) AcknowledgedBy() []string {
+	out := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, ack := range c.Acknowledges() {
+		if !ack.Action.Has(zabbix.AcknowledgeActionAcknowledge) {
+			continue
+		}
+
+		user := ack.Username
+		if user == "" {
+			user = ack.UserID
+		}
+
+		if !seen[user] {
+			seen[user] = true
+			out = append(out, user)
+		}
+	}
+
+	return out
+}
+
+// eventLess orders Events by time and ID.
+func eventLess(a, b *zabbix.Event) bool {
+	if !a.Timestamp.Equal(b.Timestamp) {
+		return a.Timestamp.Before(b.Timestamp)
+	}
+
+	if len(a.EventID) != len(b.EventID) {
+		return len(a.EventID) < len(b.EventID)
+	}
+
+	return a.EventID < b.EventID
+}
+
+// Build returns the Incidents of the given trigger Events in order of their
+// start.
+//
+// Problem Events are paired with the recovery Event given by their
+// RecoveryEventID. If the Zabbix API does not return recovery Event IDs, a
+// problem is resolved by the first OK Event of the same Trigger which follows
+// it. Problems whose recovery Event is not given are unresolved.
+func Build(events []zabbix.Event) []Incident {
+	sorted := make([]*zabbix.Event, 0, len(events))
+	byID := make(map[string]*zabbix.Event, len(events))
+	for i := range events {
+		if events[i].Source != zabbix.EventSourceTrigger || events[i].ObjectType != zabbix.EventObjectTypeTrigger {
+			continue
+		}
+
+		sorted = append(sorted, &events[i])
+		byID[events[i].EventID] = &events[i]
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool { return eventLess(sorted[i], sorted[j]) })
+
+	// recovery events of known problems do not resolve other problems
+	paired := make(map[string]bool)
+	for _, event := range sorted {
+		if event.RecoveryEventID != "" {
+			paired[event.RecoveryEventID] = true
+		}
+	}
+
+	out := make([]Incident, 0)
+	open := make(map[int][]int) // indexes of unresolved incidents by trigger
+	for _, event := range sorted {
+		switch event.Value {
+		case zabbix.TriggerEventValueProblem:
+			incident := Incident{
+				TriggerID: strconv.Itoa(event.ObjectID),
+				Name:      event.Name,
+				Severity:  event.Severity,
+				Hosts:     event.Hosts,
+				Problem:   *event,
+				Start:     event.Timestamp,
+			}
+
+			// events of versions before 4.0 have no name and severity
+			if incident.Name == "" && event.RelatedTrigger != nil {
+				incident.Name = event.RelatedTrigger.Description
+				incident.Severity = event.RelatedTrigger.Severity
+			}
+
+			if event.RecoveryEventID != "" {
+				incident.Recovery = byID[event.RecoveryEventID]
+			} else {
+				open[event.ObjectID] = append(open[event.ObjectID], len(out))
+			}
+
+			out = append(out, incident)
+
+		case zabbix.TriggerEventValueOK:
+			// value transitions of versions without recovery event IDs
+			if paired[event.EventID] {
+				continue
+			}
+
+			for _, i := range open[event.ObjectID] {
+				if out[i].Recovery == nil {
+					out[i].Recovery = event
+				}
+			}
+			delete(open, event.ObjectID)
+		}
+	}
+
+	for i := range out {
+		out[i].build()
+	}
+
+	return out
+}
+
+// build populates the end and timeline of the Incident.
+func (c *Incident) build() {
+	c.Timeline = []Entry{{Time: c.Start, Kind: EntryProblem, Event: &c.Problem}}
+
+	for i := range c.Problem.Acknowledges {
+		ack := &c.Problem.Acknowledges[i]
+		c.Timeline = append(c.Timeline, Entry{Time: ack.Timestamp, Kind: EntryAcknowledge, Acknowledge: ack})
+	}
+
+	for i := range c.Problem.Alerts {
+		alert := &c.Problem.Alerts[i]
+		c.Timeline = append(c.Timeline, Entry{Time: alert.Timestamp, Kind: EntryAlert, Alert: alert})
+	}
+
+	if c.Recovery != nil {
+		c.End = c.Recovery.Timestamp
+		c.Timeline = append(c.Timeline, Entry{Time: c.End, Kind: EntryRecovery, Event: c.Recovery})
+
+		for i := range c.Recovery.Alerts {
+			alert := &c.Recovery.Alerts[i]
+			c.Timeline = append(c.Timeline, Entry{Time: alert.Timestamp, Kind: EntryAlert, Alert: alert})
+		}
+	}
+
+	sort.SliceStable(c.Timeline, func(i, j int) bool {
+		return c.Timeline[i].Time.Before(c.Timeline[j].Time)
+	})
+}
+
+// Query selects the Incidents returned by Fetch.
+type Query struct {
+	// TriggerIDs filters Incidents to problems of the given Triggers.
+	TriggerIDs []string
+
+	// HostIDs filters Incidents to problems of the given Hosts.
+	HostIDs []string
+
+	// From and Till select Incidents which started in the given time range.
+	From time.Time
+	Till time.Time
+}
+
+// Fetch queries the Zabbix API for the Incidents which started in the time
+// range of the given Query, with their recovery Events, acknowledgements and
+// alerts.
+//
+// An error is returned if a transport, parsing or API error occurs.
+func Fetch(session *zabbix.Session, q Query) ([]Incident, error) {
+	if q.From.IsZero() || q.Till.IsZero() {
+		return nil, errors.New("A time range is required")
+	}
+
+	params := zabbix.EventGetParams{
+		GetParameters: zabbix.GetParameters{
+			SortField: []string{"clock", "eventid"},
+			SortOrder: "ASC",
+		},
+		ObjectIDs:              q.TriggerIDs,
+		HostIDs:                q.HostIDs,
+		ObjectType:             zabbix.EventObjectTypeTrigger,
+		MinTime:                q.From.Unix(),
+		MaxTime:                q.Till.Unix(),
+		Value:                  []int{zabbix.TriggerEventValueProblem},
+		SelectHosts:            zabbix.SelectFields{"hostid", "host", "name"},
+		SelectRelatedObject:    zabbix.SelectFields{"triggerid", "description", "priority"},
+		SelectAcknowledgements: zabbix.SelectExtendedOutput,
+		SelectAlerts:           zabbix.SelectExtendedOutput,
+	}
+
+	problems, err := session.GetEvents(params)
+	if err == zabbix.ErrNotFound {
+		return []Incident{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// recovery events may follow the time range
+	events := problems
+	recoveryIDs := make([]string, 0)
+	for _, problem := range problems {
+		if problem.RecoveryEventID != "" {
+			recoveryIDs = append(recoveryIDs, problem.RecoveryEventID)
+		}
+	}
+
+	pairedByID, err := session.VersionAtLeast(3, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	params.MaxTime = 0
+	params.Value = []int{zabbix.TriggerEventValueOK}
+	params.SelectAcknowledgements = nil
+	if pairedByID {
+		if len(recoveryIDs) == 0 {
+			return Build(events), nil
+		}
+
+		params.MinTime = 0
+		params.EventIDs = recoveryIDs
+		params.ObjectIDs = nil
+		params.HostIDs = nil
+	}
+
+	// versions without recovery event IDs are paired by value transitions
+	recoveries, err := session.GetEvents(params)
+	if err != nil && err != zabbix.ErrNotFound {
+		return nil, err
+	}
+
+	return Build(append(events, recoveries...)), nil
+}
@@ -0,0 +1,98 @@
+package incident
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cavaliercoder/go-zabbix"
+)
+
+func event(id string, triggerID, value int, clock int64) zabbix.Event {
+	return zabbix.Event{
+		EventID:    id,
+		Source:     zabbix.EventSourceTrigger,
+		ObjectType: zabbix.EventObjectTypeTrigger,
+		ObjectID:   triggerID,
+		Value:      value,
+		Timestamp:  time.Unix(clock, 0),
+	}
+}
+
+func TestBuild(t *testing.T) {
+	problem := event("10", 1, zabbix.TriggerEventValueProblem, 1000)
+	problem.Name = "High load"
+	problem.RecoveryEventID = "12"
+	problem.Acknowledges = []zabbix.Acknowledgement{
+		{AcknowledgeID: "3", Username: "bob", Timestamp: time.Unix(1300, 0), Action: zabbix.AcknowledgeActionAcknowledge | zabbix.AcknowledgeActionMessage},
+		{AcknowledgeID: "2", Username: "alice", Timestamp: time.Unix(1200, 0), Action: zabbix.AcknowledgeActionMessage},
+	}
+	problem.Alerts = []zabbix.Alert{{AlertID: "5", Timestamp: time.Unix(1001, 0)}}
+
+	recovery := event("12", 1, zabbix.TriggerEventValueOK, 1600)
+	recovery.Alerts = []zabbix.Alert{{AlertID: "6", Timestamp: time.Unix(1601, 0)}}
+
+	unresolved := event("11", 2, zabbix.TriggerEventValueProblem, 1100)
+
+	incidents := Build([]zabbix.Event{recovery, unresolved, problem})
+	if len(incidents) != 2 {
+		t.Fatalf("Expected 2 incidents, got %d", len(incidents))
+	}
+
+	i := incidents[0]
+	if i.Name != "High load" || i.TriggerID != "1" || !i.Resolved() || i.Duration() != 600*time.Second {
+		t.Errorf("Unexpected incident: %+v", i)
+	}
+
+	if tta, ok := i.TimeToAcknowledge(); !ok || tta != 300*time.Second {
+		t.Errorf("Expected time to acknowledge of 5m, got %v", tta)
+	}
+
+	if by := i.AcknowledgedBy(); len(by) != 1 || by[0] != "bob" {
+		t.Errorf("Unexpected acknowledging users: %v", by)
+	}
+
+	kinds := []EntryKind{EntryProblem, EntryAlert, EntryAcknowledge, EntryAcknowledge, EntryRecovery, EntryAlert}
+	if len(i.Timeline) != len(kinds) {
+		t.Fatalf("Expected %d timeline entries, got %d", len(kinds), len(i.Timeline))
+	}
+	for j, kind := range kinds {
+		if i.Timeline[j].Kind != kind {
+			t.Errorf("Expected timeline entry %d to be %v, got %v", j, kind, i.Timeline[j].Kind)
+		}
+	}
+
+	if incidents[1].Resolved() || !incidents[1].End.IsZero() {
+		t.Errorf("Expected incident to be unresolved: %+v", incidents[1])
+	}
+
+	if _, ok := incidents[1].TimeToAcknowledge(); ok {
+		t.Errorf("Expected incident to be unacknowledged")
+	}
+}
+
+func TestBuildValueTransitions(t *testing.T) {
+	// versions before 3.2 do not return recovery event IDs
+	incidents := Build([]zabbix.Event{
+		event("1", 1, zabbix.TriggerEventValueProblem, 100),
+		event("2", 2, zabbix.TriggerEventValueProblem, 150),
+		event("3", 1, zabbix.TriggerEventValueOK, 200),
+		event("4", 1, zabbix.TriggerEventValueProblem, 300),
+		event("5", 1, zabbix.TriggerEventValueOK, 400),
+	})
+
+	if len(incidents) != 3 {
+		t.Fatalf("Expected 3 incidents, got %d", len(incidents))
+	}
+
+	expect := []string{"3", "", "5"}
+	for i, id := range expect {
+		recovery := ""
+		if incidents[i].Recovery != nil {
+			recovery = incidents[i].Recovery.EventID
+		}
+
+		if recovery != id {
+			t.Errorf("Expected incident %d to be resolved by %q, got %q", i, id, recovery)
+		}
+	}
+}
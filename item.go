@@ -4,6 +4,100 @@ import (
 	"fmt"
 )
 
+const (
+	// ItemTypeZabbixAgent indicates that an Item is polled by the Zabbix
+	// agent.
+	ItemTypeZabbixAgent = 0
+
+	// ItemTypeZabbixTrapper indicates that an Item receives values sent by
+	// zabbix_sender.
+	ItemTypeZabbixTrapper = 2
+
+	// ItemTypeSimpleCheck indicates that an Item is a simple check.
+	ItemTypeSimpleCheck = 3
+
+	// ItemTypeZabbixInternal indicates that an Item is an internal check.
+	ItemTypeZabbixInternal = 5
+
+	// ItemTypeZabbixAgentActive indicates that an Item is sent by an active
+	// Zabbix agent.
+	ItemTypeZabbixAgentActive = 7
+
+	// ItemTypeWebItem indicates that an Item is created by a web scenario.
+	ItemTypeWebItem = 9
+
+	// ItemTypeExternalCheck indicates that an Item is an external check.
+	ItemTypeExternalCheck = 10
+
+	// ItemTypeDatabaseMonitor indicates that an Item is a database monitor.
+	ItemTypeDatabaseMonitor = 11
+
+	// ItemTypeIPMIAgent indicates that an Item is polled by an IPMI agent.
+	ItemTypeIPMIAgent = 12
+
+	// ItemTypeSSHAgent indicates that an Item is polled by SSH.
+	ItemTypeSSHAgent = 13
+
+	// ItemTypeTelnetAgent indicates that an Item is polled by Telnet.
+	ItemTypeTelnetAgent = 14
+
+	// ItemTypeCalculated indicates that an Item is calculated from a formula.
+	ItemTypeCalculated = 15
+
+	// ItemTypeJMXAgent indicates that an Item is polled by a JMX agent.
+	ItemTypeJMXAgent = 16
+
+	// ItemTypeSNMPTrap indicates that an Item receives SNMP traps.
+	ItemTypeSNMPTrap = 17
+
+	// ItemTypeDependent indicates that an Item gets its value from a master
+	// Item.
+	ItemTypeDependent = 18
+
+	// ItemTypeHTTPAgent indicates that an Item is polled by HTTP.
+	ItemTypeHTTPAgent = 19
+
+	// ItemTypeSNMPAgent indicates that an Item is polled by SNMP.
+	ItemTypeSNMPAgent = 20
+
+	// ItemTypeScript indicates that an Item gets its value from a script.
+	ItemTypeScript = 21
+)
+
+const (
+	// ItemValueTypeFloat indicates that an Item stores numeric float values.
+	ItemValueTypeFloat = 0
+
+	// ItemValueTypeCharacter indicates that an Item stores character values.
+	ItemValueTypeCharacter = 1
+
+	// ItemValueTypeLog indicates that an Item stores log values.
+	ItemValueTypeLog = 2
+
+	// ItemValueTypeUnsigned indicates that an Item stores numeric unsigned
+	// values.
+	ItemValueTypeUnsigned = 3
+
+	// ItemValueTypeText indicates that an Item stores text values.
+	ItemValueTypeText = 4
+)
+
+const (
+	// ItemStatusEnabled indicates that an Item is enabled.
+	ItemStatusEnabled = 0
+
+	// ItemStatusDisabled indicates that an Item is disabled.
+	ItemStatusDisabled = 1
+)
+
+const (
+	// ItemStateNormal indicates that an Item is supported.
+	ItemStateNormal = 0
+
+	// ItemStateNotSupported indicates that an Item is not supported.
+	ItemStateNotSupported = 1
+)
+
 // Item represents a Zabbix Item returned from the Zabbix API.
 //
 // See: https://www.zabbix.com/documentation/4.0/manual/api/reference/item/object
@@ -26,9 +120,114 @@ type Item struct {
 	// LastValue is the last value of the Item.
 	LastValue string
 
-	// LastValueType is the type of LastValue
-	// 0 - float; 1 - text; 3 - int;
+	// LastValueType is the type of information of the Item and LastValue.
+	//
+	// LastValueType must be one of the ItemValueType constants.
 	LastValueType int
+
+	// Key is the item key.
+	Key string
+
+	// Type is the type of the Item and must be one of the ItemType constants.
+	Type int
+
+	// Delay is the update interval of the Item (e.g. "30s" or "1m"). Can
+	// contain user macros and flexible or scheduling intervals.
+	Delay string
+
+	// Units is the value units of the Item.
+	Units string
+
+	// History is how long history data is stored (e.g. "90d").
+	History string
+
+	// Trends is how long trend data is stored (e.g. "365d").
+	Trends string
+
+	// ValueMapID is the ID of the value map of the Item.
+	ValueMapID string
+
+	// ValueMap is the value map of the Item.
+	//
+	// ValueMap is only populated if ItemGetParams.SelectValueMap is given in
+	// the query parameters that returned this Item.
+	ValueMap *ValueMap
+
+	// MasterItemID is the ID of the master Item of a dependent Item.
+	MasterItemID string
+
+	// InterfaceID is the ID of the host interface used by the Item.
+	InterfaceID string
+
+	// TemplateID is the ID of the parent template Item if the Item was
+	// inherited from a template.
+	TemplateID string
+
+	// Status of the Item. Must be one of the ItemStatus constants.
+	Status int
+
+	// State of the Item. Must be one of the ItemState constants.
+	State int
+
+	// Error is the error text if the Item is not supported.
+	Error string
+
+	// Params contains additional parameters depending on the type of the
+	// Item, such as the formula of a calculated Item or a script.
+	Params string
+
+	// Tags are the tags of the Item.
+	//
+	// Tags is only populated if ItemGetParams.SelectTags is given in the query
+	// parameters that returned this Item.
+	Tags []ItemTag
+
+	// Preprocessing contains the preprocessing steps of the Item, in the order
+	// they are applied.
+	//
+	// Preprocessing is only populated if ItemGetParams.SelectPreprocessing is
+	// given in the query parameters that returned this Item.
+	Preprocessing []ItemPreprocessing
+}
+
+// ItemTag is a tag assigned to an Item.
+type ItemTag struct {
+	Tag   string `json:"tag"`
+	Value string `json:"value"`
+}
+
+// ItemPreprocessing is a preprocessing step of an Item.
+//
+// See: https://www.zabbix.com/documentation/current/manual/api/reference/item/object#item_preprocessing
+type ItemPreprocessing struct {
+	// Type of the preprocessing step.
+	Type int `json:"type,string"`
+
+	// Params are the parameters of the preprocessing step. Multiple
+	// parameters are delimited by a line feed.
+	Params string `json:"params"`
+
+	// ErrorHandler is the action to take if the preprocessing step fails.
+	ErrorHandler int `json:"error_handler,string"`
+
+	// ErrorHandlerParams are the parameters of the error handler.
+	ErrorHandlerParams string `json:"error_handler_params"`
+}
+
+// ValueMap is a value map which maps Item values to human readable values.
+//
+// See: https://www.zabbix.com/documentation/current/manual/api/reference/valuemap/object
+type ValueMap struct {
+	ValueMapID string            `json:"valuemapid"`
+	Name       string            `json:"name"`
+	Mappings   []ValueMapMapping `json:"mappings,omitempty"`
+}
+
+// ValueMapMapping is a single mapping of a ValueMap.
+type ValueMapMapping struct {
+	Type     int    `json:"type,string,omitempty"`
+	Value    string `json:"value"`
+	NewValue string `json:"newvalue"`
 }
 
 type ItemTagFilter struct {
@@ -107,6 +306,18 @@ type ItemGetParams struct {
 
 	// Filter by tags
 	Tags []ItemTagFilter `json:"tags,omitempty"`
+
+	// SelectPreprocessing causes the preprocessing steps of each Item to be
+	// attached in the search results.
+	SelectPreprocessing SelectQuery `json:"selectPreprocessing,omitempty"`
+
+	// SelectTags causes the tags of each Item to be attached in the search
+	// results.
+	SelectTags SelectQuery `json:"selectTags,omitempty"`
+
+	// SelectValueMap causes the value map of each Item to be attached in the
+	// search results.
+	SelectValueMap SelectQuery `json:"selectValueMap,omitempty"`
 }
 
 // GetItems queries the Zabbix API for Items matching the given search
@@ -135,3 +346,131 @@ func (c *Session) GetItems(params ItemGetParams) ([]Item, error) {
 
 	return out, nil
 }
+
+// CountItems queries the Zabbix API for the number of Items matching the given
+// search parameters.
+//
+// An error is returned if a transport, parsing or API error occurs.
+func (c *Session) CountItems(params ItemGetParams) (int, error) {
+	params.CountOutput = true
+	return c.count("item.get", params)
+}
+
+// ItemResponse represent item action response body
+type ItemResponse struct {
+	ItemIDs []string `json:"itemids"`
+}
+
+// ItemCreateParams represent the parameters for an `item.create` API call.
+//
+// See: https://www.zabbix.com/documentation/current/manual/api/reference/item/create
+type ItemCreateParams struct {
+	// HostID is the ID of the Host or Template that the Item belongs to.
+	HostID string `json:"hostid"`
+
+	// Name is the name of the Item.
+	Name string `json:"name"`
+
+	// Key is the item key.
+	Key string `json:"key_"`
+
+	// Type is the type of the Item and must be one of the ItemType constants.
+	Type int `json:"type"`
+
+	// ValueType is the type of information of the Item and must be one of the
+	// ItemValueType constants.
+	ValueType int `json:"value_type"`
+
+	// Delay is the update interval of the Item. Required for all but trapper
+	// and dependent Items.
+	Delay string `json:"delay,omitempty"`
+
+	// InterfaceID is the ID of the host interface used by the Item. Required
+	// for most Item types on Hosts.
+	InterfaceID string `json:"interfaceid,omitempty"`
+
+	Description  string `json:"description,omitempty"`
+	Units        string `json:"units,omitempty"`
+	History      string `json:"history,omitempty"`
+	Trends       string `json:"trends,omitempty"`
+	ValueMapID   string `json:"valuemapid,omitempty"`
+	MasterItemID string `json:"master_itemid,omitempty"`
+	Params       string `json:"params,omitempty"`
+
+	// Status of the Item. Must be one of the ItemStatus constants.
+	Status int `json:"status"`
+
+	Tags          []ItemTag           `json:"tags,omitempty"`
+	Preprocessing []ItemPreprocessing `json:"preprocessing,omitempty"`
+}
+
+// ItemUpdateParams represent the parameters for an `item.update` API call.
+//
+// Only the fields that are set are updated. Tags and Preprocessing replace the
+// existing values of the Item when given.
+//
+// See: https://www.zabbix.com/documentation/current/manual/api/reference/item/update
+type ItemUpdateParams struct {
+	// ItemID is the ID of the Item to update.
+	ItemID string `json:"itemid"`
+
+	Name         string `json:"name,omitempty"`
+	Key          string `json:"key_,omitempty"`
+	Type         *int   `json:"type,omitempty"`
+	ValueType    *int   `json:"value_type,omitempty"`
+	Delay        string `json:"delay,omitempty"`
+	InterfaceID  string `json:"interfaceid,omitempty"`
+	Description  string `json:"description,omitempty"`
+	Units        string `json:"units,omitempty"`
+	History      string `json:"history,omitempty"`
+	Trends       string `json:"trends,omitempty"`
+	ValueMapID   string `json:"valuemapid,omitempty"`
+	MasterItemID string `json:"master_itemid,omitempty"`
+	Params       string `json:"params,omitempty"`
+
+	// Status of the Item. Must be one of the ItemStatus constants.
+	Status *int `json:"status,omitempty"`
+
+	Tags          []ItemTag           `json:"tags,omitempty"`
+	Preprocessing []ItemPreprocessing `json:"preprocessing,omitempty"`
+}
+
+// itemAction calls the given item write method and returns the affected Item
+// IDs.
+func (c *Session) itemAction(method string, params interface{}) ([]string, error) {
+	var body ItemResponse
+
+	if err := c.Get(method, params, &body); err != nil {
+		return nil, err
+	}
+
+	if len(body.ItemIDs) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return body.ItemIDs, nil
+}
+
+// CreateItems creates a single or multiple new items.
+// Returns a list of item id(s) of created item(s).
+//
+// Zabbix API docs: https://www.zabbix.com/documentation/current/manual/api/reference/item/create
+func (c *Session) CreateItems(items ...ItemCreateParams) (itemIDs []string, err error) {
+	return c.itemAction("item.create", items)
+}
+
+// UpdateItems updates a single or multiple existing items.
+// Returns a list of updated item id(s).
+//
+// Zabbix API docs: https://www.zabbix.com/documentation/current/manual/api/reference/item/update
+func (c *Session) UpdateItems(items ...ItemUpdateParams) (itemIDs []string, err error) {
+	return c.itemAction("item.update", items)
+}
+
+// DeleteItems deletes a single or multiple items.
+// Returns a list of deleted item id(s).
+//
+// Zabbix API docs: https://www.zabbix.com/documentation/current/manual/api/reference/item/delete
+func (c *Session) DeleteItems(itemIDs ...string) ([]string, error) {
+	return c.itemAction("item.delete", itemIDs)
+}
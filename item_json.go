@@ -1,6 +1,7 @@
 package zabbix
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
 )
@@ -8,20 +9,47 @@ import (
 // jItem is a private map for the Zabbix API Host object.
 // See: https://www.zabbix.com/documentation/4.0/manual/api/reference/item/get
 type jItem struct {
-	HostID    string `json:"hostid,omitempty"`
-	ItemID    string `json:"itemid"`
-	ItemName  string `json:"name"`
-	ItemDescr string `json:"description,omitempty"`
-	LastClock string `json:"lastclock,omitempty"`
-	LastValue string `json:"lastvalue,omitempty"`
-	LastValueType string `json:"value_type"`
+	HostID        string              `json:"hostid,omitempty"`
+	ItemID        string              `json:"itemid"`
+	ItemName      string              `json:"name"`
+	ItemDescr     string              `json:"description,omitempty"`
+	LastClock     string              `json:"lastclock,omitempty"`
+	LastValue     string              `json:"lastvalue,omitempty"`
+	LastValueType string              `json:"value_type"`
+	Key           string              `json:"key_"`
+	Type          string              `json:"type"`
+	Delay         string              `json:"delay"`
+	Units         string              `json:"units"`
+	History       string              `json:"history"`
+	Trends        string              `json:"trends"`
+	ValueMapID    string              `json:"valuemapid"`
+	ValueMap      json.RawMessage     `json:"valuemap,omitempty"`
+	MasterItemID  string              `json:"master_itemid"`
+	InterfaceID   string              `json:"interfaceid"`
+	TemplateID    string              `json:"templateid"`
+	Status        string              `json:"status"`
+	State         string              `json:"state"`
+	Error         string              `json:"error"`
+	Params        string              `json:"params"`
+	Tags          []ItemTag           `json:"tags,omitempty"`
+	Preprocessing []ItemPreprocessing `json:"preprocessing,omitempty"`
+}
+
+// atoi parses the given integer field of a JSON object, returning zero if the
+// field was not returned.
+func atoi(s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	return strconv.Atoi(s)
 }
 
 // Item returns a native Go Item struct mapped from the given JSON Item data.
 func (c *jItem) Item() (*Item, error) {
 	var err error
 	item := &Item{}
-	item.HostID, err = strconv.Atoi(c.HostID)
+	item.HostID, err = atoi(c.HostID)
 	if err != nil {
 		return nil, fmt.Errorf("Error parsing Host ID: %v", err)
 	}
@@ -32,17 +60,56 @@ func (c *jItem) Item() (*Item, error) {
 	item.ItemName = c.ItemName
 	item.ItemDescr = c.ItemDescr
 
-	item.LastClock, err = strconv.Atoi(c.LastClock)
+	// lastclock is not set for template items
+	item.LastClock, err = atoi(c.LastClock)
 	if err != nil {
 		return nil, fmt.Errorf("Error parsing Item LastClock: %v", err)
 	}
 	item.LastValue = c.LastValue
 
-	item.LastValueType, err = strconv.Atoi(c.LastValueType)
+	item.LastValueType, err = atoi(c.LastValueType)
 	if err != nil {
 		return nil, fmt.Errorf("Error parsing Item LastValueType: %v", err)
 	}
-	return item, err
+
+	item.Key = c.Key
+	item.Delay = c.Delay
+	item.Units = c.Units
+	item.History = c.History
+	item.Trends = c.Trends
+	item.ValueMapID = c.ValueMapID
+	item.MasterItemID = c.MasterItemID
+	item.InterfaceID = c.InterfaceID
+	item.TemplateID = c.TemplateID
+	item.Error = c.Error
+	item.Params = c.Params
+	item.Tags = c.Tags
+	item.Preprocessing = c.Preprocessing
+
+	item.Type, err = atoi(c.Type)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing Item Type: %v", err)
+	}
+
+	item.Status, err = atoi(c.Status)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing Item Status: %v", err)
+	}
+
+	item.State, err = atoi(c.State)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing Item State: %v", err)
+	}
+
+	// the value map is returned as an empty array if not set
+	if len(c.ValueMap) > 0 && c.ValueMap[0] == '{' {
+		item.ValueMap = &ValueMap{}
+		if err := json.Unmarshal(c.ValueMap, item.ValueMap); err != nil {
+			return nil, fmt.Errorf("Error parsing Item ValueMap: %v", err)
+		}
+	}
+
+	return item, nil
 }
 
 // jItems is a slice of jItems structs.
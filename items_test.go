@@ -0,0 +1,33 @@
+package zabbix
+
+import (
+	"testing"
+)
+
+func TestItems(t *testing.T) {
+	session := GetTestSession(t)
+
+	params := ItemGetParams{
+		Templated:           true,
+		SelectPreprocessing: SelectExtendedOutput,
+		SelectTags:          SelectExtendedOutput,
+		SelectValueMap:      SelectExtendedOutput,
+	}
+
+	items, err := session.GetItems(params)
+	if err != nil {
+		t.Fatalf("Error getting Items: %v", err)
+	}
+
+	for i, item := range items {
+		if item.ItemID == 0 {
+			t.Fatalf("Item %d returned in response body has no Item ID", i)
+		}
+
+		if item.Key == "" {
+			t.Fatalf("Item %d returned in response body has no key", i)
+		}
+	}
+
+	t.Logf("Validated %d Items", len(items))
+}
@@ -3,6 +3,7 @@ package zabbix
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 )
@@ -25,6 +26,24 @@ const (
 	EveryMonth
 )
 
+const (
+	// MaintenancePeriodOnce is a maintenance time period which occurs once.
+	MaintenancePeriodOnce = 0
+
+	// MaintenancePeriodDaily is a maintenance time period which occurs every
+	// given number of days.
+	MaintenancePeriodDaily = 2
+
+	// MaintenancePeriodWeekly is a maintenance time period which occurs on the
+	// given days of every given number of weeks.
+	MaintenancePeriodWeekly = 3
+
+	// MaintenancePeriodMonthly is a maintenance time period which occurs on
+	// the given day, or the given day of week of the given week, of the given
+	// months.
+	MaintenancePeriodMonthly = 4
+)
+
 type Maintenance struct {
 	MaintenanceID string
 	Name          string
@@ -35,6 +54,18 @@ type Maintenance struct {
 	ServicePeriod       int
 	Type                MaintenanceType
 	ActionEvalTypeAndOr TagsEvaltype
+
+	// Hosts is only populated if MaintenanceGetParams.SelectHosts is given in
+	// the query parameters that returned this Maintenance.
+	Hosts []Host
+
+	// Groups is only populated if MaintenanceGetParams.SelectGroups is given
+	// in the query parameters that returned this Maintenance.
+	Groups []Hostgroup
+
+	// Timeperiods is only populated if MaintenanceGetParams.SelectTimeperiods
+	// is given in the query parameters that returned this Maintenance.
+	Timeperiods []Timeperiods
 }
 
 type MaintenanceGetParams struct {
@@ -81,7 +112,7 @@ type MaintenanceCreateResponse struct {
 // GetMaintenance queries the Zabbix API for Maintenance matching the given search
 // parameters.
 func (s *Session) GetMaintenance(params *MaintenanceGetParams) ([]Maintenance, error) {
-	jmaintenance := make([]JMaintenance, 0)
+	jmaintenance := make([]jMaintenanceResult, 0)
 	err := s.Get("maintenance.get", params, &jmaintenance)
 	if err != nil {
 		return nil, err
@@ -104,6 +135,57 @@ func (s *Session) GetMaintenance(params *MaintenanceGetParams) ([]Maintenance, e
 	return out, nil
 }
 
+// GetHostMaintenances queries the Zabbix API for the Maintenances of the
+// given hosts, directly or by the given host groups, with their hosts, groups
+// and time periods. Maintenances of both are returned once.
+//
+// ErrNotFound is returned if no Maintenance was found.
+func (s *Session) GetHostMaintenances(hostIDs, groupIDs []string) ([]Maintenance, error) {
+	out := make([]Maintenance, 0)
+	seen := make(map[string]bool)
+	for _, params := range []*MaintenanceGetParams{
+		{Hostids: hostIDs},
+		{Groupids: groupIDs},
+	} {
+		if len(params.Hostids) == 0 && len(params.Groupids) == 0 {
+			continue
+		}
+
+		params.SelectHosts = SelectFields{"hostid"}
+		params.SelectGroups = SelectFields{"groupid"}
+		params.SelectTimeperiods = SelectExtendedOutput
+
+		found, err := s.GetMaintenance(params)
+		if err != nil && err != ErrNotFound {
+			return nil, err
+		}
+
+		for _, maintenance := range found {
+			if !seen[maintenance.MaintenanceID] {
+				seen[maintenance.MaintenanceID] = true
+				out = append(out, maintenance)
+			}
+		}
+	}
+
+	if len(out) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return out, nil
+}
+
+// CountMaintenance queries the Zabbix API for the number of Maintenance
+// matching the given search parameters.
+func (s *Session) CountMaintenance(params *MaintenanceGetParams) (int, error) {
+	var p MaintenanceGetParams
+	if params != nil {
+		p = *params
+	}
+	p.CountOutput = true
+	return s.count("maintenance.get", p)
+}
+
 func (s *Session) CreateMaintenance(params *MaintenanceCreateParams) (response MaintenanceCreateResponse, err error) {
 	if err = params.FillHostIDs(s); err != nil {
 		return
@@ -153,3 +235,155 @@ func (c *MaintenanceCreateParams) FillFields(Object *Maintenance) *MaintenanceCr
 
 	return c
 }
+
+// AppliesTo returns true if the Maintenance is assigned to any of the given
+// hosts, directly or by any of the given host groups.
+//
+// The Maintenance must have been queried with MaintenanceGetParams.SelectHosts
+// and SelectGroups.
+func (m *Maintenance) AppliesTo(hosts []Host, groups []Hostgroup) bool {
+	for _, a := range m.Hosts {
+		for _, b := range hosts {
+			if a.HostID == b.HostID {
+				return true
+			}
+		}
+	}
+
+	for _, a := range m.Groups {
+		for _, b := range groups {
+			if a.GroupID == b.GroupID {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// MaintenanceWindow is a time range during which a Maintenance is active.
+type MaintenanceWindow struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Windows returns the time ranges between from and till during which the
+// Maintenance is active, in chronological order. The time periods of the
+// Maintenance are evaluated in the given time zone, which should be the time
+// zone of the Zabbix server.
+//
+// The Maintenance must have been queried with
+// MaintenanceGetParams.SelectTimeperiods.
+func (m *Maintenance) Windows(from, till time.Time, loc *time.Location) []MaintenanceWindow {
+	if m.ActiveSince.After(from) {
+		from = m.ActiveSince
+	}
+	if m.ActiveTill.Before(till) {
+		till = m.ActiveTill
+	}
+
+	out := make([]MaintenanceWindow, 0)
+	if !from.Before(till) {
+		return out
+	}
+
+	add := func(start time.Time, period int) {
+		end := start.Add(time.Duration(period) * time.Second)
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(till) {
+			end = till
+		}
+		if start.Before(end) {
+			out = append(out, MaintenanceWindow{Start: start, End: end})
+		}
+	}
+
+	for _, tp := range m.Timeperiods {
+		if tp.TimeperiodType == MaintenancePeriodOnce {
+			add(time.Unix(tp.StartDate, 0), tp.Period)
+			continue
+		}
+
+		// each day on which the period may start, including days on which a
+		// period that overlaps from started
+		first := from.In(loc).Add(-time.Duration(tp.Period) * time.Second)
+		day := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, loc)
+		since := m.ActiveSince.In(loc)
+		since = time.Date(since.Year(), since.Month(), since.Day(), 0, 0, 0, 0, loc)
+		for ; day.Before(till); day = day.AddDate(0, 0, 1) {
+			if tp.occursOn(day, since) {
+				start := day.Add(time.Duration(tp.StartTime) * time.Second)
+				add(start, tp.Period)
+			}
+		}
+	}
+
+	// merge overlapping windows
+	sort.Slice(out, func(i, j int) bool { return out[i].Start.Before(out[j].Start) })
+	merged := make([]MaintenanceWindow, 0, len(out))
+	for _, w := range out {
+		if n := len(merged); n > 0 && !w.Start.After(merged[n-1].End) {
+			if w.End.After(merged[n-1].End) {
+				merged[n-1].End = w.End
+			}
+			continue
+		}
+		merged = append(merged, w)
+	}
+
+	return merged
+}
+
+// occursOn returns true if a recurring time period starts on the given day.
+// Daily and weekly periods are counted from the day the Maintenance became
+// active.
+func (tp *Timeperiods) occursOn(day, since time.Time) bool {
+	every := tp.Every
+	if every < 1 {
+		every = 1
+	}
+
+	// days and weeks between the local midnights, ignoring DST offsets
+	offset := day.Sub(since) + 12*time.Hour
+	if offset < 0 {
+		return false
+	}
+	days := int(offset / (24 * time.Hour))
+
+	// day of week bitmask starts with Monday
+	weekday := (int(day.Weekday()) + 6) % 7
+
+	switch tp.TimeperiodType {
+	case MaintenancePeriodDaily:
+		return days%every == 0
+
+	case MaintenancePeriodWeekly:
+		sinceWeekday := (int(since.Weekday()) + 6) % 7
+		weeks := (days + sinceWeekday) / 7
+		return weeks%every == 0 && tp.Dayofweek&(1<<uint(weekday)) != 0
+
+	case MaintenancePeriodMonthly:
+		if tp.Month&(1<<uint(day.Month()-1)) == 0 {
+			return false
+		}
+
+		if tp.Day > 0 {
+			return day.Day() == tp.Day
+		}
+
+		if tp.Dayofweek&(1<<uint(weekday)) == 0 {
+			return false
+		}
+
+		// every is the week of the month, where 5 is the last week
+		week := (day.Day()-1)/7 + 1
+		if every == 5 {
+			return day.AddDate(0, 0, 7).Month() != day.Month()
+		}
+		return week == every
+	}
+
+	return false
+}
@@ -25,6 +25,100 @@ type Timeperiods struct {
 	Dayofweek      int `json:"dayofweek,string"`
 	StartTime      int `json:"start_time,string"`
 	Period         int `json:"period,string"`
+
+	// Month is a bitmask of the months of monthly periods, starting with
+	// January.
+	Month int `json:"month,string,omitempty"`
+
+	// Day is the day of the month of monthly periods.
+	Day int `json:"day,string,omitempty"`
+
+	// StartDate is the Unix timestamp of periods which occur once.
+	StartDate int64 `json:"start_date,string,omitempty"`
+}
+
+// jTimeperiod is a private map for the Zabbix API Time period object, which
+// returns all values as strings.
+type jTimeperiod struct {
+	TimeperiodType string `json:"timeperiod_type"`
+	Every          string `json:"every"`
+	Dayofweek      string `json:"dayofweek"`
+	StartTime      string `json:"start_time"`
+	Period         string `json:"period"`
+	Month          string `json:"month"`
+	Day            string `json:"day"`
+	StartDate      string `json:"start_date"`
+}
+
+// Timeperiods returns a native Go Timeperiods struct mapped from the given JSON
+// Time period data.
+func (c *jTimeperiod) Timeperiods() (*Timeperiods, error) {
+	var err error
+	tp := &Timeperiods{}
+	for _, field := range []struct {
+		Name  string
+		Value string
+		Dest  *int
+	}{
+		{"type", c.TimeperiodType, &tp.TimeperiodType},
+		{"every", c.Every, &tp.Every},
+		{"day of week", c.Dayofweek, &tp.Dayofweek},
+		{"start time", c.StartTime, &tp.StartTime},
+		{"period", c.Period, &tp.Period},
+		{"month", c.Month, &tp.Month},
+		{"day", c.Day, &tp.Day},
+	} {
+		if *field.Dest, err = atoi(field.Value); err != nil {
+			return nil, fmt.Errorf("Error parsing Time period %s: %v", field.Name, err)
+		}
+	}
+
+	startDate, err := atoi(c.StartDate)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing Time period start date: %v", err)
+	}
+	tp.StartDate = int64(startDate)
+
+	return tp, nil
+}
+
+// jMaintenanceResult is a Maintenance returned by maintenance.get with the
+// selected related objects.
+type jMaintenanceResult struct {
+	JMaintenance
+	Hosts       jHosts        `json:"hosts"`
+	Groups      jHostgroups   `json:"groups"`
+	Timeperiods []jTimeperiod `json:"timeperiods"`
+}
+
+// Maintenance returns a native Go Maintenance struct mapped from the given JSON
+// Maintenance data.
+func (c *jMaintenanceResult) Maintenance() (*Maintenance, error) {
+	maintenance, err := c.JMaintenance.Maintenance()
+	if err != nil {
+		return nil, err
+	}
+
+	if maintenance.Hosts, err = c.Hosts.Hosts(); err != nil {
+		return nil, err
+	}
+
+	if maintenance.Groups, err = c.Groups.Hostgroups(); err != nil {
+		return nil, err
+	}
+
+	if c.Timeperiods != nil {
+		maintenance.Timeperiods = make([]Timeperiods, len(c.Timeperiods))
+		for i, jtp := range c.Timeperiods {
+			tp, err := jtp.Timeperiods()
+			if err != nil {
+				return nil, err
+			}
+			maintenance.Timeperiods[i] = *tp
+		}
+	}
+
+	return maintenance, nil
 }
 
 // Maintenance returns a native Go Maintenance struct mapped from the given JSON Maintenance
@@ -0,0 +1,146 @@
+package zabbix
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMaintenanceMapping(t *testing.T) {
+	data := `{
+		"maintenanceid": "3",
+		"name": "Patching",
+		"active_since": "1600000000",
+		"active_till": "1700000000",
+		"maintenance_type": "0",
+		"tags_evaltype": "0",
+		"hosts": [{"hostid": "10084", "host": "web01"}],
+		"timeperiods": [{"timeperiod_type": "3", "every": "1", "dayofweek": "64", "start_time": "7200", "period": "3600", "month": "0", "day": "0", "start_date": "0"}]
+	}`
+
+	var jmaintenance jMaintenanceResult
+	if err := json.Unmarshal([]byte(data), &jmaintenance); err != nil {
+		t.Fatalf("Error unmarshalling Maintenance: %v", err)
+	}
+
+	maintenance, err := jmaintenance.Maintenance()
+	if err != nil {
+		t.Fatalf("Error mapping Maintenance: %v", err)
+	}
+
+	if len(maintenance.Hosts) != 1 || maintenance.Hosts[0].Hostname != "web01" {
+		t.Errorf("Unexpected Maintenance hosts: %+v", maintenance.Hosts)
+	}
+
+	if len(maintenance.Timeperiods) != 1 || maintenance.Timeperiods[0].TimeperiodType != MaintenancePeriodWeekly || maintenance.Timeperiods[0].Dayofweek != 64 {
+		t.Errorf("Unexpected Maintenance time periods: %+v", maintenance.Timeperiods)
+	}
+}
+
+func TestMaintenanceWindows(t *testing.T) {
+	date := func(day, hour int) time.Time {
+		return time.Date(2021, time.March, day, hour, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		Name       string
+		Timeperiod Timeperiods
+		Expect     []MaintenanceWindow
+	}{
+		{
+			"once",
+			Timeperiods{TimeperiodType: MaintenancePeriodOnce, StartDate: date(3, 22).Unix(), Period: 4 * 3600},
+			[]MaintenanceWindow{{date(3, 22), date(4, 2)}},
+		},
+		{
+			"every second day",
+			Timeperiods{TimeperiodType: MaintenancePeriodDaily, Every: 2, StartTime: 3600, Period: 3600},
+			[]MaintenanceWindow{{date(1, 1), date(1, 2)}, {date(3, 1), date(3, 2)}, {date(5, 1), date(5, 2)}, {date(7, 1), date(7, 2)}},
+		},
+		{
+			// 1 March 2021 is a Monday
+			"weekly on sunday and wednesday",
+			Timeperiods{TimeperiodType: MaintenancePeriodWeekly, Every: 1, Dayofweek: 64 | 4, StartTime: 23 * 3600, Period: 2 * 3600},
+			[]MaintenanceWindow{{date(3, 23), date(4, 1)}, {date(7, 23), date(8, 0)}},
+		},
+		{
+			"first monday of march",
+			Timeperiods{TimeperiodType: MaintenancePeriodMonthly, Every: 1, Month: 4, Dayofweek: 1, Period: 3600},
+			[]MaintenanceWindow{{date(1, 0), date(1, 1)}},
+		},
+		{
+			"last sunday of march",
+			Timeperiods{TimeperiodType: MaintenancePeriodMonthly, Every: 5, Month: 4, Dayofweek: 64, Period: 3600},
+			[]MaintenanceWindow{},
+		},
+	}
+
+	for _, test := range tests {
+		m := &Maintenance{
+			ActiveSince: date(1, 0),
+			ActiveTill:  date(31, 0),
+			Timeperiods: []Timeperiods{test.Timeperiod},
+		}
+
+		windows := m.Windows(date(1, 0), date(8, 0), time.UTC)
+		if len(windows) != len(test.Expect) {
+			t.Errorf("%s: expected %d windows, got %v", test.Name, len(test.Expect), windows)
+			continue
+		}
+
+		for i, w := range test.Expect {
+			if !windows[i].Start.Equal(w.Start) || !windows[i].End.Equal(w.End) {
+				t.Errorf("%s: expected window %v - %v, got %v - %v", test.Name, w.Start, w.End, windows[i].Start, windows[i].End)
+			}
+		}
+	}
+}
+
+func TestMaintenanceAppliesTo(t *testing.T) {
+	m := &Maintenance{
+		Hosts:  []Host{{HostID: "101"}},
+		Groups: []Hostgroup{{GroupID: "2"}},
+	}
+
+	tests := []struct {
+		Hosts  []Host
+		Groups []Hostgroup
+		Expect bool
+	}{
+		{[]Host{{HostID: "101"}}, nil, true},
+		{[]Host{{HostID: "102"}}, []Hostgroup{{GroupID: "2"}}, true},
+		{[]Host{{HostID: "102"}}, []Hostgroup{{GroupID: "3"}}, false},
+		{nil, nil, false},
+	}
+
+	for i, test := range tests {
+		if applies := m.AppliesTo(test.Hosts, test.Groups); applies != test.Expect {
+			t.Errorf("Expected AppliesTo %v for test %d, got %v", test.Expect, i, applies)
+		}
+	}
+}
+
+func TestCountMaintenanceNilParams(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     uint64 `json:"id"`
+			Params struct {
+				CountOutput bool `json:"countOutput"`
+			} `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || !req.Params.CountOutput {
+			t.Errorf("Unexpected count request: %+v (%v)", req, err)
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","result":"3","id":%d}`, req.ID)
+	}))
+	defer srv.Close()
+
+	session := &Session{URL: srv.URL, client: srv.Client()}
+	n, err := session.CountMaintenance(nil)
+	if err != nil || n != 3 {
+		t.Errorf("Expected 3 Maintenances, got %d (%v)", n, err)
+	}
+}
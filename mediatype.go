@@ -0,0 +1,34 @@
+package zabbix
+
+const (
+	// MediaTypeEmail indicates that a Media Type sends email.
+	MediaTypeEmail = 0
+
+	// MediaTypeScript indicates that a Media Type runs a script.
+	MediaTypeScript = 1
+
+	// MediaTypeSMS indicates that a Media Type sends SMS.
+	MediaTypeSMS = 2
+
+	// MediaTypeWebhook indicates that a Media Type calls a webhook.
+	MediaTypeWebhook = 4
+)
+
+// MediaType represents a Zabbix Media Type, which delivers the messages of
+// Alerts.
+//
+// See: https://www.zabbix.com/documentation/current/manual/api/reference/mediatype/object
+type MediaType struct {
+	// MediaTypeID is the unique ID of the Media Type.
+	MediaTypeID string
+
+	// Name is the name of the Media Type.
+	Name string
+
+	// Type is the transport of the Media Type and must be one of the
+	// MediaType constants.
+	Type int
+
+	// Enabled indicates if the Media Type is enabled.
+	Enabled bool
+}
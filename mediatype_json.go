@@ -0,0 +1,60 @@
+package zabbix
+
+import (
+	"fmt"
+)
+
+// jMediaType is a private map for the Zabbix API Media Type object.
+// See: https://www.zabbix.com/documentation/current/manual/api/reference/mediatype/object
+type jMediaType struct {
+	MediaTypeID string `json:"mediatypeid"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Type        string `json:"type"`
+	Status      string `json:"status"`
+}
+
+// MediaType returns a native Go MediaType struct mapped from the given JSON
+// Media Type data.
+func (c *jMediaType) MediaType() (*MediaType, error) {
+	var err error
+	mediaType := &MediaType{
+		MediaTypeID: c.MediaTypeID,
+		Name:        c.Name,
+		Enabled:     c.Status != "1",
+	}
+
+	// the name is named description before Zabbix 4.4
+	if mediaType.Name == "" {
+		mediaType.Name = c.Description
+	}
+
+	if mediaType.Type, err = atoi(c.Type); err != nil {
+		return nil, fmt.Errorf("Error parsing Media Type type: %v", err)
+	}
+
+	return mediaType, nil
+}
+
+// jMediaTypes is a slice of jMediaType structs.
+type jMediaTypes []jMediaType
+
+// MediaTypes returns a native Go slice of MediaTypes mapped from the given JSON
+// Media Types data.
+func (c jMediaTypes) MediaTypes() ([]MediaType, error) {
+	if c == nil {
+		return nil, nil
+	}
+
+	out := make([]MediaType, len(c))
+	for i, jmediaType := range c {
+		mediaType, err := jmediaType.MediaType()
+		if err != nil {
+			return nil, fmt.Errorf("Error unmarshalling Media Type %d in JSON data: %v", i, err)
+		}
+
+		out[i] = *mediaType
+	}
+
+	return out, nil
+}
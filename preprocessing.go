@@ -0,0 +1,770 @@
+package zabbix
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// PreprocessingTypeMultiplier multiplies a numeric value by a constant.
+	PreprocessingTypeMultiplier = 1
+
+	// PreprocessingTypeRightTrim removes the given characters from the end of
+	// a value.
+	PreprocessingTypeRightTrim = 2
+
+	// PreprocessingTypeLeftTrim removes the given characters from the start of
+	// a value.
+	PreprocessingTypeLeftTrim = 3
+
+	// PreprocessingTypeTrim removes the given characters from both ends of a
+	// value.
+	PreprocessingTypeTrim = 4
+
+	// PreprocessingTypeRegex extracts the output of a regular expression from a
+	// value.
+	PreprocessingTypeRegex = 5
+
+	// PreprocessingTypeBoolToDecimal converts a boolean value to 0 or 1.
+	PreprocessingTypeBoolToDecimal = 6
+
+	// PreprocessingTypeOctalToDecimal converts an octal value to decimal.
+	PreprocessingTypeOctalToDecimal = 7
+
+	// PreprocessingTypeHexToDecimal converts a hexadecimal value to decimal.
+	PreprocessingTypeHexToDecimal = 8
+
+	// PreprocessingTypeSimpleChange calculates the difference between the
+	// current and previous values.
+	PreprocessingTypeSimpleChange = 9
+
+	// PreprocessingTypeChangePerSecond calculates the speed of change per
+	// second between the current and previous values.
+	PreprocessingTypeChangePerSecond = 10
+
+	// PreprocessingTypeXMLXPath extracts a value from XML data using an XPath
+	// expression.
+	PreprocessingTypeXMLXPath = 11
+
+	// PreprocessingTypeJSONPath extracts a value from JSON data using a
+	// JSONPath expression.
+	PreprocessingTypeJSONPath = 12
+
+	// PreprocessingTypeInRange validates that a numeric value is within a
+	// range.
+	PreprocessingTypeInRange = 13
+
+	// PreprocessingTypeMatchesRegex validates that a value matches a regular
+	// expression.
+	PreprocessingTypeMatchesRegex = 14
+
+	// PreprocessingTypeNotMatchesRegex validates that a value does not match a
+	// regular expression.
+	PreprocessingTypeNotMatchesRegex = 15
+
+	// PreprocessingTypeCheckJSONError fails if a JSONPath expression matches
+	// an error message in JSON data.
+	PreprocessingTypeCheckJSONError = 16
+
+	// PreprocessingTypeCheckXMLError fails if an XPath expression matches an
+	// error message in XML data.
+	PreprocessingTypeCheckXMLError = 17
+
+	// PreprocessingTypeCheckRegexError fails if a regular expression matches
+	// an error message in a value.
+	PreprocessingTypeCheckRegexError = 18
+
+	// PreprocessingTypeDiscardUnchanged discards a value if it has not changed.
+	PreprocessingTypeDiscardUnchanged = 19
+
+	// PreprocessingTypeDiscardUnchangedHeartbeat discards a value if it has not
+	// changed within the given heartbeat period.
+	PreprocessingTypeDiscardUnchangedHeartbeat = 20
+
+	// PreprocessingTypeJavaScript transforms a value with a JavaScript script.
+	PreprocessingTypeJavaScript = 21
+
+	// PreprocessingTypePrometheusPattern extracts a value from Prometheus
+	// metrics using a pattern.
+	PreprocessingTypePrometheusPattern = 22
+
+	// PreprocessingTypePrometheusToJSON converts Prometheus metrics to JSON.
+	PreprocessingTypePrometheusToJSON = 23
+
+	// PreprocessingTypeCSVToJSON converts CSV data to JSON.
+	PreprocessingTypeCSVToJSON = 24
+
+	// PreprocessingTypeReplace replaces all occurrences of a string in a value.
+	PreprocessingTypeReplace = 25
+
+	// PreprocessingTypeCheckUnsupported checks if an Item is not supported.
+	PreprocessingTypeCheckUnsupported = 26
+
+	// PreprocessingTypeXMLToJSON converts XML data to JSON.
+	PreprocessingTypeXMLToJSON = 27
+)
+
+const (
+	// PreprocessingErrorHandlerDefault sets the Item to not supported if a
+	// preprocessing step fails.
+	PreprocessingErrorHandlerDefault = 0
+
+	// PreprocessingErrorHandlerDiscard discards the value if a preprocessing
+	// step fails.
+	PreprocessingErrorHandlerDiscard = 1
+
+	// PreprocessingErrorHandlerSetValue sets the value to the error handler
+	// parameters if a preprocessing step fails.
+	PreprocessingErrorHandlerSetValue = 2
+
+	// PreprocessingErrorHandlerSetError sets the error message of the Item to
+	// the error handler parameters if a preprocessing step fails.
+	PreprocessingErrorHandlerSetError = 3
+)
+
+// ErrPreprocessingUnsupported describes a preprocessing step which can not be
+// applied locally by a Preprocessor.
+var ErrPreprocessingUnsupported = errors.New("Preprocessing step type is not supported locally")
+
+// PreprocessingMultiplier returns a preprocessing step which multiplies
+// numeric values by the given factor.
+func PreprocessingMultiplier(factor string) ItemPreprocessing {
+	return ItemPreprocessing{Type: PreprocessingTypeMultiplier, Params: factor}
+}
+
+// PreprocessingTrim returns a preprocessing step which removes the given
+// characters from both ends of a value.
+func PreprocessingTrim(chars string) ItemPreprocessing {
+	return ItemPreprocessing{Type: PreprocessingTypeTrim, Params: chars}
+}
+
+// PreprocessingLeftTrim returns a preprocessing step which removes the given
+// characters from the start of a value.
+func PreprocessingLeftTrim(chars string) ItemPreprocessing {
+	return ItemPreprocessing{Type: PreprocessingTypeLeftTrim, Params: chars}
+}
+
+// PreprocessingRightTrim returns a preprocessing step which removes the given
+// characters from the end of a value.
+func PreprocessingRightTrim(chars string) ItemPreprocessing {
+	return ItemPreprocessing{Type: PreprocessingTypeRightTrim, Params: chars}
+}
+
+// PreprocessingRegex returns a preprocessing step which matches values against
+// the given regular expression and returns the given output template, where
+// \N is replaced with the Nth capture group.
+func PreprocessingRegex(pattern, output string) ItemPreprocessing {
+	return ItemPreprocessing{Type: PreprocessingTypeRegex, Params: pattern + "\n" + output}
+}
+
+// PreprocessingXPath returns a preprocessing step which extracts a value from
+// XML data using the given XPath expression.
+func PreprocessingXPath(expression string) ItemPreprocessing {
+	return ItemPreprocessing{Type: PreprocessingTypeXMLXPath, Params: expression}
+}
+
+// PreprocessingJSONPath returns a preprocessing step which extracts a value
+// from JSON data using the given JSONPath expression.
+func PreprocessingJSONPath(path string) ItemPreprocessing {
+	return ItemPreprocessing{Type: PreprocessingTypeJSONPath, Params: path}
+}
+
+// PreprocessingSimpleChange returns a preprocessing step which calculates the
+// difference between the current and previous values.
+func PreprocessingSimpleChange() ItemPreprocessing {
+	return ItemPreprocessing{Type: PreprocessingTypeSimpleChange}
+}
+
+// PreprocessingChangePerSecond returns a preprocessing step which calculates
+// the speed of change per second between the current and previous values.
+func PreprocessingChangePerSecond() ItemPreprocessing {
+	return ItemPreprocessing{Type: PreprocessingTypeChangePerSecond}
+}
+
+// PreprocessingInRange returns a preprocessing step which fails if a numeric
+// value is outside of the given range. Either limit may be empty.
+func PreprocessingInRange(min, max string) ItemPreprocessing {
+	return ItemPreprocessing{Type: PreprocessingTypeInRange, Params: min + "\n" + max}
+}
+
+// PreprocessingMatchesRegex returns a preprocessing step which fails if a value
+// does not match the given regular expression.
+func PreprocessingMatchesRegex(pattern string) ItemPreprocessing {
+	return ItemPreprocessing{Type: PreprocessingTypeMatchesRegex, Params: pattern}
+}
+
+// PreprocessingNotMatchesRegex returns a preprocessing step which fails if a
+// value matches the given regular expression.
+func PreprocessingNotMatchesRegex(pattern string) ItemPreprocessing {
+	return ItemPreprocessing{Type: PreprocessingTypeNotMatchesRegex, Params: pattern}
+}
+
+// PreprocessingDiscardUnchanged returns a preprocessing step which discards
+// values that are equal to the previous value.
+func PreprocessingDiscardUnchanged() ItemPreprocessing {
+	return ItemPreprocessing{Type: PreprocessingTypeDiscardUnchanged}
+}
+
+// PreprocessingDiscardUnchangedHeartbeat returns a preprocessing step which
+// discards values that are equal to the previous value, unless the given
+// heartbeat period (e.g. "1h") has elapsed since the last value was kept.
+func PreprocessingDiscardUnchangedHeartbeat(heartbeat string) ItemPreprocessing {
+	return ItemPreprocessing{Type: PreprocessingTypeDiscardUnchangedHeartbeat, Params: heartbeat}
+}
+
+// PreprocessingJavaScript returns a preprocessing step which transforms values
+// with the given JavaScript function body. JavaScript steps can not be applied
+// by a Preprocessor.
+func PreprocessingJavaScript(script string) ItemPreprocessing {
+	return ItemPreprocessing{Type: PreprocessingTypeJavaScript, Params: script}
+}
+
+// PreprocessingPrometheusPattern returns a preprocessing step which extracts a
+// value from Prometheus metrics matching the given pattern.
+//
+// Result must be one of "value", "label" or "function". For "label", param is
+// the name of the label to return. For "function", param is one of "sum",
+// "min", "max", "avg" or "count".
+func PreprocessingPrometheusPattern(pattern, result, param string) ItemPreprocessing {
+	return ItemPreprocessing{
+		Type:   PreprocessingTypePrometheusPattern,
+		Params: pattern + "\n" + result + "\n" + param,
+	}
+}
+
+// PreprocessingCSVToJSON returns a preprocessing step which converts CSV data
+// to a JSON array of objects. If header is true, the first line of the CSV
+// data is used for the object keys.
+func PreprocessingCSVToJSON(delimiter, quote string, header bool) ItemPreprocessing {
+	withHeader := "0"
+	if header {
+		withHeader = "1"
+	}
+
+	return ItemPreprocessing{
+		Type:   PreprocessingTypeCSVToJSON,
+		Params: delimiter + "\n" + quote + "\n" + withHeader,
+	}
+}
+
+// PreprocessingReplace returns a preprocessing step which replaces all
+// occurrences of search in a value with replace.
+func PreprocessingReplace(search, replace string) ItemPreprocessing {
+	return ItemPreprocessing{Type: PreprocessingTypeReplace, Params: search + "\n" + replace}
+}
+
+// WithErrorHandler returns a copy of the preprocessing step with the given
+// error handler, which must be one of the PreprocessingErrorHandler constants.
+func (c ItemPreprocessing) WithErrorHandler(handler int, params string) ItemPreprocessing {
+	c.ErrorHandler = handler
+	c.ErrorHandlerParams = params
+	return c
+}
+
+// params returns the line delimited parameters of the preprocessing step,
+// padded to at least n parameters.
+func (c *ItemPreprocessing) params(n int) []string {
+	params := strings.Split(c.Params, "\n")
+	for len(params) < n {
+		params = append(params, "")
+	}
+
+	return params
+}
+
+// PreprocessingError describes a failed preprocessing step.
+type PreprocessingError struct {
+	// Step is the index of the failed step.
+	Step int
+
+	// Message is the error message of the failed step, or the custom error
+	// message of the PreprocessingErrorHandlerSetError error handler.
+	Message string
+}
+
+// Error returns the string representation of a PreprocessingError.
+func (e *PreprocessingError) Error() string {
+	return fmt.Sprintf("Preprocessing step %d failed: %s", e.Step+1, e.Message)
+}
+
+// preprocessingState is the history of a stateful preprocessing step.
+type preprocessingState struct {
+	value string
+	clock time.Time
+	set   bool
+}
+
+// A Preprocessor applies a chain of Item preprocessing steps to values locally,
+// so that Item definitions can be tested before they are created on a Zabbix
+// server.
+//
+// A Preprocessor keeps the previous values required by steps such as
+// PreprocessingTypeChangePerSecond, so values must be given in the order they
+// would be received. Regular expressions are evaluated with the Go regexp
+// package, which does not support all PCRE features used by Zabbix.
+type Preprocessor struct {
+	steps []ItemPreprocessing
+	state []preprocessingState
+}
+
+// NewPreprocessor returns a new Preprocessor for the given steps.
+func NewPreprocessor(steps ...ItemPreprocessing) *Preprocessor {
+	return &Preprocessor{
+		steps: steps,
+		state: make([]preprocessingState, len(steps)),
+	}
+}
+
+// Preprocess applies the given steps to a single value with no previous
+// values.
+//
+// See Preprocessor.Process.
+func Preprocess(value string, steps ...ItemPreprocessing) (result string, ok bool, err error) {
+	return NewPreprocessor(steps...).Process(value, time.Now())
+}
+
+// Process applies all preprocessing steps to the given value, received at the
+// given time.
+//
+// If ok is false, the value was discarded by a preprocessing step or error
+// handler. A *PreprocessingError is returned if a step failed and its error
+// handler did not discard or replace the value. ErrPreprocessingUnsupported is
+// returned for steps which can not be applied locally.
+func (c *Preprocessor) Process(value string, clock time.Time) (result string, ok bool, err error) {
+	for i := range c.steps {
+		step := &c.steps[i]
+		value, ok, err = c.apply(i, value, clock)
+		if err == ErrPreprocessingUnsupported {
+			return "", false, err
+		}
+
+		if err != nil {
+			switch step.ErrorHandler {
+			case PreprocessingErrorHandlerDiscard:
+				return "", false, nil
+
+			case PreprocessingErrorHandlerSetValue:
+				return step.ErrorHandlerParams, true, nil
+
+			case PreprocessingErrorHandlerSetError:
+				return "", false, &PreprocessingError{Step: i, Message: step.ErrorHandlerParams}
+			}
+
+			return "", false, &PreprocessingError{Step: i, Message: err.Error()}
+		}
+
+		if !ok {
+			return "", false, nil
+		}
+	}
+
+	return value, true, nil
+}
+
+// apply applies a single preprocessing step.
+func (c *Preprocessor) apply(i int, value string, clock time.Time) (string, bool, error) {
+	step := &c.steps[i]
+	state := &c.state[i]
+
+	switch step.Type {
+	case PreprocessingTypeMultiplier:
+		v, err := parseNumeric(value)
+		if err != nil {
+			return "", false, err
+		}
+
+		factor, err := parseNumeric(step.Params)
+		if err != nil {
+			return "", false, fmt.Errorf("invalid multiplier: %v", err)
+		}
+
+		return formatNumeric(v * factor), true, nil
+
+	case PreprocessingTypeRightTrim:
+		return strings.TrimRight(value, step.Params), true, nil
+
+	case PreprocessingTypeLeftTrim:
+		return strings.TrimLeft(value, step.Params), true, nil
+
+	case PreprocessingTypeTrim:
+		return strings.Trim(value, step.Params), true, nil
+
+	case PreprocessingTypeRegex:
+		params := step.params(2)
+		result, matched, err := regexOutput(params[0], params[1], value)
+		if err != nil {
+			return "", false, err
+		}
+
+		if !matched {
+			return "", false, fmt.Errorf("cannot perform regular expression %q match for value of type \"string\"", params[0])
+		}
+
+		return result, true, nil
+
+	case PreprocessingTypeBoolToDecimal:
+		switch strings.ToLower(strings.TrimSpace(value)) {
+		case "true", "t", "yes", "y", "on", "up", "running", "enabled", "available", "ok", "master", "1":
+			return "1", true, nil
+		case "false", "f", "no", "n", "off", "down", "unused", "disabled", "unavailable", "err", "slave", "0":
+			return "0", true, nil
+		}
+
+		return "", false, fmt.Errorf("cannot convert value %q to decimal", value)
+
+	case PreprocessingTypeOctalToDecimal:
+		n, err := strconv.ParseUint(strings.TrimSpace(value), 8, 64)
+		if err != nil {
+			return "", false, fmt.Errorf("cannot convert value %q from octal to decimal", value)
+		}
+
+		return strconv.FormatUint(n, 10), true, nil
+
+	case PreprocessingTypeHexToDecimal:
+		hex := strings.Join(strings.Fields(value), "")
+		n, err := strconv.ParseUint(strings.TrimPrefix(strings.ToLower(hex), "0x"), 16, 64)
+		if err != nil {
+			return "", false, fmt.Errorf("cannot convert value %q from hexadecimal to decimal", value)
+		}
+
+		return strconv.FormatUint(n, 10), true, nil
+
+	case PreprocessingTypeSimpleChange, PreprocessingTypeChangePerSecond:
+		v, err := parseNumeric(value)
+		if err != nil {
+			return "", false, err
+		}
+
+		prev := *state
+		*state = preprocessingState{value: value, clock: clock, set: true}
+		if !prev.set {
+			return "", false, nil // first value is discarded
+		}
+
+		pv, err := parseNumeric(prev.value)
+		if err != nil {
+			return "", false, err
+		}
+
+		// negative changes are discarded, e.g. after a counter reset
+		delta := v - pv
+		if delta < 0 {
+			return "", false, nil
+		}
+
+		if step.Type == PreprocessingTypeSimpleChange {
+			return formatNumeric(delta), true, nil
+		}
+
+		seconds := clock.Sub(prev.clock).Seconds()
+		if seconds <= 0 {
+			return "", false, nil
+		}
+
+		return formatNumeric(delta / seconds), true, nil
+
+	case PreprocessingTypeXMLXPath:
+		return xpathString(step.Params, value)
+
+	case PreprocessingTypeJSONPath:
+		result, err := jsonPathString(step.Params, value)
+		if err != nil {
+			return "", false, err
+		}
+
+		return result, true, nil
+
+	case PreprocessingTypeInRange:
+		v, err := parseNumeric(value)
+		if err != nil {
+			return "", false, err
+		}
+
+		params := step.params(2)
+		if params[0] != "" {
+			min, err := parseNumeric(params[0])
+			if err != nil {
+				return "", false, fmt.Errorf("invalid minimum value: %v", err)
+			}
+
+			if v < min {
+				return "", false, fmt.Errorf("value %s is out of allowed range %s - %s", value, params[0], params[1])
+			}
+		}
+
+		if params[1] != "" {
+			max, err := parseNumeric(params[1])
+			if err != nil {
+				return "", false, fmt.Errorf("invalid maximum value: %v", err)
+			}
+
+			if v > max {
+				return "", false, fmt.Errorf("value %s is out of allowed range %s - %s", value, params[0], params[1])
+			}
+		}
+
+		return value, true, nil
+
+	case PreprocessingTypeMatchesRegex, PreprocessingTypeNotMatchesRegex:
+		re, err := regexp.Compile(step.Params)
+		if err != nil {
+			return "", false, fmt.Errorf("invalid regular expression: %v", err)
+		}
+
+		if re.MatchString(value) != (step.Type == PreprocessingTypeMatchesRegex) {
+			return "", false, fmt.Errorf("value %q does not satisfy regular expression %q check", value, step.Params)
+		}
+
+		return value, true, nil
+
+	case PreprocessingTypeCheckJSONError:
+		// only a non-empty error message fails the step
+		if v, err := jsonPathValue(step.Params, value); err == nil && v != nil {
+			if message, err := jsonValueString(v); err == nil && message != "" {
+				return "", false, errors.New(message)
+			}
+		}
+
+		return value, true, nil
+
+	case PreprocessingTypeCheckXMLError:
+		message, ok, err := xpathString(step.Params, value)
+		if err == ErrPreprocessingUnsupported {
+			return "", false, err
+		}
+
+		if err == nil && ok && message != "" {
+			return "", false, errors.New(message)
+		}
+
+		return value, true, nil
+
+	case PreprocessingTypeCheckRegexError:
+		params := step.params(2)
+		message, matched, err := regexOutput(params[0], params[1], value)
+		if err != nil {
+			return "", false, err
+		}
+
+		if matched {
+			return "", false, errors.New(message)
+		}
+
+		return value, true, nil
+
+	case PreprocessingTypeDiscardUnchanged, PreprocessingTypeDiscardUnchangedHeartbeat:
+		prev := *state
+		if prev.set && prev.value == value {
+			if step.Type == PreprocessingTypeDiscardUnchanged {
+				return "", false, nil
+			}
+
+			heartbeat, err := parseTimeSuffix(step.Params)
+			if err != nil {
+				return "", false, fmt.Errorf("invalid heartbeat: %v", err)
+			}
+
+			if clock.Sub(prev.clock) < heartbeat {
+				return "", false, nil
+			}
+		}
+
+		*state = preprocessingState{value: value, clock: clock, set: true}
+		return value, true, nil
+
+	case PreprocessingTypePrometheusPattern:
+		params := step.params(3)
+		result, err := prometheusPattern(value, params[0], params[1], params[2])
+		if err != nil {
+			return "", false, err
+		}
+
+		return result, true, nil
+
+	case PreprocessingTypeCSVToJSON:
+		params := step.params(3)
+		result, err := csvToJSON(value, params[0], params[1], params[2] == "1")
+		if err != nil {
+			return "", false, err
+		}
+
+		return result, true, nil
+
+	case PreprocessingTypeReplace:
+		params := step.params(2)
+		if params[0] == "" {
+			return value, true, nil
+		}
+
+		return strings.Replace(value, unescapeReplace(params[0]), unescapeReplace(params[1]), -1), true, nil
+	}
+
+	return "", false, ErrPreprocessingUnsupported
+}
+
+// parseNumeric parses a numeric Item value.
+func parseNumeric(value string) (float64, error) {
+	v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0, fmt.Errorf("cannot convert value %q to numeric", value)
+	}
+
+	return v, nil
+}
+
+// formatNumeric formats a numeric Item value without trailing zeros.
+func formatNumeric(v float64) string {
+	if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+		return strconv.FormatFloat(v, 'f', 0, 64)
+	}
+
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// regexOutput matches the given value with the given regular expression and
+// returns the output template with each \N replaced by the Nth capture group.
+func regexOutput(pattern, output, value string) (string, bool, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", false, fmt.Errorf("invalid regular expression: %v", err)
+	}
+
+	match := re.FindStringSubmatch(value)
+	if match == nil {
+		return "", false, nil
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(output); i++ {
+		if output[i] == '\\' && i+1 < len(output) && output[i+1] >= '0' && output[i+1] <= '9' {
+			n := int(output[i+1] - '0')
+			if n < len(match) {
+				b.WriteString(match[n])
+			}
+			i++
+			continue
+		}
+		b.WriteByte(output[i])
+	}
+
+	return b.String(), true, nil
+}
+
+// unescapeReplace expands the escape sequences supported in the parameters of
+// a PreprocessingTypeReplace step.
+func unescapeReplace(s string) string {
+	return strings.NewReplacer(`\\`, `\`, `\n`, "\n", `\r`, "\r", `\t`, "\t", `\s`, " ").Replace(s)
+}
+
+// csvToJSON converts CSV data to a JSON array of objects. Fields are
+// separated by the given delimiter and may be enclosed in the given quote
+// character. If header is true, the first line is used for the object keys,
+// otherwise keys are the column numbers starting at 1.
+func csvToJSON(value, delimiter, quote string, header bool) (string, error) {
+	if len(delimiter) > 1 || len(quote) > 1 {
+		return "", errors.New("delimiter and quote must be a single character")
+	}
+
+	delim := byte(',')
+	if delimiter != "" {
+		delim = delimiter[0]
+	}
+
+	var q byte
+	if quote != "" {
+		q = quote[0]
+	}
+
+	// split the data into records of fields
+	records := make([][]string, 0)
+	record := make([]string, 0)
+	var field strings.Builder
+	quoted, inQuotes := false, false
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case inQuotes:
+			if c == q {
+				if i+1 < len(value) && value[i+1] == q {
+					field.WriteByte(q)
+					i++
+					continue
+				}
+				inQuotes = false
+				continue
+			}
+			field.WriteByte(c)
+
+		case q != 0 && c == q && field.Len() == 0 && !quoted:
+			inQuotes, quoted = true, true
+
+		case c == delim:
+			record = append(record, field.String())
+			field.Reset()
+			quoted = false
+
+		case c == '\r' && i+1 < len(value) && value[i+1] == '\n':
+			// handled with the line feed
+
+		case c == '\n':
+			record = append(record, field.String())
+			records = append(records, record)
+			record = make([]string, 0)
+			field.Reset()
+			quoted = false
+
+		default:
+			field.WriteByte(c)
+		}
+	}
+
+	if inQuotes {
+		return "", errors.New("unterminated quoted field in CSV data")
+	}
+
+	if field.Len() > 0 || quoted || len(record) > 0 {
+		records = append(records, append(record, field.String()))
+	}
+
+	var keys []string
+	if header {
+		if len(records) == 0 {
+			return "", errors.New("missing header in CSV data")
+		}
+		keys, records = records[0], records[1:]
+	}
+
+	out := make([]interface{}, 0, len(records))
+	for n, record := range records {
+		if header && len(record) != len(keys) {
+			return "", fmt.Errorf("line %d has %d fields, but the header has %d", n+2, len(record), len(keys))
+		}
+
+		obj := &jsonObject{values: make(map[string]interface{}, len(record))}
+		for i, v := range record {
+			key := strconv.Itoa(i + 1)
+			if header {
+				key = keys[i]
+			}
+
+			if _, ok := obj.values[key]; !ok {
+				obj.keys = append(obj.keys, key)
+			}
+			obj.values[key] = v
+		}
+		out = append(out, obj)
+	}
+
+	b, err := json.Marshal(out)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
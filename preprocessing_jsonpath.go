@@ -0,0 +1,871 @@
+package zabbix
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// errJSONPathNoData describes a JSONPath expression which matched nothing.
+var errJSONPathNoData = errors.New("no data matches the specified path")
+
+// jsonObject is a JSON object which preserves the order of its members, so
+// that JSONPath results are returned in document order.
+type jsonObject struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+// MarshalJSON encodes a jsonObject with its members in document order.
+func (c *jsonObject) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, key := range c.keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+
+		v, err := json.Marshal(c.values[key])
+		if err != nil {
+			return nil, err
+		}
+
+		b.Write(k)
+		b.WriteByte(':')
+		b.Write(v)
+	}
+	b.WriteByte('}')
+
+	return b.Bytes(), nil
+}
+
+// decodeJSONValue decodes the next JSON value from the given decoder, using
+// jsonObject for objects and json.Number for numbers.
+func decodeJSONValue(dec *json.Decoder) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			obj := &jsonObject{values: make(map[string]interface{})}
+			for dec.More() {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+
+				key := keyTok.(string)
+				value, err := decodeJSONValue(dec)
+				if err != nil {
+					return nil, err
+				}
+
+				if _, ok := obj.values[key]; !ok {
+					obj.keys = append(obj.keys, key)
+				}
+				obj.values[key] = value
+			}
+
+			_, err = dec.Token() // closing brace
+			return obj, err
+
+		case '[':
+			arr := make([]interface{}, 0)
+			for dec.More() {
+				value, err := decodeJSONValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				arr = append(arr, value)
+			}
+
+			_, err = dec.Token() // closing bracket
+			return arr, err
+		}
+	}
+
+	return tok, nil
+}
+
+// parseJSON parses JSON data for evaluation by a JSONPath expression.
+func parseJSON(data string) (interface{}, error) {
+	dec := json.NewDecoder(strings.NewReader(data))
+	dec.UseNumber()
+
+	value, err := decodeJSONValue(dec)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse as a valid JSON object: %v", err)
+	}
+
+	return value, nil
+}
+
+// jsonPathSegment is a single step of a compiled JSONPath expression.
+type jsonPathSegment struct {
+	deep     bool          // ".." descendant scan
+	wildcard bool          // "*"
+	names    []string      // member names
+	indexes  []int         // array indexes
+	slice    []*int        // start, end and step of an array slice
+	filter   *jsonPathExpr // filter expression
+}
+
+// jsonPath is a compiled JSONPath expression.
+type jsonPath struct {
+	segments []jsonPathSegment
+	function string
+	definite bool
+}
+
+// compileJSONPath compiles the given JSONPath expression.
+func compileJSONPath(path string) (*jsonPath, error) {
+	p := &jsonPathParser{s: strings.TrimSpace(path)}
+	if p.s == "" || (p.s[0] != '$' && p.s[0] != '@') {
+		return nil, fmt.Errorf("JSONPath %q must start with '$'", path)
+	}
+	p.pos = 1
+
+	out, err := p.parsePath(false)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSONPath %q: %v", path, err)
+	}
+
+	if p.pos < len(p.s) {
+		return nil, fmt.Errorf("invalid JSONPath %q: unexpected %q at position %d", path, p.s[p.pos], p.pos)
+	}
+
+	return out, nil
+}
+
+// jsonPathParser parses JSONPath expressions.
+type jsonPathParser struct {
+	s   string
+	pos int
+}
+
+func (p *jsonPathParser) peek() byte {
+	if p.pos < len(p.s) {
+		return p.s[p.pos]
+	}
+	return 0
+}
+
+func (p *jsonPathParser) skipSpace() {
+	for p.pos < len(p.s) && p.s[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+// parsePath parses the segments of a path following the root symbol. If
+// nested is true, parsing stops at the first character which can not continue
+// a path, as in a filter expression.
+func (p *jsonPathParser) parsePath(nested bool) (*jsonPath, error) {
+	out := &jsonPath{definite: true}
+	for p.pos < len(p.s) {
+		switch p.s[p.pos] {
+		case '.':
+			p.pos++
+			seg := jsonPathSegment{}
+			if p.peek() == '.' {
+				p.pos++
+				seg.deep = true
+				out.definite = false
+				if p.peek() == '[' {
+					if err := p.parseBracket(&seg, out); err != nil {
+						return nil, err
+					}
+					out.segments = append(out.segments, seg)
+					continue
+				}
+			}
+
+			if p.peek() == '*' {
+				p.pos++
+				seg.wildcard = true
+				out.definite = false
+				out.segments = append(out.segments, seg)
+				continue
+			}
+
+			start := p.pos
+			for p.pos < len(p.s) && !strings.ContainsRune(".[]() =!<>&|,", rune(p.s[p.pos])) {
+				p.pos++
+			}
+
+			name := p.s[start:p.pos]
+			if name == "" {
+				return nil, fmt.Errorf("expected member name at position %d", start)
+			}
+
+			// trailing function call
+			if strings.HasPrefix(p.s[p.pos:], "()") {
+				p.pos += 2
+				switch name {
+				case "length", "first", "sum", "avg", "min", "max":
+					out.function = name
+				default:
+					return nil, fmt.Errorf("unsupported function %q", name)
+				}
+
+				if p.pos < len(p.s) && !nested {
+					return nil, fmt.Errorf("function %q must be the last segment", name)
+				}
+				return out, nil
+			}
+
+			seg.names = []string{name}
+			out.segments = append(out.segments, seg)
+
+		case '[':
+			seg := jsonPathSegment{}
+			if err := p.parseBracket(&seg, out); err != nil {
+				return nil, err
+			}
+			out.segments = append(out.segments, seg)
+
+		default:
+			if nested {
+				return out, nil
+			}
+			return nil, fmt.Errorf("unexpected %q at position %d", p.s[p.pos], p.pos)
+		}
+	}
+
+	return out, nil
+}
+
+// parseBracket parses a bracket segment into seg.
+func (p *jsonPathParser) parseBracket(seg *jsonPathSegment, out *jsonPath) error {
+	p.pos++ // '['
+	p.skipSpace()
+
+	switch p.peek() {
+	case '*':
+		p.pos++
+		seg.wildcard = true
+		out.definite = false
+
+	case '?':
+		p.pos++
+		p.skipSpace()
+		if p.peek() != '(' {
+			return fmt.Errorf("expected '(' at position %d", p.pos)
+		}
+		p.pos++
+
+		expr, err := p.parseOr()
+		if err != nil {
+			return err
+		}
+
+		p.skipSpace()
+		if p.peek() != ')' {
+			return fmt.Errorf("expected ')' at position %d", p.pos)
+		}
+		p.pos++
+		seg.filter = expr
+		out.definite = false
+
+	case '\'', '"':
+		for {
+			name, err := p.parseString()
+			if err != nil {
+				return err
+			}
+			seg.names = append(seg.names, name)
+
+			p.skipSpace()
+			if p.peek() != ',' {
+				break
+			}
+			p.pos++
+			p.skipSpace()
+		}
+
+		if len(seg.names) > 1 {
+			out.definite = false
+		}
+
+	default:
+		start := p.pos
+		for p.pos < len(p.s) && p.s[p.pos] != ']' {
+			p.pos++
+		}
+
+		body := strings.TrimSpace(p.s[start:p.pos])
+		if strings.Contains(body, ":") {
+			parts := strings.Split(body, ":")
+			if len(parts) > 3 {
+				return fmt.Errorf("invalid slice %q", body)
+			}
+
+			seg.slice = make([]*int, 3)
+			for i, part := range parts {
+				part = strings.TrimSpace(part)
+				if part == "" {
+					continue
+				}
+
+				n, err := strconv.Atoi(part)
+				if err != nil {
+					return fmt.Errorf("invalid slice %q", body)
+				}
+				seg.slice[i] = &n
+			}
+			out.definite = false
+			break
+		}
+
+		for _, part := range strings.Split(body, ",") {
+			n, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil {
+				return fmt.Errorf("invalid array index %q", part)
+			}
+			seg.indexes = append(seg.indexes, n)
+		}
+
+		if len(seg.indexes) > 1 {
+			out.definite = false
+		}
+	}
+
+	p.skipSpace()
+	if p.peek() != ']' {
+		return fmt.Errorf("expected ']' at position %d", p.pos)
+	}
+	p.pos++
+
+	return nil
+}
+
+// parseString parses a quoted string literal.
+func (p *jsonPathParser) parseString() (string, error) {
+	quote := p.s[p.pos]
+	p.pos++
+
+	var b strings.Builder
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		p.pos++
+		if c == '\\' && p.pos < len(p.s) {
+			b.WriteByte(p.s[p.pos])
+			p.pos++
+			continue
+		}
+
+		if c == quote {
+			return b.String(), nil
+		}
+		b.WriteByte(c)
+	}
+
+	return "", errors.New("unterminated string")
+}
+
+// jsonPathExpr is a node of a JSONPath filter expression.
+type jsonPathExpr struct {
+	op          string // "||", "&&", comparison operator or "" for an operand
+	left, right *jsonPathExpr
+
+	path    *jsonPath   // operand relative to the current node
+	literal interface{} // operand literal value
+	re      *regexp.Regexp
+}
+
+func (p *jsonPathParser) parseOr() (*jsonPathExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		p.skipSpace()
+		if !strings.HasPrefix(p.s[p.pos:], "||") {
+			return left, nil
+		}
+		p.pos += 2
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &jsonPathExpr{op: "||", left: left, right: right}
+	}
+}
+
+func (p *jsonPathParser) parseAnd() (*jsonPathExpr, error) {
+	left, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		p.skipSpace()
+		if !strings.HasPrefix(p.s[p.pos:], "&&") {
+			return left, nil
+		}
+		p.pos += 2
+
+		right, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		left = &jsonPathExpr{op: "&&", left: left, right: right}
+	}
+}
+
+func (p *jsonPathParser) parseComparison() (*jsonPathExpr, error) {
+	p.skipSpace()
+	if p.peek() == '(' {
+		p.pos++
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		p.skipSpace()
+		if p.peek() != ')' {
+			return nil, fmt.Errorf("expected ')' at position %d", p.pos)
+		}
+		p.pos++
+		return expr, nil
+	}
+
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpace()
+	for _, op := range []string{"==", "!=", "<=", ">=", "=~", "<", ">"} {
+		if !strings.HasPrefix(p.s[p.pos:], op) {
+			continue
+		}
+		p.pos += len(op)
+
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+
+		expr := &jsonPathExpr{op: op, left: left, right: right}
+		if op == "=~" {
+			pattern, ok := right.literal.(string)
+			if !ok {
+				return nil, errors.New("regular expression must be a string")
+			}
+
+			expr.re, err = regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid regular expression: %v", err)
+			}
+		}
+
+		return expr, nil
+	}
+
+	// a path without comparison tests for existence
+	return left, nil
+}
+
+func (p *jsonPathParser) parseOperand() (*jsonPathExpr, error) {
+	p.skipSpace()
+	switch c := p.peek(); {
+	case c == '@' || c == '$':
+		p.pos++
+		path, err := p.parsePath(true)
+		if err != nil {
+			return nil, err
+		}
+		return &jsonPathExpr{path: path}, nil
+
+	case c == '\'' || c == '"':
+		s, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return &jsonPathExpr{literal: s}, nil
+	}
+
+	start := p.pos
+	for p.pos < len(p.s) && !strings.ContainsRune(" )=!<>&|", rune(p.s[p.pos])) {
+		p.pos++
+	}
+
+	token := p.s[start:p.pos]
+	switch token {
+	case "true":
+		return &jsonPathExpr{literal: true}, nil
+	case "false":
+		return &jsonPathExpr{literal: false}, nil
+	case "null":
+		return &jsonPathExpr{literal: nil}, nil
+	}
+
+	n, err := strconv.ParseFloat(token, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid operand %q at position %d", token, start)
+	}
+
+	return &jsonPathExpr{literal: n}, nil
+}
+
+// eval evaluates a filter expression against the given node.
+func (e *jsonPathExpr) eval(node interface{}) bool {
+	switch e.op {
+	case "||":
+		return e.left.eval(node) || e.right.eval(node)
+	case "&&":
+		return e.left.eval(node) && e.right.eval(node)
+	case "":
+		if e.path == nil {
+			return e.literal != nil && e.literal != false
+		}
+		_, ok := e.value(node)
+		return ok
+	}
+
+	left, ok := e.left.value(node)
+	if !ok {
+		return false
+	}
+
+	right, ok := e.right.value(node)
+	if !ok {
+		return false
+	}
+
+	if e.op == "=~" {
+		s, ok := left.(string)
+		return ok && e.re.MatchString(s)
+	}
+
+	// compare numerically if both operands are numeric
+	if lf, ok := jsonNumber(left); ok {
+		if rf, ok := jsonNumber(right); ok {
+			switch e.op {
+			case "==":
+				return lf == rf
+			case "!=":
+				return lf != rf
+			case "<":
+				return lf < rf
+			case "<=":
+				return lf <= rf
+			case ">":
+				return lf > rf
+			case ">=":
+				return lf >= rf
+			}
+		}
+	}
+
+	ls, rs := fmt.Sprint(left), fmt.Sprint(right)
+	switch e.op {
+	case "==":
+		return ls == rs
+	case "!=":
+		return ls != rs
+	case "<":
+		return ls < rs
+	case "<=":
+		return ls <= rs
+	case ">":
+		return ls > rs
+	case ">=":
+		return ls >= rs
+	}
+
+	return false
+}
+
+// value returns the value of an operand for the given node.
+func (e *jsonPathExpr) value(node interface{}) (interface{}, bool) {
+	if e.path == nil {
+		return e.literal, true
+	}
+
+	results := e.path.eval(node)
+	if len(results) == 0 {
+		return nil, false
+	}
+
+	if e.path.function != "" {
+		v, err := e.path.apply(results)
+		if err != nil {
+			return nil, false
+		}
+		return v, true
+	}
+
+	return results[0], true
+}
+
+// jsonNumber returns the given JSON value as a float.
+func jsonNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case float64:
+		return n, true
+	}
+
+	return 0, false
+}
+
+// eval returns all nodes matched by the path from the given root.
+func (c *jsonPath) eval(root interface{}) []interface{} {
+	nodes := []interface{}{root}
+	for i := range c.segments {
+		seg := &c.segments[i]
+		next := make([]interface{}, 0)
+		for _, node := range nodes {
+			if seg.deep {
+				jsonDescendants(node, func(n interface{}) {
+					next = append(next, seg.match(n)...)
+				})
+				continue
+			}
+			next = append(next, seg.match(node)...)
+		}
+		nodes = next
+	}
+
+	return nodes
+}
+
+// jsonDescendants calls fn for the given node and all of its descendants.
+func jsonDescendants(node interface{}, fn func(interface{})) {
+	fn(node)
+	switch n := node.(type) {
+	case *jsonObject:
+		for _, key := range n.keys {
+			jsonDescendants(n.values[key], fn)
+		}
+	case []interface{}:
+		for _, v := range n {
+			jsonDescendants(v, fn)
+		}
+	}
+}
+
+// match returns the children of the given node which match the segment.
+func (c *jsonPathSegment) match(node interface{}) []interface{} {
+	out := make([]interface{}, 0)
+	switch n := node.(type) {
+	case *jsonObject:
+		switch {
+		case c.wildcard:
+			for _, key := range n.keys {
+				out = append(out, n.values[key])
+			}
+
+		case c.filter != nil:
+			for _, key := range n.keys {
+				if c.filter.eval(n.values[key]) {
+					out = append(out, n.values[key])
+				}
+			}
+
+		default:
+			for _, name := range c.names {
+				if v, ok := n.values[name]; ok {
+					out = append(out, v)
+				}
+			}
+		}
+
+	case []interface{}:
+		switch {
+		case c.wildcard:
+			out = append(out, n...)
+
+		case c.filter != nil:
+			for _, v := range n {
+				if c.filter.eval(v) {
+					out = append(out, v)
+				}
+			}
+
+		case c.slice != nil:
+			start, end, step := 0, len(n), 1
+			if c.slice[2] != nil && *c.slice[2] != 0 {
+				step = *c.slice[2]
+			}
+			if step < 0 {
+				start, end = len(n)-1, -1
+			}
+			if c.slice[0] != nil {
+				start = normalizeIndex(*c.slice[0], len(n))
+			}
+			if c.slice[1] != nil {
+				end = normalizeIndex(*c.slice[1], len(n))
+			}
+
+			for i := start; (step > 0 && i < end) || (step < 0 && i > end); i += step {
+				if i >= 0 && i < len(n) {
+					out = append(out, n[i])
+				}
+			}
+
+		default:
+			for _, i := range c.indexes {
+				i = normalizeIndex(i, len(n))
+				if i >= 0 && i < len(n) {
+					out = append(out, n[i])
+				}
+			}
+		}
+	}
+
+	return out
+}
+
+// normalizeIndex converts negative array indexes to an offset from the end of
+// an array of the given length.
+func normalizeIndex(i, length int) int {
+	if i < 0 {
+		return length + i
+	}
+	return i
+}
+
+// apply applies the path function to the given results.
+func (c *jsonPath) apply(results []interface{}) (interface{}, error) {
+	// functions of a definite path apply to the elements of a matched array
+	values := results
+	if c.definite && len(results) == 1 {
+		if arr, ok := results[0].([]interface{}); ok {
+			values = arr
+		}
+	}
+
+	switch c.function {
+	case "length":
+		return float64(len(values)), nil
+
+	case "first":
+		if len(values) == 0 {
+			return nil, errJSONPathNoData
+		}
+		return values[0], nil
+	}
+
+	if len(values) == 0 {
+		return nil, errJSONPathNoData
+	}
+
+	sum, min, max := 0.0, math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		f, ok := jsonNumber(v)
+		if !ok {
+			s, isString := v.(string)
+			if !isString {
+				return nil, fmt.Errorf("cannot apply function %q to non-numeric value", c.function)
+			}
+
+			var err error
+			if f, err = strconv.ParseFloat(s, 64); err != nil {
+				return nil, fmt.Errorf("cannot apply function %q to non-numeric value", c.function)
+			}
+		}
+
+		sum += f
+		min = math.Min(min, f)
+		max = math.Max(max, f)
+	}
+
+	switch c.function {
+	case "sum":
+		return sum, nil
+	case "avg":
+		return sum / float64(len(values)), nil
+	case "min":
+		return min, nil
+	}
+
+	return max, nil
+}
+
+// jsonValueString formats a JSON value as an Item value. Strings are returned
+// without quotes and all other values are returned as JSON.
+func jsonValueString(v interface{}) (string, error) {
+	switch t := v.(type) {
+	case string:
+		return t, nil
+	case json.Number:
+		return t.String(), nil
+	case float64:
+		return formatNumeric(t), nil
+	case nil:
+		return "null", nil
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// jsonPathValue evaluates the given JSONPath expression against the given JSON
+// data and returns the matched value.
+//
+// Definite paths return the matched value. Indefinite paths, such as those
+// with wildcards or filters, return a slice of all matched values.
+func jsonPathValue(path, data string) (interface{}, error) {
+	p, err := compileJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := parseJSON(data)
+	if err != nil {
+		return nil, err
+	}
+
+	results := p.eval(root)
+	if p.function != "" {
+		return p.apply(results)
+	}
+
+	if len(results) == 0 {
+		return nil, errJSONPathNoData
+	}
+
+	if p.definite {
+		return results[0], nil
+	}
+
+	return results, nil
+}
+
+// jsonPathString evaluates the given JSONPath expression against the given
+// JSON data and returns the result as an Item value.
+//
+// Definite paths return the matched value. Indefinite paths, such as those
+// with wildcards or filters, return a JSON array of all matched values.
+func jsonPathString(path, data string) (string, error) {
+	v, err := jsonPathValue(path, data)
+	if err != nil {
+		return "", err
+	}
+
+	return jsonValueString(v)
+}
@@ -0,0 +1,309 @@
+package zabbix
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// prometheusMetric is a single sample parsed from Prometheus text format.
+type prometheusMetric struct {
+	name   string
+	labels map[string]string
+	value  string
+}
+
+// prometheusMatcher is a label matcher of a Prometheus pattern.
+type prometheusMatcher struct {
+	label string
+	op    string
+	value string
+	re    *regexp.Regexp
+}
+
+// matches returns true if the given label value satisfies the matcher.
+func (c *prometheusMatcher) matches(value string) bool {
+	switch c.op {
+	case "=":
+		return value == c.value
+	case "!=":
+		return value != c.value
+	case "=~":
+		return c.re.MatchString(value)
+	}
+
+	return !c.re.MatchString(value)
+}
+
+// prometheusPatternSpec is a compiled Prometheus pattern.
+type prometheusPatternSpec struct {
+	matchers []prometheusMatcher
+	value    string // optional "== value" comparison
+}
+
+// parsePrometheusLabels parses a comma separated list of label pairs or
+// matchers between braces, starting at s[0] == '{'. It returns the parsed
+// pairs and the remainder of s.
+func parsePrometheusLabels(s string) ([]prometheusMatcher, string, error) {
+	out := make([]prometheusMatcher, 0)
+	s = strings.TrimSpace(s[1:])
+	for {
+		if strings.HasPrefix(s, "}") {
+			return out, s[1:], nil
+		}
+
+		i := strings.IndexAny(s, "=!")
+		if i <= 0 {
+			return nil, "", fmt.Errorf("invalid label matcher %q", s)
+		}
+
+		m := prometheusMatcher{label: strings.TrimSpace(s[:i])}
+		s = s[i:]
+		for _, op := range []string{"=~", "!~", "!=", "="} {
+			if strings.HasPrefix(s, op) {
+				m.op = op
+				s = strings.TrimSpace(s[len(op):])
+				break
+			}
+		}
+		if m.op == "" || !strings.HasPrefix(s, `"`) {
+			return nil, "", fmt.Errorf("invalid label matcher for %q", m.label)
+		}
+
+		// quoted label value with escapes
+		var b strings.Builder
+		j := 1
+		for ; j < len(s) && s[j] != '"'; j++ {
+			if s[j] == '\\' && j+1 < len(s) {
+				j++
+				switch s[j] {
+				case 'n':
+					b.WriteByte('\n')
+				default:
+					b.WriteByte(s[j])
+				}
+				continue
+			}
+			b.WriteByte(s[j])
+		}
+		if j >= len(s) {
+			return nil, "", fmt.Errorf("unterminated value for label %q", m.label)
+		}
+		m.value = b.String()
+		s = strings.TrimSpace(s[j+1:])
+
+		if m.op == "=~" || m.op == "!~" {
+			re, err := regexp.Compile("^(?:" + m.value + ")$")
+			if err != nil {
+				return nil, "", fmt.Errorf("invalid regular expression for label %q: %v", m.label, err)
+			}
+			m.re = re
+		}
+		out = append(out, m)
+
+		if strings.HasPrefix(s, ",") {
+			s = strings.TrimSpace(s[1:])
+		}
+	}
+}
+
+// parsePrometheusLine parses a single sample line in Prometheus text format.
+func parsePrometheusLine(line string) (*prometheusMetric, error) {
+	m := &prometheusMetric{labels: make(map[string]string)}
+	i := strings.IndexAny(line, "{ \t")
+	if i < 0 {
+		return nil, fmt.Errorf("invalid metric line %q", line)
+	}
+
+	m.name = line[:i]
+	rest := line[i:]
+	if strings.HasPrefix(rest, "{") {
+		labels, r, err := parsePrometheusLabels(rest)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, label := range labels {
+			if label.op != "=" {
+				return nil, fmt.Errorf("invalid label %q in metric line", label.label)
+			}
+			m.labels[label.label] = label.value
+		}
+		rest = r
+	}
+
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("missing value in metric line %q", line)
+	}
+	m.value = fields[0]
+
+	return m, nil
+}
+
+// compilePrometheusPattern compiles a Prometheus pattern of the form
+// name{label="value",...} == value, where each part is optional.
+func compilePrometheusPattern(pattern string) (*prometheusPatternSpec, error) {
+	spec := &prometheusPatternSpec{}
+	s := strings.TrimSpace(pattern)
+	if s == "" {
+		return nil, errors.New("empty Prometheus pattern")
+	}
+
+	i := strings.IndexAny(s, "{ =")
+	if i < 0 {
+		i = len(s)
+	}
+	if i > 0 {
+		spec.matchers = append(spec.matchers, prometheusMatcher{
+			label: "__name__",
+			op:    "=",
+			value: s[:i],
+		})
+	}
+
+	s = strings.TrimSpace(s[i:])
+	if strings.HasPrefix(s, "{") {
+		matchers, r, err := parsePrometheusLabels(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Prometheus pattern: %v", err)
+		}
+		spec.matchers = append(spec.matchers, matchers...)
+		s = strings.TrimSpace(r)
+	}
+
+	if strings.HasPrefix(s, "==") {
+		spec.value = strings.TrimSpace(s[2:])
+		s = ""
+	}
+
+	if s != "" {
+		return nil, fmt.Errorf("invalid Prometheus pattern %q", pattern)
+	}
+
+	return spec, nil
+}
+
+// matches returns true if the given metric satisfies the pattern.
+func (c *prometheusPatternSpec) matches(m *prometheusMetric) bool {
+	for i := range c.matchers {
+		value := m.labels[c.matchers[i].label]
+		if c.matchers[i].label == "__name__" {
+			value = m.name
+		}
+
+		if !c.matchers[i].matches(value) {
+			return false
+		}
+	}
+
+	if c.value != "" {
+		want, err1 := strconv.ParseFloat(c.value, 64)
+		got, err2 := strconv.ParseFloat(m.value, 64)
+		if err1 != nil || err2 != nil {
+			return c.value == m.value
+		}
+		return want == got
+	}
+
+	return true
+}
+
+// prometheusPattern extracts a value from metrics in Prometheus text format
+// which match the given pattern.
+//
+// Result must be one of "value", "label" or "function". The older two
+// parameter form, where result is empty for the value or a label name, is
+// also accepted.
+func prometheusPattern(data, pattern, result, param string) (string, error) {
+	switch result {
+	case "value", "label", "function":
+	case "":
+		result = "value"
+	default:
+		result, param = "label", result
+	}
+
+	spec, err := compilePrometheusPattern(pattern)
+	if err != nil {
+		return "", err
+	}
+
+	matched := make([]*prometheusMetric, 0)
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		m, err := parsePrometheusLine(line)
+		if err != nil {
+			return "", fmt.Errorf("cannot parse Prometheus data: %v", err)
+		}
+
+		if spec.matches(m) {
+			matched = append(matched, m)
+		}
+	}
+
+	if result == "function" {
+		return prometheusFunction(matched, param)
+	}
+
+	if len(matched) == 0 {
+		return "", errors.New("no data matches the specified Prometheus pattern")
+	}
+
+	if len(matched) > 1 {
+		return "", errors.New("multiple metrics match the specified Prometheus pattern")
+	}
+
+	if result == "label" {
+		value, ok := matched[0].labels[param]
+		if !ok {
+			return "", fmt.Errorf("label %q not found in matching metric", param)
+		}
+		return value, nil
+	}
+
+	return matched[0].value, nil
+}
+
+// prometheusFunction aggregates the values of the given metrics.
+func prometheusFunction(metrics []*prometheusMetric, function string) (string, error) {
+	if function == "count" {
+		return strconv.Itoa(len(metrics)), nil
+	}
+
+	if len(metrics) == 0 {
+		return "", errors.New("no data matches the specified Prometheus pattern")
+	}
+
+	sum, min, max := 0.0, math.Inf(1), math.Inf(-1)
+	for _, m := range metrics {
+		v, err := strconv.ParseFloat(m.value, 64)
+		if err != nil {
+			return "", fmt.Errorf("cannot convert metric value %q to numeric", m.value)
+		}
+
+		sum += v
+		min = math.Min(min, v)
+		max = math.Max(max, v)
+	}
+
+	switch function {
+	case "sum":
+		return formatNumeric(sum), nil
+	case "min":
+		return formatNumeric(min), nil
+	case "max":
+		return formatNumeric(max), nil
+	case "avg":
+		return formatNumeric(sum / float64(len(metrics))), nil
+	}
+
+	return "", fmt.Errorf("unsupported Prometheus function %q", function)
+}
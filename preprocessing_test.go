@@ -0,0 +1,159 @@
+package zabbix
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPreprocess(t *testing.T) {
+	tests := []struct {
+		Value  string
+		Step   ItemPreprocessing
+		Expect string
+	}{
+		{"10", PreprocessingMultiplier("1.5"), "15"},
+		{"  abc  ", PreprocessingTrim(" "), "abc"},
+		{"xxabc", PreprocessingLeftTrim("x"), "abc"},
+		{"temp=21.5C", PreprocessingRegex(`temp=([0-9.]+)`, `\1`), "21.5"},
+		{"a-b-c", PreprocessingReplace("-", "+"), "a+b+c"},
+		{"5", PreprocessingInRange("1", "10"), "5"},
+		{`{"a":{"b":"c"}}`, PreprocessingJSONPath("$.a.b"), "c"},
+		{`{"a":[1,2,3]}`, PreprocessingJSONPath("$.a[-1]"), "3"},
+		{`{"a":[1,2,3]}`, PreprocessingJSONPath("$.a.length()"), "3"},
+		{`{"a":[1,2,3]}`, PreprocessingJSONPath("$.a.sum()"), "6"},
+		{`{"a":{"y":1,"x":2}}`, PreprocessingJSONPath("$.a"), `{"y":1,"x":2}`},
+		{`[{"n":"a","v":1},{"n":"b","v":2}]`, PreprocessingJSONPath("$[?(@.v > 1)].n"), `["b"]`},
+		{`[{"n":"a","v":1},{"n":"b","v":2}]`, PreprocessingJSONPath("$..n"), `["a","b"]`},
+		{`<a><b id="1">x</b><b id="2">y</b></a>`, PreprocessingXPath("/a/b[@id='2']/text()"), "y"},
+		{`<a><b id="1">x</b><b id="2">y</b></a>`, PreprocessingXPath("count(//b)"), "2"},
+		{`<a><b id="1">x</b></a>`, PreprocessingXPath("/a/b"), `<b id="1">x</b>`},
+		{"# HELP up\nup{job=\"a\"} 1\nup{job=\"b\"} 0\n", PreprocessingPrometheusPattern(`up{job="b"}`, "value", ""), "0"},
+		{"up{job=\"a\"} 1\nup{job=\"b\"} 0\n", PreprocessingPrometheusPattern(`up`, "function", "sum"), "1"},
+		{"up{job=\"a\"} 1\n", PreprocessingPrometheusPattern(`up == 1`, "label", "job"), "a"},
+		{"a;b\n1;'x;y'\n", PreprocessingCSVToJSON(";", "'", true), `[{"a":"1","b":"x;y"}]`},
+		{"1,2\n", PreprocessingCSVToJSON(",", `"`, false), `[{"1":"1","2":"2"}]`},
+		{`{"error":""}`, ItemPreprocessing{Type: PreprocessingTypeCheckJSONError, Params: "$.error"}, `{"error":""}`},
+		{`{"error":null}`, ItemPreprocessing{Type: PreprocessingTypeCheckJSONError, Params: "$.error"}, `{"error":null}`},
+		{`{"ok":true}`, ItemPreprocessing{Type: PreprocessingTypeCheckJSONError, Params: "$.error"}, `{"ok":true}`},
+	}
+
+	for _, test := range tests {
+		result, ok, err := Preprocess(test.Value, test.Step)
+		if err != nil {
+			t.Errorf("Error applying step %d to %q: %v", test.Step.Type, test.Value, err)
+			continue
+		}
+
+		if !ok || result != test.Expect {
+			t.Errorf("Expected step %d to return %q for %q, got %q", test.Step.Type, test.Expect, test.Value, result)
+		}
+	}
+}
+
+func TestPreprocessFailures(t *testing.T) {
+	tests := []struct {
+		Value       string
+		Step        ItemPreprocessing
+		Unsupported bool
+	}{
+		{"x", PreprocessingMultiplier("2"), false},
+		{"11", PreprocessingInRange("1", "10"), false},
+		{"abc", PreprocessingRegex(`[0-9]+`, `\0`), false},
+		{`{"a":1}`, PreprocessingJSONPath("$.b"), false},
+		{`{"a":1`, PreprocessingJSONPath("$.a"), false},
+		{`{"a":[1]}`, PreprocessingJSONPath("$.a.avg(1"), false},
+		{`{"error":"failed"}`, ItemPreprocessing{Type: PreprocessingTypeCheckJSONError, Params: "$.error"}, false},
+		{`<a><b>x</b></a>`, PreprocessingXPath("/a/c"), false},
+		{`<a><b>x</b>`, PreprocessingXPath("/a/b"), false},
+		{`<a><b>x</b></a>`, PreprocessingXPath("/a/b[x"), false},
+		{`<a><error>failed</error></a>`, ItemPreprocessing{Type: PreprocessingTypeCheckXMLError, Params: "/a/error/text()"}, false},
+		{"up 1\n", PreprocessingPrometheusPattern(`down`, "value", ""), false},
+		{"up 1\n", PreprocessingPrometheusPattern(`up`, "function", "median"), false},
+		{`<a><b>x</b><b>y</b></a>`, PreprocessingXPath("/a/b[position()>1]/text()"), true},
+		{`<a><b id="1">x</b></a>`, PreprocessingXPath("/a/b[@id!='1']"), true},
+		{`<a><b id="1">x</b></a>`, PreprocessingXPath("/a/b[@id='1' or @id='2']"), true},
+		{`<a><b>x</b></a>`, PreprocessingXPath("/a/b[text()>'1']"), true},
+		{`<a><b>x</b></a>`, PreprocessingXPath("name(/a/*)"), true},
+		{`<a><b>x</b></a>`, PreprocessingXPath("count("), true},
+		{`<a><b>x</b></a>`, PreprocessingXPath("("), true},
+		{`<a><b>x</b></a>`, PreprocessingXPath("/a/b | /a/c"), true},
+		{`<a><b>x</b></a>`, PreprocessingXPath("/a/ns:b"), true},
+		{`<a><error>failed</error></a>`, ItemPreprocessing{Type: PreprocessingTypeCheckXMLError, Params: "concat(/a/error, '!')"}, true},
+		{"x", PreprocessingJavaScript("return value"), true},
+	}
+
+	for _, test := range tests {
+		_, _, err := Preprocess(test.Value, test.Step)
+		if test.Unsupported {
+			if err != ErrPreprocessingUnsupported {
+				t.Errorf("Expected step %d %q to be unsupported, got %v", test.Step.Type, test.Step.Params, err)
+			}
+			continue
+		}
+
+		if _, ok := err.(*PreprocessingError); !ok {
+			t.Errorf("Expected step %d %q to fail for %q, got %v", test.Step.Type, test.Step.Params, test.Value, err)
+		}
+	}
+}
+
+func TestPreprocessErrorHandler(t *testing.T) {
+	_, _, err := Preprocess("x", PreprocessingMultiplier("2"))
+	if _, ok := err.(*PreprocessingError); !ok {
+		t.Errorf("Expected PreprocessingError, got %v", err)
+	}
+
+	result, ok, err := Preprocess("x", PreprocessingMultiplier("2").WithErrorHandler(PreprocessingErrorHandlerSetValue, "0"))
+	if err != nil || !ok || result != "0" {
+		t.Errorf("Expected custom value \"0\", got %q, %v, %v", result, ok, err)
+	}
+
+	_, ok, err = Preprocess("x", PreprocessingMultiplier("2").WithErrorHandler(PreprocessingErrorHandlerDiscard, ""))
+	if err != nil || ok {
+		t.Errorf("Expected value to be discarded, got %v, %v", ok, err)
+	}
+
+	_, _, err = Preprocess("x", PreprocessingJavaScript("return value"))
+	if err != ErrPreprocessingUnsupported {
+		t.Errorf("Expected ErrPreprocessingUnsupported, got %v", err)
+	}
+}
+
+func TestPreprocessorChangePerSecond(t *testing.T) {
+	p := NewPreprocessor(PreprocessingChangePerSecond())
+	start := time.Unix(1000, 0)
+
+	if _, ok, err := p.Process("100", start); err != nil || ok {
+		t.Fatalf("Expected first value to be discarded, got %v, %v", ok, err)
+	}
+
+	result, ok, err := p.Process("160", start.Add(30*time.Second))
+	if err != nil || !ok || result != "2" {
+		t.Errorf("Expected \"2\", got %q, %v, %v", result, ok, err)
+	}
+}
+
+func TestPreprocessorDiscardUnchangedHeartbeat(t *testing.T) {
+	p := NewPreprocessor(PreprocessingDiscardUnchangedHeartbeat("1m"))
+	start := time.Unix(1000, 0)
+
+	for i, test := range []struct {
+		Offset time.Duration
+		Value  string
+		Expect bool
+	}{
+		{0, "a", true},
+		{30 * time.Second, "a", false},
+		{90 * time.Second, "a", true},
+		{100 * time.Second, "b", true},
+	} {
+		_, ok, err := p.Process(test.Value, start.Add(test.Offset))
+		if err != nil {
+			t.Fatalf("Error processing value %d: %v", i, err)
+		}
+
+		if ok != test.Expect {
+			t.Errorf("Expected value %d to return ok=%v", i, test.Expect)
+		}
+	}
+}
@@ -0,0 +1,476 @@
+package zabbix
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// errXPathNoData describes an XPath expression which matched nothing.
+var errXPathNoData = errors.New("no data matches the specified XPath")
+
+const (
+	xmlNodeElement = iota
+	xmlNodeText
+	xmlNodeAttr
+)
+
+// xmlNode is a node of a parsed XML document.
+type xmlNode struct {
+	kind     int
+	name     string
+	value    string // text and attribute nodes
+	attrs    []*xmlNode
+	children []*xmlNode
+	parent   *xmlNode
+}
+
+// text returns the string value of the node as defined by XPath.
+func (c *xmlNode) text() string {
+	if c.kind != xmlNodeElement {
+		return c.value
+	}
+
+	var b strings.Builder
+	for _, child := range c.children {
+		b.WriteString(child.text())
+	}
+
+	return b.String()
+}
+
+// write serializes the node as XML.
+func (c *xmlNode) write(b *bytes.Buffer) {
+	switch c.kind {
+	case xmlNodeText:
+		xml.EscapeText(b, []byte(c.value))
+		return
+	case xmlNodeAttr:
+		b.WriteString(c.value)
+		return
+	}
+
+	b.WriteString("<" + c.name)
+	for _, attr := range c.attrs {
+		b.WriteString(" " + attr.name + `="`)
+		xml.EscapeText(b, []byte(attr.value))
+		b.WriteString(`"`)
+	}
+
+	if len(c.children) == 0 {
+		b.WriteString("/>")
+		return
+	}
+
+	b.WriteString(">")
+	for _, child := range c.children {
+		child.write(b)
+	}
+	b.WriteString("</" + c.name + ">")
+}
+
+// parseXML parses an XML document and returns its root node. The root node
+// is a document node whose only element child is the document element.
+func parseXML(data string) (*xmlNode, error) {
+	doc := &xmlNode{kind: xmlNodeElement}
+	cur := doc
+
+	dec := xml.NewDecoder(strings.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse XML value: %v", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			node := &xmlNode{kind: xmlNodeElement, name: xmlName(t.Name), parent: cur}
+			for _, attr := range t.Attr {
+				node.attrs = append(node.attrs, &xmlNode{
+					kind:   xmlNodeAttr,
+					name:   xmlName(attr.Name),
+					value:  attr.Value,
+					parent: node,
+				})
+			}
+			cur.children = append(cur.children, node)
+			cur = node
+
+		case xml.EndElement:
+			cur = cur.parent
+
+		case xml.CharData:
+			if cur == doc {
+				continue // whitespace outside of the document element
+			}
+			cur.children = append(cur.children, &xmlNode{
+				kind:   xmlNodeText,
+				value:  string(t),
+				parent: cur,
+			})
+		}
+	}
+
+	if len(doc.children) == 0 {
+		return nil, errors.New("cannot parse XML value: no document element")
+	}
+
+	return doc, nil
+}
+
+// xmlName returns the qualified name of an XML element or attribute. The
+// decoder resolves namespace prefixes to URIs, which are ignored.
+func xmlName(name xml.Name) string {
+	return name.Local
+}
+
+// xpathPredicate is a predicate of an XPath location step.
+type xpathPredicate struct {
+	position int    // [n], or -1 for [last()]
+	attr     string // [@attr] or [@attr='value']
+	child    string // [child='value']
+	value    *string
+}
+
+// xpathStep is a single location step of an XPath expression.
+type xpathStep struct {
+	deep       bool // preceded by "//"
+	name       string
+	predicates []xpathPredicate
+}
+
+// compileXPath compiles an XPath location path.
+func compileXPath(expr string) ([]xpathStep, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, errors.New("empty XPath expression")
+	}
+
+	steps := make([]xpathStep, 0)
+	relative := !strings.HasPrefix(expr, "/")
+	for len(expr) > 0 {
+		step := xpathStep{}
+		switch {
+		case strings.HasPrefix(expr, "//"):
+			step.deep = true
+			expr = expr[2:]
+		case strings.HasPrefix(expr, "/"):
+			expr = expr[1:]
+		case relative:
+			relative = false
+		default:
+			return nil, fmt.Errorf("unexpected %q in XPath expression", expr)
+		}
+
+		// step name ends at the next separator or predicate
+		end := strings.IndexAny(expr, "/[")
+		if end < 0 {
+			end = len(expr)
+		}
+		step.name = strings.TrimSpace(expr[:end])
+		if step.name == "" {
+			return nil, errors.New("missing XPath location step")
+		}
+		if !isXPathNodeTest(step.name) {
+			return nil, ErrPreprocessingUnsupported
+		}
+		expr = expr[end:]
+
+		for strings.HasPrefix(expr, "[") {
+			end := strings.Index(expr, "]")
+			if end < 0 {
+				return nil, errors.New("unterminated XPath predicate")
+			}
+
+			pred, err := parseXPathPredicate(strings.TrimSpace(expr[1:end]))
+			if err != nil {
+				return nil, err
+			}
+			step.predicates = append(step.predicates, pred)
+			expr = expr[end+1:]
+		}
+
+		steps = append(steps, step)
+	}
+
+	return steps, nil
+}
+
+// parseXPathPredicate parses the content of an XPath predicate.
+func parseXPathPredicate(s string) (xpathPredicate, error) {
+	if s == "last()" {
+		return xpathPredicate{position: -1}, nil
+	}
+
+	if n, err := strconv.Atoi(s); err == nil {
+		if n < 1 {
+			return xpathPredicate{}, fmt.Errorf("invalid XPath position %d", n)
+		}
+		return xpathPredicate{position: n}, nil
+	}
+
+	pred := xpathPredicate{}
+	name := s
+	if i := strings.Index(s, "="); i >= 0 {
+		name = strings.TrimSpace(s[:i])
+		value := strings.TrimSpace(s[i+1:])
+
+		// only a single string literal is supported, not expressions
+		if len(value) < 2 || (value[0] != '\'' && value[0] != '"') || value[len(value)-1] != value[0] ||
+			strings.IndexByte(value[1:len(value)-1], value[0]) >= 0 {
+			return xpathPredicate{}, ErrPreprocessingUnsupported
+		}
+		value = value[1 : len(value)-1]
+		pred.value = &value
+	}
+
+	if strings.HasPrefix(name, "@") {
+		pred.attr = name[1:]
+		if pred.attr != "*" && !isXMLName(pred.attr) {
+			return xpathPredicate{}, ErrPreprocessingUnsupported
+		}
+	} else {
+		pred.child = name
+		if pred.child != "*" && !isXMLName(pred.child) {
+			return xpathPredicate{}, ErrPreprocessingUnsupported
+		}
+	}
+
+	return pred, nil
+}
+
+// isXMLName returns true if s is an XML name without a namespace prefix.
+// Prefixes are not supported as namespaces are ignored.
+func isXMLName(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for i, r := range s {
+		switch {
+		case unicode.IsLetter(r) || r == '_':
+		case i > 0 && (unicode.IsDigit(r) || r == '-' || r == '.'):
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// isXPathNodeTest returns true if s is a location step supported by the
+// XPath engine: an element or attribute name, a wildcard, a node type test or
+// an abbreviated step.
+func isXPathNodeTest(s string) bool {
+	switch s {
+	case "*", "@*", "node()", "text()", ".", "..":
+		return true
+	}
+
+	return isXMLName(strings.TrimPrefix(s, "@"))
+}
+
+// matches returns true if the given node satisfies the predicate.
+func (c *xpathPredicate) matches(node *xmlNode) bool {
+	candidates := node.children
+	name := c.child
+	if c.attr != "" {
+		candidates = node.attrs
+		name = c.attr
+	}
+
+	for _, n := range candidates {
+		if n.kind == xmlNodeText || (name != "*" && n.name != name) {
+			continue
+		}
+
+		if c.value == nil || n.text() == *c.value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// xmlDescendants calls fn for the given node and all of its descendants.
+func xmlDescendants(node *xmlNode, fn func(*xmlNode)) {
+	fn(node)
+	for _, child := range node.children {
+		xmlDescendants(child, fn)
+	}
+}
+
+// selectStep returns the nodes selected by a location step from the given
+// context node.
+func (c *xpathStep) selectStep(node *xmlNode) []*xmlNode {
+	switch c.name {
+	case ".":
+		return []*xmlNode{node}
+	case "..":
+		if node.parent == nil {
+			return nil
+		}
+		return []*xmlNode{node.parent}
+	}
+
+	out := make([]*xmlNode, 0)
+	match := func(n *xmlNode) {
+		var candidates []*xmlNode
+		switch {
+		case strings.HasPrefix(c.name, "@"):
+			for _, attr := range n.attrs {
+				if c.name == "@*" || attr.name == c.name[1:] {
+					candidates = append(candidates, attr)
+				}
+			}
+
+		default:
+			for _, child := range n.children {
+				switch {
+				case c.name == "node()",
+					c.name == "text()" && child.kind == xmlNodeText,
+					child.kind == xmlNodeElement && (c.name == "*" || child.name == c.name):
+					candidates = append(candidates, child)
+				}
+			}
+		}
+
+		// predicates filter the candidates of each context node in turn
+		for _, pred := range c.predicates {
+			filtered := make([]*xmlNode, 0, len(candidates))
+			for i, candidate := range candidates {
+				switch {
+				case pred.position == -1:
+					if i == len(candidates)-1 {
+						filtered = append(filtered, candidate)
+					}
+				case pred.position > 0:
+					if i == pred.position-1 {
+						filtered = append(filtered, candidate)
+					}
+				case pred.matches(candidate):
+					filtered = append(filtered, candidate)
+				}
+			}
+			candidates = filtered
+		}
+
+		out = append(out, candidates...)
+	}
+
+	if c.deep {
+		xmlDescendants(node, match)
+	} else {
+		match(node)
+	}
+
+	return out
+}
+
+// evalXPath returns the nodes selected by the given location path.
+func evalXPath(doc *xmlNode, steps []xpathStep) []*xmlNode {
+	nodes := []*xmlNode{doc}
+	for i := range steps {
+		next := make([]*xmlNode, 0)
+		seen := make(map[*xmlNode]bool)
+		for _, node := range nodes {
+			for _, n := range steps[i].selectStep(node) {
+				if !seen[n] {
+					seen[n] = true
+					next = append(next, n)
+				}
+			}
+		}
+		nodes = next
+	}
+
+	return nodes
+}
+
+// xpathString evaluates the given XPath expression against the given XML
+// document and returns the result as an Item value.
+//
+// Node sets are returned as serialized XML and the string(), number(),
+// count() and sum() functions return their scalar result. The returned
+// boolean is false if the expression produced no value.
+//
+// ErrPreprocessingUnsupported is returned for other functions, operators and
+// predicates, which are not supported by the XPath engine.
+func xpathString(expr, data string) (string, bool, error) {
+	expr = strings.TrimSpace(expr)
+	function := ""
+	if i := strings.Index(expr, "("); i > 0 && strings.HasSuffix(expr, ")") {
+		switch name := expr[:i]; name {
+		case "string", "number", "count", "sum":
+			function = name
+			expr = expr[i+1 : len(expr)-1]
+		}
+	}
+
+	steps, err := compileXPath(expr)
+	if err == ErrPreprocessingUnsupported {
+		return "", false, err
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("cannot parse XPath expression %q: %v", expr, err)
+	}
+
+	doc, err := parseXML(data)
+	if err != nil {
+		return "", false, err
+	}
+
+	nodes := evalXPath(doc, steps)
+
+	switch function {
+	case "string":
+		if len(nodes) == 0 {
+			return "", true, nil
+		}
+		return nodes[0].text(), true, nil
+
+	case "number":
+		if len(nodes) == 0 {
+			return "NaN", true, nil
+		}
+
+		v, err := strconv.ParseFloat(strings.TrimSpace(nodes[0].text()), 64)
+		if err != nil {
+			return "NaN", true, nil
+		}
+		return formatNumeric(v), true, nil
+
+	case "count":
+		return strconv.Itoa(len(nodes)), true, nil
+
+	case "sum":
+		sum := 0.0
+		for _, node := range nodes {
+			v, err := strconv.ParseFloat(strings.TrimSpace(node.text()), 64)
+			if err != nil {
+				return "NaN", true, nil
+			}
+			sum += v
+		}
+		return formatNumeric(sum), true, nil
+	}
+
+	if len(nodes) == 0 {
+		return "", false, errXPathNoData
+	}
+
+	var b bytes.Buffer
+	for _, node := range nodes {
+		node.write(&b)
+	}
+
+	return b.String(), true, nil
+}
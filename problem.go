@@ -0,0 +1,348 @@
+package zabbix
+
+import (
+	"time"
+)
+
+const (
+	// TagEvalTypeAndOr means a search result must match all tag filters with
+	// different tag names and any tag filter with the same tag name.
+	TagEvalTypeAndOr = 0
+
+	// TagEvalTypeOr means a search result must match any tag filter.
+	TagEvalTypeOr = 2
+)
+
+const (
+	// TagOperatorContains matches tags with a value containing the given
+	// value.
+	TagOperatorContains = iota
+
+	// TagOperatorEquals matches tags with the given value.
+	TagOperatorEquals
+
+	// TagOperatorNotContains matches tags with a value not containing the
+	// given value.
+	TagOperatorNotContains
+
+	// TagOperatorNotEquals matches tags without the given value.
+	TagOperatorNotEquals
+
+	// TagOperatorExists matches if a tag with the given name exists.
+	TagOperatorExists
+
+	// TagOperatorNotExists matches if no tag with the given name exists.
+	TagOperatorNotExists
+)
+
+// Problem represents a Zabbix Problem returned from the Zabbix API. A Problem
+// is a problem Event which is unresolved or was recently resolved.
+//
+// See: https://www.zabbix.com/documentation/6.0/manual/api/reference/problem/object
+type Problem struct {
+	// EventID is the ID of the problem Event.
+	EventID string
+
+	// Source is the type of the Event source.
+	//
+	// Source must be one of the EventSource constants.
+	Source int
+
+	// ObjectType is the type of the Object that is related to the Problem.
+	//
+	// ObjectType must be one of the EventObjectType constants.
+	ObjectType int
+
+	// ObjectID is the ID of the Object that caused the Problem.
+	ObjectID string
+
+	// Timestamp is the time at which the Problem occurred.
+	Timestamp time.Time
+
+	// RecoveryEventID is the ID of the recovery Event, or empty if the
+	// Problem is unresolved.
+	RecoveryEventID string
+
+	// RecoveryTimestamp is the time at which the Problem was resolved, or
+	// the zero time if the Problem is unresolved.
+	RecoveryTimestamp time.Time
+
+	// CorrelationID is the ID of the correlation rule which generated the
+	// recovery Event.
+	CorrelationID string
+
+	// UserID is the ID of the user who closed the Problem manually.
+	UserID string
+
+	// Name is the resolved name of the Problem.
+	Name string
+
+	// Acknowledged indicates if the Problem has been acknowledged.
+	Acknowledged bool
+
+	// Severity is the current severity of the Problem.
+	//
+	// Severity must be one of the TriggerSeverity constants.
+	Severity int
+
+	// Suppressed indicates if the Problem is suppressed by a maintenance.
+	Suppressed bool
+
+	// OpData is the operational data of the Problem.
+	OpData string
+
+	// Acknowledges are the updates of the Problem, in reverse chronological
+	// order.
+	//
+	// Acknowledges is only populated if ProblemGetParams.SelectAcknowledges is
+	// given in the query parameters that returned this Problem.
+	Acknowledges []Acknowledgement
+
+	// Tags are the tags of the Problem.
+	//
+	// Tags is only populated if ProblemGetParams.SelectTags is given in the
+	// query parameters that returned this Problem.
+	Tags []EventTag
+
+	// Suppression describes the maintenances or users which suppress the
+	// Problem.
+	//
+	// Suppression is only populated if ProblemGetParams.SelectSuppressionData
+	// is given in the query parameters that returned this Problem.
+	Suppression []ProblemSuppression
+
+	// Hosts are the Hosts of the Trigger which caused the Problem.
+	//
+	// Hosts is only populated by Session.ResolveProblemHosts, as problem.get
+	// does not return Hosts.
+	Hosts []Host
+}
+
+// Resolved returns true if the Problem has been resolved.
+func (c *Problem) Resolved() bool {
+	return c.RecoveryEventID != "" && c.RecoveryEventID != "0"
+}
+
+// Duration returns the duration of the Problem until it was resolved, or
+// until now if it is unresolved.
+func (c *Problem) Duration() time.Duration {
+	if c.Resolved() {
+		return c.RecoveryTimestamp.Sub(c.Timestamp)
+	}
+
+	return time.Since(c.Timestamp)
+}
+
+// ProblemSuppression describes why a Problem is suppressed.
+type ProblemSuppression struct {
+	// MaintenanceID is the ID of the maintenance which suppresses the
+	// Problem.
+	MaintenanceID string
+
+	// UserID is the ID of the user who suppressed the Problem manually.
+	UserID string
+
+	// Until is the time until which the Problem is suppressed, or the zero
+	// time if it is suppressed indefinitely.
+	Until time.Time
+}
+
+// ProblemTagFilter filters Problems by their tags.
+type ProblemTagFilter struct {
+	// Tag is the tag name.
+	Tag string `json:"tag"`
+
+	// Value is the tag value to compare with.
+	Value string `json:"value"`
+
+	// Operator must be one of the TagOperator constants.
+	Operator int `json:"operator"`
+}
+
+// ProblemGetParams is query params for problem.get call
+//
+// See: https://www.zabbix.com/documentation/6.0/manual/api/reference/problem/get
+type ProblemGetParams struct {
+	GetParameters
+
+	// EventIDs filters search results to Problems that matched the given
+	// Event IDs.
+	EventIDs []string `json:"eventids,omitempty"`
+
+	// GroupIDs filters search results to Problems for hosts that are members
+	// of the given Group IDs.
+	GroupIDs []string `json:"groupids,omitempty"`
+
+	// HostIDs filters search results to Problems for hosts that matched the
+	// given Host IDs.
+	HostIDs []string `json:"hostids,omitempty"`
+
+	// ObjectIDs filters search results to Problems for Objects that matched
+	// the given Object IDs.
+	ObjectIDs []string `json:"objectids,omitempty"`
+
+	// Source filters search results to Problems created by the given source.
+	// Must be one of the EventSource constants.
+	//
+	// Default: EventSourceTrigger
+	Source int `json:"source"`
+
+	// ObjectType filters search results to Problems created by the given
+	// Object Type. Must be one of the EventObjectType constants.
+	//
+	// Default: EventObjectTypeTrigger
+	ObjectType int `json:"object"`
+
+	// Severities filters search results to Problems with the given
+	// severities. Each severity must be one of the TriggerSeverity constants.
+	Severities []int `json:"severities,omitempty"`
+
+	// Recent causes recently resolved Problems to be included in the search
+	// results.
+	Recent bool `json:"recent,omitempty"`
+
+	// Acknowledged filters search results to acknowledged Problems if true,
+	// or unacknowledged Problems if false.
+	Acknowledged *bool `json:"acknowledged,omitempty"`
+
+	// Suppressed filters search results to suppressed Problems if true, or
+	// unsuppressed Problems if false.
+	Suppressed *bool `json:"suppressed,omitempty"`
+
+	// EvalType is the evaluation method of the tag filters in Tags.
+	//
+	// EvalType must be one of the TagEvalType constants.
+	EvalType int `json:"evaltype,omitempty"`
+
+	// Tags filters search results to Problems with the given tags.
+	Tags []ProblemTagFilter `json:"tags,omitempty"`
+
+	// MinEventID filters search results to Problems with an ID greater or
+	// equal to the given ID.
+	MinEventID string `json:"eventid_from,omitempty"`
+
+	// MaxEventID filters search results to Problems with an ID lesser or
+	// equal to the given ID.
+	MaxEventID string `json:"eventid_till,omitempty"`
+
+	// MinTime filters search results to Problems created at or after the
+	// given timestamp.
+	MinTime int64 `json:"time_from,omitempty"`
+
+	// MaxTime filters search results to Problems created at or before the
+	// given timestamp.
+	MaxTime int64 `json:"time_till,omitempty"`
+
+	// SelectAcknowledges causes the updates of each Problem to be attached in
+	// the search results in reverse chronological order.
+	SelectAcknowledges SelectQuery `json:"selectAcknowledges,omitempty"`
+
+	// SelectTags causes the tags of each Problem to be attached in the search
+	// results.
+	SelectTags SelectQuery `json:"selectTags,omitempty"`
+
+	// SelectSuppressionData causes the maintenances and users which suppress
+	// each Problem to be attached in the search results.
+	SelectSuppressionData SelectQuery `json:"selectSuppressionData,omitempty"`
+
+	// GroupCount causes a CountOutput query to return the number of Problems
+	// grouped by the values of the fields given in OutputFields.
+	GroupCount bool `json:"groupCount,omitempty"`
+}
+
+// GetProblems queries the Zabbix API for Problems matching the given search
+// parameters.
+//
+// ErrNotFound is returned if the search result set is empty.
+// An error is returned if a transport, parsing or API error occurs.
+func (c *Session) GetProblems(params ProblemGetParams) ([]Problem, error) {
+	problems := make(jProblems, 0)
+	err := c.Get("problem.get", params, &problems)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(problems) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return problems.Problems()
+}
+
+// ResolveProblemHosts populates the Hosts of each of the given Problems which
+// was caused by a Trigger, with the Hosts of the Trigger.
+//
+// An error is returned if a transport, parsing or API error occurs.
+func (c *Session) ResolveProblemHosts(problems []Problem) error {
+	triggerIDs := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, problem := range problems {
+		if problem.ObjectType == EventObjectTypeTrigger && !seen[problem.ObjectID] {
+			seen[problem.ObjectID] = true
+			triggerIDs = append(triggerIDs, problem.ObjectID)
+		}
+	}
+
+	if len(triggerIDs) == 0 {
+		return nil
+	}
+
+	triggers, err := c.GetTriggers(TriggerGetParams{
+		GetParameters: GetParameters{
+			OutputFields: SelectFields{"triggerid"},
+		},
+		TriggerIDs:  triggerIDs,
+		SelectHosts: SelectFields{"hostid", "host", "name"},
+	})
+	if err != nil && err != ErrNotFound {
+		return err
+	}
+
+	hosts := make(map[string][]Host, len(triggers))
+	for _, trigger := range triggers {
+		hosts[trigger.TriggerID] = trigger.Hosts
+	}
+
+	for i := range problems {
+		if problems[i].ObjectType == EventObjectTypeTrigger {
+			problems[i].Hosts = hosts[problems[i].ObjectID]
+		}
+	}
+
+	return nil
+}
+
+// CountProblems queries the Zabbix API for the number of Problems matching the
+// given search parameters.
+//
+// An error is returned if a transport, parsing or API error occurs.
+func (c *Session) CountProblems(params ProblemGetParams) (int, error) {
+	params.CountOutput = true
+	params.GroupCount = false
+	return c.count("problem.get", params)
+}
+
+// CountProblemsBy queries the Zabbix API for the number of Problems matching
+// the given search parameters, grouped by the values of the given fields.
+//
+// An error is returned if a transport, parsing or API error occurs.
+func (c *Session) CountProblemsBy(params ProblemGetParams, fields ...string) ([]GroupCount, error) {
+	params.CountOutput = true
+	params.GroupCount = true
+	params.OutputFields = SelectFields(fields)
+	return c.groupCount("problem.get", params)
+}
+
+// CountProblemsBySeverity queries the Zabbix API for the number of Problems
+// matching the given search parameters for each severity. The returned map is
+// keyed by the TriggerSeverity constants.
+//
+// An error is returned if a transport, parsing or API error occurs.
+func (c *Session) CountProblemsBySeverity(params ProblemGetParams) (map[int]int, error) {
+	groups, err := c.CountProblemsBy(params, "severity")
+	if err != nil {
+		return nil, err
+	}
+
+	return countBySeverity(groups, "severity")
+}
@@ -0,0 +1,146 @@
+package zabbix
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// unixTime parses the given Unix timestamp and nanoseconds as returned by the
+// Zabbix API. A timestamp which is empty or zero is the zero time.
+func unixTime(clock, ns string) (time.Time, error) {
+	sec, err := atoi(clock)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if sec == 0 {
+		return time.Time{}, nil
+	}
+
+	nsec, err := atoi(ns)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Unix(int64(sec), int64(nsec)), nil
+}
+
+// jProblemSuppression is a private map for the suppression data of the Zabbix
+// API Problem object.
+type jProblemSuppression struct {
+	MaintenanceID string `json:"maintenanceid"`
+	UserID        string `json:"userid"`
+	SuppressUntil string `json:"suppress_until"`
+}
+
+// jProblem is a private map for the Zabbix API Problem object.
+// See: https://www.zabbix.com/documentation/6.0/manual/api/reference/problem/object
+type jProblem struct {
+	EventID         string                `json:"eventid"`
+	Source          string                `json:"source"`
+	ObjectType      string                `json:"object"`
+	ObjectID        string                `json:"objectid"`
+	Clock           string                `json:"clock"`
+	Nanoseconds     string                `json:"ns"`
+	RecoveryEventID string                `json:"r_eventid"`
+	RecoveryClock   string                `json:"r_clock"`
+	RecoveryNs      string                `json:"r_ns"`
+	CorrelationID   string                `json:"correlationid"`
+	UserID          string                `json:"userid"`
+	Name            string                `json:"name"`
+	Acknowledged    string                `json:"acknowledged"`
+	Severity        string                `json:"severity"`
+	Suppressed      string                `json:"suppressed"`
+	OpData          string                `json:"opdata"`
+	Acknowledges    jAcknowledgements     `json:"acknowledges"`
+	Tags            []EventTag            `json:"tags"`
+	SuppressionData []jProblemSuppression `json:"suppression_data"`
+}
+
+// Problem returns a native Go Problem struct mapped from the given JSON Problem
+// data.
+func (c *jProblem) Problem() (*Problem, error) {
+	var err error
+	problem := &Problem{
+		EventID:       c.EventID,
+		ObjectID:      c.ObjectID,
+		CorrelationID: c.CorrelationID,
+		UserID:        c.UserID,
+		Name:          c.Name,
+		Acknowledged:  c.Acknowledged == "1",
+		Suppressed:    c.Suppressed == "1",
+		OpData:        c.OpData,
+		Tags:          c.Tags,
+	}
+
+	if c.RecoveryEventID != "0" {
+		problem.RecoveryEventID = c.RecoveryEventID
+	}
+
+	if problem.Source, err = atoi(c.Source); err != nil {
+		return nil, fmt.Errorf("Error parsing Problem source: %v", err)
+	}
+
+	if problem.ObjectType, err = atoi(c.ObjectType); err != nil {
+		return nil, fmt.Errorf("Error parsing Problem object type: %v", err)
+	}
+
+	if problem.Severity, err = atoi(c.Severity); err != nil {
+		return nil, fmt.Errorf("Error parsing Problem severity: %v", err)
+	}
+
+	problem.Timestamp, err = unixTime(c.Clock, c.Nanoseconds)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing Problem timestamp: %v", err)
+	}
+
+	problem.RecoveryTimestamp, err = unixTime(c.RecoveryClock, c.RecoveryNs)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing Problem recovery timestamp: %v", err)
+	}
+
+	problem.Acknowledges, err = c.Acknowledges.Acknowledgements()
+	if err != nil {
+		return nil, err
+	}
+
+	if c.SuppressionData != nil {
+		problem.Suppression = make([]ProblemSuppression, len(c.SuppressionData))
+		for i, data := range c.SuppressionData {
+			until, err := strconv.ParseInt(data.SuppressUntil, 10, 64)
+			if err != nil && data.SuppressUntil != "" {
+				return nil, fmt.Errorf("Error parsing Problem suppression time: %v", err)
+			}
+
+			problem.Suppression[i] = ProblemSuppression{
+				MaintenanceID: data.MaintenanceID,
+				UserID:        data.UserID,
+			}
+			if until > 0 {
+				problem.Suppression[i].Until = time.Unix(until, 0)
+			}
+		}
+	}
+
+	return problem, nil
+}
+
+// jProblems is a slice of jProblem structs.
+type jProblems []jProblem
+
+// Problems returns a native Go slice of Problems mapped from the given JSON
+// Problems data.
+func (c jProblems) Problems() ([]Problem, error) {
+	out := make([]Problem, len(c))
+	for i, jproblem := range c {
+		problem, err := jproblem.Problem()
+		if err != nil {
+			return nil, fmt.Errorf("Error mapping Problem %d in response: %v", i, err)
+		}
+
+		out[i] = *problem
+	}
+
+	return out, nil
+}
@@ -0,0 +1,94 @@
+package zabbix
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestProblemMapping(t *testing.T) {
+	data := `{
+		"eventid": "500",
+		"source": "0",
+		"object": "0",
+		"objectid": "100",
+		"clock": "1600000000",
+		"ns": "5",
+		"r_eventid": "0",
+		"r_clock": "0",
+		"r_ns": "0",
+		"name": "High load",
+		"acknowledged": "1",
+		"severity": "4",
+		"suppressed": "1",
+		"acknowledges": [{"acknowledgeid": "1", "userid": "2", "eventid": "500", "clock": "1600000060", "message": "on it", "action": "6", "old_severity": "0", "new_severity": "0"}],
+		"tags": [{"tag": "service", "value": "web"}],
+		"suppression_data": [{"maintenanceid": "7", "suppress_until": "0"}]
+	}`
+
+	var jproblem jProblem
+	if err := json.Unmarshal([]byte(data), &jproblem); err != nil {
+		t.Fatalf("Error unmarshalling Problem: %v", err)
+	}
+
+	problem, err := jproblem.Problem()
+	if err != nil {
+		t.Fatalf("Error mapping Problem: %v", err)
+	}
+
+	if problem.Resolved() || !problem.RecoveryTimestamp.IsZero() {
+		t.Errorf("Expected Problem to be unresolved: %+v", problem)
+	}
+
+	if problem.Timestamp.Unix() != 1600000000 || problem.Timestamp.Nanosecond() != 5 {
+		t.Errorf("Unexpected Problem timestamp: %v", problem.Timestamp)
+	}
+
+	if problem.Severity != TriggerSeverityHigh || !problem.Acknowledged || !problem.Suppressed {
+		t.Errorf("Unexpected Problem state: %+v", problem)
+	}
+
+	if len(problem.Acknowledges) != 1 || problem.Acknowledges[0].Message != "on it" || problem.Acknowledges[0].Action != 6 {
+		t.Errorf("Unexpected Problem acknowledges: %+v", problem.Acknowledges)
+	}
+
+	if len(problem.Tags) != 1 || problem.Tags[0].Value != "web" {
+		t.Errorf("Unexpected Problem tags: %+v", problem.Tags)
+	}
+
+	if len(problem.Suppression) != 1 || problem.Suppression[0].MaintenanceID != "7" || !problem.Suppression[0].Until.IsZero() {
+		t.Errorf("Unexpected Problem suppression data: %+v", problem.Suppression)
+	}
+}
+
+func TestProblems(t *testing.T) {
+	session := GetTestSession(t)
+
+	problems, err := session.GetProblems(ProblemGetParams{
+		Recent:                true,
+		SelectAcknowledges:    SelectExtendedOutput,
+		SelectTags:            SelectExtendedOutput,
+		SelectSuppressionData: SelectExtendedOutput,
+	})
+	if err == ErrNotFound {
+		t.Skip("No problems found")
+	}
+	if err != nil {
+		t.Fatalf("Error getting Problems: %v", err)
+	}
+
+	if err := session.ResolveProblemHosts(problems); err != nil {
+		t.Fatalf("Error resolving Problem hosts: %v", err)
+	}
+
+	for i, problem := range problems {
+		if problem.EventID == "" {
+			t.Fatalf("Problem %d has no Event ID", i)
+		}
+
+		if problem.Timestamp.IsZero() {
+			t.Fatalf("Problem %d has no timestamp", i)
+		}
+	}
+
+	t.Logf("Validated %d Problems", len(problems))
+}
@@ -48,3 +48,22 @@ func NewRequest(method string, params interface{}) *Request {
 		AuthToken:      "", // set by session
 	}
 }
+
+// objectIDs returns the given IDs as an array of objects with the given ID
+// field, as expected by many of the Zabbix API create and mass update methods.
+//
+// For example, objectIDs("hostid", []string{"1", "2"}) is encoded as:
+//
+//	[{"hostid": "1"}, {"hostid": "2"}]
+func objectIDs(field string, ids []string) []map[string]string {
+	if ids == nil {
+		return nil
+	}
+
+	out := make([]map[string]string, len(ids))
+	for i, id := range ids {
+		out[i] = map[string]string{field: id}
+	}
+
+	return out
+}
@@ -0,0 +1,106 @@
+package series
+
+import (
+	"math"
+	"sort"
+)
+
+// Aggregator reduces the values of an interval to a single value.
+//
+// Aggregators return NaN if no values are given, except for Count.
+type Aggregator func(values []float64) float64
+
+var (
+	// Min returns the minimum value.
+	Min Aggregator = func(values []float64) float64 {
+		if len(values) == 0 {
+			return math.NaN()
+		}
+
+		out := values[0]
+		for _, v := range values[1:] {
+			out = math.Min(out, v)
+		}
+
+		return out
+	}
+
+	// Max returns the maximum value.
+	Max Aggregator = func(values []float64) float64 {
+		if len(values) == 0 {
+			return math.NaN()
+		}
+
+		out := values[0]
+		for _, v := range values[1:] {
+			out = math.Max(out, v)
+		}
+
+		return out
+	}
+
+	// Sum returns the sum of all values.
+	Sum Aggregator = func(values []float64) float64 {
+		if len(values) == 0 {
+			return math.NaN()
+		}
+
+		out := 0.0
+		for _, v := range values {
+			out += v
+		}
+
+		return out
+	}
+
+	// Avg returns the arithmetic mean of all values.
+	Avg Aggregator = func(values []float64) float64 {
+		return Sum(values) / float64(len(values))
+	}
+
+	// Count returns the number of values.
+	Count Aggregator = func(values []float64) float64 {
+		return float64(len(values))
+	}
+
+	// First returns the earliest value.
+	First Aggregator = func(values []float64) float64 {
+		if len(values) == 0 {
+			return math.NaN()
+		}
+
+		return values[0]
+	}
+
+	// Last returns the latest value.
+	Last Aggregator = func(values []float64) float64 {
+		if len(values) == 0 {
+			return math.NaN()
+		}
+
+		return values[len(values)-1]
+	}
+)
+
+// Percentile returns an Aggregator which returns the given percentile of all
+// values, between 0 and 100, interpolated linearly between the closest ranks.
+func Percentile(p float64) Aggregator {
+	return func(values []float64) float64 {
+		if len(values) == 0 || p < 0 || p > 100 {
+			return math.NaN()
+		}
+
+		sorted := make([]float64, len(values))
+		copy(sorted, values)
+		sort.Float64s(sorted)
+
+		rank := p / 100 * float64(len(sorted)-1)
+		lower := int(math.Floor(rank))
+		upper := int(math.Ceil(rank))
+		if lower == upper {
+			return sorted[lower]
+		}
+
+		return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+	}
+}
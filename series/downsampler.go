@@ -0,0 +1,103 @@
+package series
+
+import (
+	"time"
+
+	"github.com/cavaliercoder/go-zabbix"
+)
+
+// A Downsampler aggregates values of one or more Items into intervals as they
+// are received, so that History may be downsampled while it is streamed
+// without holding all values in memory.
+//
+// Values of each Item must be added in order of time. When a value is added
+// for a later interval, the previous interval of the Item is complete and its
+// aggregate is passed to the emit function.
+//
+//	d := series.NewDownsampler(time.Hour, series.Max, func(itemID int, p series.Point) error {
+//		fmt.Println(itemID, p.Time, p.Value)
+//		return nil
+//	})
+//
+//	if err := session.StreamHistories(params, 1000, d.AddHistory); err != nil {
+//		panic(err)
+//	}
+//
+//	if err := d.Flush(); err != nil {
+//		panic(err)
+//	}
+type Downsampler struct {
+	interval time.Duration
+	fn       Aggregator
+	emit     func(itemID int, p Point) error
+	buckets  map[int]*Bucket
+	order    []int
+}
+
+// NewDownsampler returns a new Downsampler which passes the result of the
+// given Aggregator for each interval of the given duration to emit.
+func NewDownsampler(interval time.Duration, fn Aggregator, emit func(itemID int, p Point) error) *Downsampler {
+	return &Downsampler{
+		interval: interval,
+		fn:       fn,
+		emit:     emit,
+		buckets:  make(map[int]*Bucket),
+	}
+}
+
+// Add adds a value of the given Item to the Downsampler.
+//
+// An error is returned if emit returns an error for a completed interval.
+func (c *Downsampler) Add(itemID int, p Point) error {
+	start := truncate(p.Time, c.interval)
+	b, ok := c.buckets[itemID]
+	if !ok {
+		c.order = append(c.order, itemID)
+	}
+
+	if ok && !b.Start.Equal(start) {
+		if err := c.emit(itemID, Point{Time: b.Start, Value: c.fn(b.Values)}); err != nil {
+			return err
+		}
+		ok = false
+	}
+
+	if !ok {
+		b = &Bucket{Start: start}
+		c.buckets[itemID] = b
+	}
+
+	b.Values = append(b.Values, p.Value)
+	return nil
+}
+
+// AddHistory adds a numeric History to the Downsampler. It may be passed
+// directly to Session.StreamHistories.
+//
+// An error is returned if the History value type is not numeric or emit
+// returns an error for a completed interval.
+func (c *Downsampler) AddHistory(history zabbix.History) error {
+	p, err := FromHistory(history)
+	if err != nil {
+		return err
+	}
+
+	return c.Add(history.ItemID, p)
+}
+
+// Flush passes the aggregate of the last interval of each Item to emit, in the
+// order the Items were first added.
+//
+// An error is returned if emit returns an error.
+func (c *Downsampler) Flush() error {
+	for _, itemID := range c.order {
+		b := c.buckets[itemID]
+		if err := c.emit(itemID, Point{Time: b.Start, Value: c.fn(b.Values)}); err != nil {
+			return err
+		}
+	}
+
+	c.buckets = make(map[int]*Bucket)
+	c.order = nil
+	return nil
+}
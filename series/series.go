@@ -0,0 +1,237 @@
+/*
+Package series provides client-side aggregation and downsampling of numeric
+Zabbix History, so that values fetched from the Zabbix API can feed charts and
+reports without a separate time-series database.
+
+	histories, err := session.GetHistories(params)
+	if err != nil {
+		panic(err)
+	}
+
+	items, err := series.FromHistories(histories)
+	if err != nil {
+		panic(err)
+	}
+
+	for itemID, s := range items {
+		hourly := s.Downsample(time.Hour, series.Avg)
+		fmt.Println(itemID, hourly)
+	}
+
+For long time ranges, a Downsampler aggregates History as it is streamed with
+Session.StreamHistories.
+*/
+package series
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/cavaliercoder/go-zabbix"
+)
+
+// Point is a single value of a time series.
+type Point struct {
+	// Time is the time of the value.
+	Time time.Time
+
+	// Value is the numeric value.
+	Value float64
+}
+
+// Series is a numeric time series in order of time.
+type Series []Point
+
+// Gap is a period with no values in a Series.
+type Gap struct {
+	// Start is the time of the last value before the gap.
+	Start time.Time
+
+	// End is the time of the first value after the gap.
+	End time.Time
+}
+
+// Duration returns the duration of the Gap.
+func (c Gap) Duration() time.Duration {
+	return c.End.Sub(c.Start)
+}
+
+// Bucket is the values of a Series in a single downsampling interval.
+type Bucket struct {
+	// Start is the start of the interval.
+	Start time.Time
+
+	// Values are the values received in the interval, in order of time.
+	Values []float64
+}
+
+// FromHistory returns the given numeric History as a Point.
+//
+// An error is returned if the History value type is not numeric.
+func FromHistory(history zabbix.History) (Point, error) {
+	v, err := history.Float()
+	if err != nil {
+		return Point{}, err
+	}
+
+	return Point{Time: history.Time(), Value: v}, nil
+}
+
+// FromHistories returns the given numeric Histories as a Series for each
+// Item, keyed by Item ID. Each Series is sorted in order of time.
+//
+// An error is returned if any History value type is not numeric.
+func FromHistories(histories []zabbix.History) (map[int]Series, error) {
+	out := make(map[int]Series)
+	for i, history := range histories {
+		p, err := FromHistory(history)
+		if err != nil {
+			return nil, fmt.Errorf("Error mapping History %d: %v", i, err)
+		}
+
+		out[history.ItemID] = append(out[history.ItemID], p)
+	}
+
+	for _, s := range out {
+		s.Sort()
+	}
+
+	return out, nil
+}
+
+// FromTrends returns the given Trends as a Series for each Item, keyed by Item
+// ID. The value of each Point is selected from each Trend by the given
+// function, such as func(t zabbix.Trend) float64 { return t.ValueAvg }.
+func FromTrends(trends []zabbix.Trend, value func(zabbix.Trend) float64) map[int]Series {
+	out := make(map[int]Series)
+	for _, trend := range trends {
+		out[trend.ItemID] = append(out[trend.ItemID], Point{
+			Time:  trend.Time(),
+			Value: value(trend),
+		})
+	}
+
+	for _, s := range out {
+		s.Sort()
+	}
+
+	return out
+}
+
+// Sort sorts the Series in order of time.
+func (c Series) Sort() {
+	sort.SliceStable(c, func(i, j int) bool {
+		return c[i].Time.Before(c[j].Time)
+	})
+}
+
+// Values returns the values of the Series.
+func (c Series) Values() []float64 {
+	out := make([]float64, len(c))
+	for i, p := range c {
+		out[i] = p.Value
+	}
+
+	return out
+}
+
+// Buckets returns the values of the Series grouped into consecutive intervals
+// of the given duration, aligned to the Unix epoch. Intervals with no values
+// are omitted.
+func (c Series) Buckets(interval time.Duration) []Bucket {
+	out := make([]Bucket, 0)
+	for _, p := range c {
+		start := truncate(p.Time, interval)
+		if len(out) == 0 || !out[len(out)-1].Start.Equal(start) {
+			out = append(out, Bucket{Start: start})
+		}
+
+		b := &out[len(out)-1]
+		b.Values = append(b.Values, p.Value)
+	}
+
+	return out
+}
+
+// truncate returns the start of the interval of the given duration, aligned to
+// the Unix epoch, which contains the given time.
+func truncate(t time.Time, interval time.Duration) time.Time {
+	if interval <= 0 {
+		return t
+	}
+
+	ns := t.UnixNano()
+	offset := ns % int64(interval)
+	if offset < 0 {
+		offset += int64(interval)
+	}
+
+	return time.Unix(0, ns-offset)
+}
+
+// Downsample returns a Series with a single Point for each interval of the
+// given duration, which is the result of the given Aggregator applied to all
+// values in the interval. Each Point has the time of the start of its interval.
+// Intervals with no values are omitted.
+func (c Series) Downsample(interval time.Duration, fn Aggregator) Series {
+	buckets := c.Buckets(interval)
+	out := make(Series, len(buckets))
+	for i, b := range buckets {
+		out[i] = Point{Time: b.Start, Value: fn(b.Values)}
+	}
+
+	return out
+}
+
+// Gaps returns all periods between consecutive values of the Series which are
+// longer than the given duration, such as the update interval of an Item plus
+// some tolerance.
+func (c Series) Gaps(max time.Duration) []Gap {
+	out := make([]Gap, 0)
+	for i := 1; i < len(c); i++ {
+		if c[i].Time.Sub(c[i-1].Time) > max {
+			out = append(out, Gap{Start: c[i-1].Time, End: c[i].Time})
+		}
+	}
+
+	return out
+}
+
+// Delta returns the change between each consecutive value of the Series, at
+// the time of the later value.
+//
+// Negative changes are treated as a counter reset and omitted.
+func (c Series) Delta() Series {
+	out := make(Series, 0, len(c))
+	for i := 1; i < len(c); i++ {
+		delta := c[i].Value - c[i-1].Value
+		if delta < 0 {
+			continue
+		}
+
+		out = append(out, Point{Time: c[i].Time, Value: delta})
+	}
+
+	return out
+}
+
+// Rate returns the change per second between each consecutive value of the
+// Series, at the time of the later value.
+//
+// Negative changes are treated as a counter reset and omitted, as are values
+// with the same time as the previous value.
+func (c Series) Rate() Series {
+	out := make(Series, 0, len(c))
+	for i := 1; i < len(c); i++ {
+		delta := c[i].Value - c[i-1].Value
+		seconds := c[i].Time.Sub(c[i-1].Time).Seconds()
+		if delta < 0 || seconds <= 0 {
+			continue
+		}
+
+		out = append(out, Point{Time: c[i].Time, Value: delta / seconds})
+	}
+
+	return out
+}
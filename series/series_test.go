@@ -0,0 +1,130 @@
+package series
+
+import (
+	"math"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/cavaliercoder/go-zabbix"
+)
+
+func testSeries(values ...float64) Series {
+	out := make(Series, len(values))
+	for i, v := range values {
+		out[i] = Point{Time: time.Unix(int64(i*30), 0), Value: v}
+	}
+
+	return out
+}
+
+func TestAggregators(t *testing.T) {
+	values := []float64{4, 1, 3, 2}
+	tests := []struct {
+		Name   string
+		Fn     Aggregator
+		Expect float64
+	}{
+		{"min", Min, 1},
+		{"max", Max, 4},
+		{"sum", Sum, 10},
+		{"avg", Avg, 2.5},
+		{"count", Count, 4},
+		{"first", First, 4},
+		{"last", Last, 2},
+		{"p50", Percentile(50), 2.5},
+		{"p100", Percentile(100), 4},
+	}
+
+	for _, test := range tests {
+		if v := test.Fn(values); v != test.Expect {
+			t.Errorf("Expected %s to return %v, got %v", test.Name, test.Expect, v)
+		}
+	}
+
+	if v := Avg(nil); !math.IsNaN(v) {
+		t.Errorf("Expected avg of no values to be NaN, got %v", v)
+	}
+}
+
+func TestDownsample(t *testing.T) {
+	// values every 30 seconds for 3 minutes
+	s := testSeries(1, 2, 3, 4, 5, 6)
+	out := s.Downsample(time.Minute, Sum)
+	if len(out) != 3 {
+		t.Fatalf("Expected 3 intervals, got %d", len(out))
+	}
+
+	for i, expect := range []float64{3, 7, 11} {
+		if out[i].Value != expect || out[i].Time.Unix() != int64(i*60) {
+			t.Errorf("Expected interval %d to be %v at %d, got %+v", i, expect, i*60, out[i])
+		}
+	}
+}
+
+func TestGaps(t *testing.T) {
+	s := testSeries(1, 2, 3)
+	s = append(s, Point{Time: time.Unix(300, 0), Value: 4})
+	gaps := s.Gaps(time.Minute)
+	if len(gaps) != 1 || gaps[0].Start.Unix() != 60 || gaps[0].Duration() != 4*time.Minute {
+		t.Errorf("Unexpected gaps: %+v", gaps)
+	}
+}
+
+func TestRate(t *testing.T) {
+	// counter resets after the third value
+	s := testSeries(0, 60, 120, 30, 90)
+	rate := s.Rate()
+	if len(rate) != 3 {
+		t.Fatalf("Expected 3 rates, got %d", len(rate))
+	}
+
+	for i, p := range rate {
+		if p.Value != 2 {
+			t.Errorf("Expected rate %d to be 2, got %v", i, p.Value)
+		}
+	}
+
+	if delta := s.Delta(); len(delta) != 3 || delta[0].Value != 60 {
+		t.Errorf("Unexpected deltas: %+v", delta)
+	}
+}
+
+func TestDownsampler(t *testing.T) {
+	emitted := make(map[int][]float64)
+	d := NewDownsampler(time.Minute, Max, func(itemID int, p Point) error {
+		emitted[itemID] = append(emitted[itemID], p.Value)
+		return nil
+	})
+
+	for i := 0; i < 4; i++ {
+		for _, itemID := range []int{1, 2} {
+			err := d.AddHistory(zabbix.History{
+				ItemID:    itemID,
+				Clock:     i * 30,
+				ValueType: zabbix.ItemValueTypeUnsigned,
+				Value:     strconv.Itoa(10 + i*itemID),
+			})
+			if err != nil {
+				t.Fatalf("Error adding History: %v", err)
+			}
+		}
+	}
+
+	if err := d.Flush(); err != nil {
+		t.Fatalf("Error flushing Downsampler: %v", err)
+	}
+
+	if len(emitted[1]) != 2 || emitted[1][0] != 11 || emitted[1][1] != 13 {
+		t.Errorf("Unexpected values for Item 1: %v", emitted[1])
+	}
+
+	if len(emitted[2]) != 2 || emitted[2][1] != 16 {
+		t.Errorf("Unexpected values for Item 2: %v", emitted[2])
+	}
+
+	err := d.AddHistory(zabbix.History{ValueType: zabbix.ItemValueTypeText, Value: "x"})
+	if err == nil {
+		t.Errorf("Expected an error adding a text History")
+	}
+}
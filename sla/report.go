@@ -0,0 +1,142 @@
+package sla
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+// formatUptime formats an uptime percentage with three decimals.
+func formatUptime(v float64) string {
+	return strconv.FormatFloat(v, 'f', 3, 64)
+}
+
+// formatSeconds formats a duration as whole seconds.
+func formatSeconds(d time.Duration) string {
+	return strconv.FormatInt(int64(d/time.Second), 10)
+}
+
+// WriteText writes the Report as aligned text tables of Triggers and host
+// groups.
+func (c *Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "Availability from %s till %s\n", c.From.Format(time.RFC3339), c.Till.Format(time.RFC3339))
+
+	for _, section := range []struct {
+		Title   string
+		Results []Result
+	}{
+		{"Triggers", c.Triggers},
+		{"Host groups", c.Groups},
+	} {
+		if len(section.Results) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(tw, "\n%s\n", section.Title)
+		fmt.Fprintln(tw, "ID\tNAME\tUPTIME\tDOWNTIME\tMAINTENANCE\tINCIDENTS\tMTTR\tMTBF")
+		for _, r := range section.Results {
+			fmt.Fprintf(tw, "%s\t%s\t%s%%\t%v\t%v\t%d\t%v\t%v\n",
+				r.ID,
+				r.Name,
+				formatUptime(r.Uptime()),
+				r.Downtime.Truncate(time.Second),
+				r.Maintenance.Truncate(time.Second),
+				r.Incidents,
+				r.MTTR().Truncate(time.Second),
+				r.MTBF().Truncate(time.Second),
+			)
+		}
+	}
+
+	return tw.Flush()
+}
+
+// WriteCSV writes the Report as CSV with a header row. Each row is a Trigger
+// or host group, as given by the type column. Durations are written in
+// seconds.
+func (c *Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"type", "id", "name", "period", "uptime", "downtime", "maintenance", "incidents", "mttr", "mtbf"})
+
+	for _, section := range []struct {
+		Type    string
+		Results []Result
+	}{
+		{"trigger", c.Triggers},
+		{"group", c.Groups},
+	} {
+		for _, r := range section.Results {
+			cw.Write([]string{
+				section.Type,
+				r.ID,
+				r.Name,
+				formatSeconds(r.Period),
+				formatUptime(r.Uptime()),
+				formatSeconds(r.Downtime),
+				formatSeconds(r.Maintenance),
+				strconv.Itoa(r.Incidents),
+				formatSeconds(r.MTTR()),
+				formatSeconds(r.MTBF()),
+			})
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// jResult is the JSON representation of a Result, with durations in seconds.
+type jResult struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	Period      int64   `json:"period"`
+	Uptime      float64 `json:"uptime"`
+	Downtime    int64   `json:"downtime"`
+	Maintenance int64   `json:"maintenance"`
+	Incidents   int     `json:"incidents"`
+	MTTR        int64   `json:"mttr"`
+	MTBF        int64   `json:"mtbf"`
+}
+
+// jResults returns the JSON representation of the given Results.
+func jResults(results []Result) []jResult {
+	out := make([]jResult, len(results))
+	for i, r := range results {
+		out[i] = jResult{
+			ID:          r.ID,
+			Name:        r.Name,
+			Period:      int64(r.Period / time.Second),
+			Uptime:      r.Uptime(),
+			Downtime:    int64(r.Downtime / time.Second),
+			Maintenance: int64(r.Maintenance / time.Second),
+			Incidents:   r.Incidents,
+			MTTR:        int64(r.MTTR() / time.Second),
+			MTBF:        int64(r.MTBF() / time.Second),
+		}
+	}
+
+	return out
+}
+
+// WriteJSON writes the Report as a JSON object with the period as Unix
+// timestamps and durations in seconds.
+func (c *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		From     int64     `json:"from"`
+		Till     int64     `json:"till"`
+		Triggers []jResult `json:"triggers"`
+		Groups   []jResult `json:"groups"`
+	}{
+		From:     c.From.Unix(),
+		Till:     c.Till.Unix(),
+		Triggers: jResults(c.Triggers),
+		Groups:   jResults(c.Groups),
+	})
+}
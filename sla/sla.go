@@ -0,0 +1,384 @@
+/*
+Package sla computes the availability of Zabbix Triggers and host groups from
+their problems, excluding maintenance windows.
+
+A Calculator is populated with Triggers, their Incidents and the Maintenances
+of their hosts, either from the Zabbix API with Fetch or from data which was
+fetched before:
+
+	from := time.Date(2021, time.March, 1, 0, 0, 0, 0, time.Local)
+	calc, err := sla.Fetch(session, triggerIDs, from, from.AddDate(0, 1, 0))
+	if err != nil {
+		panic(err)
+	}
+
+	report := calc.Calculate(from, from.AddDate(0, 1, 0))
+	if err := report.WriteText(os.Stdout); err != nil {
+		panic(err)
+	}
+*/
+package sla
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/cavaliercoder/go-zabbix"
+	"github.com/cavaliercoder/go-zabbix/incident"
+)
+
+// Lookback is how long before the start of a period Fetch looks for problems
+// which may still be unresolved at the start of the period.
+var Lookback = 31 * 24 * time.Hour
+
+// Result is the availability of a Trigger or host group in a period.
+type Result struct {
+	// ID is the ID of the Trigger or host group.
+	ID string
+
+	// Name is the name of the Trigger or host group.
+	Name string
+
+	// Period is the length of the period, excluding maintenance.
+	Period time.Duration
+
+	// Maintenance is the time in maintenance during the period.
+	Maintenance time.Duration
+
+	// Downtime is the time in problem state outside maintenance.
+	Downtime time.Duration
+
+	// Incidents is the number of problems outside maintenance. The Incidents
+	// of a host group are the problems of each of its Triggers, so concurrent
+	// problems of different Triggers are separate Incidents.
+	Incidents int
+}
+
+// Uptime returns the percentage of the period, excluding maintenance, which
+// was not in problem state. A period which was entirely in maintenance has an
+// uptime of 100%.
+func (c *Result) Uptime() float64 {
+	if c.Period <= 0 {
+		return 100
+	}
+
+	return 100 * float64(c.Period-c.Downtime) / float64(c.Period)
+}
+
+// MTTR returns the mean time to recovery, which is the mean downtime of each
+// incident, or zero if there were no incidents.
+func (c *Result) MTTR() time.Duration {
+	if c.Incidents == 0 {
+		return 0
+	}
+
+	return c.Downtime / time.Duration(c.Incidents)
+}
+
+// MTBF returns the mean time between failures, which is the uptime of the
+// period divided by the number of incidents, or zero if there were no
+// incidents.
+func (c *Result) MTBF() time.Duration {
+	if c.Incidents == 0 {
+		return 0
+	}
+
+	return (c.Period - c.Downtime) / time.Duration(c.Incidents)
+}
+
+// Report is the availability of Triggers and host groups in a period.
+type Report struct {
+	From time.Time
+	Till time.Time
+
+	// Triggers are the results of each Trigger, in the order given to the
+	// Calculator.
+	Triggers []Result
+
+	// Groups are the results of each host group of the Triggers, in order of
+	// their name. A host group is down while any of its Triggers is in
+	// problem state, and in maintenance while all of its Triggers are.
+	Groups []Result
+}
+
+// Calculator computes availability Reports.
+type Calculator struct {
+	// Triggers are the Triggers to report on. Triggers must have been queried
+	// with TriggerGetParams.SelectHosts, and with SelectGroups to report on
+	// host groups.
+	Triggers []zabbix.Trigger
+
+	// Incidents are the problems of the Triggers.
+	Incidents []incident.Incident
+
+	// Maintenances are the Maintenances of the hosts of the Triggers, which
+	// must have been queried with MaintenanceGetParams.SelectHosts,
+	// SelectGroups and SelectTimeperiods. Maintenance tags are not evaluated.
+	Maintenances []zabbix.Maintenance
+
+	// Location is the time zone of the Zabbix server, in which maintenance
+	// time periods are evaluated. If nil, the local time zone is used.
+	Location *time.Location
+}
+
+// Fetch queries the Zabbix API for the given Triggers, their problems and the
+// Maintenances of their hosts, to compute availability in the given period.
+//
+// An error is returned if a transport, parsing or API error occurs.
+func Fetch(session *zabbix.Session, triggerIDs []string, from, till time.Time) (*Calculator, error) {
+	triggers, err := session.GetTriggers(zabbix.TriggerGetParams{
+		TriggerIDs:   triggerIDs,
+		SelectHosts:  zabbix.SelectFields{"hostid", "host", "name"},
+		SelectGroups: zabbix.SelectFields{"groupid", "name"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(triggers))
+	for i, trigger := range triggers {
+		ids[i] = trigger.TriggerID
+	}
+
+	incidents, err := incident.Fetch(session, incident.Query{
+		TriggerIDs: ids,
+		From:       from.Add(-Lookback),
+		Till:       till,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	hostIDs := make([]string, 0)
+	groupIDs := make([]string, 0)
+	for _, trigger := range triggers {
+		for _, host := range trigger.Hosts {
+			hostIDs = append(hostIDs, host.HostID)
+		}
+		for _, group := range trigger.Groups {
+			groupIDs = append(groupIDs, group.GroupID)
+		}
+	}
+
+	maintenances, err := session.GetHostMaintenances(hostIDs, groupIDs)
+	if err != nil && err != zabbix.ErrNotFound {
+		return nil, err
+	}
+
+	return &Calculator{
+		Triggers:     triggers,
+		Incidents:    incidents,
+		Maintenances: maintenances,
+	}, nil
+}
+
+// maintenance returns the maintenance windows of the given Trigger in the
+// given period.
+func (c *Calculator) maintenance(trigger *zabbix.Trigger, from, till time.Time) spans {
+	loc := c.Location
+	if loc == nil {
+		loc = time.Local
+	}
+
+	out := make(spans, 0)
+	for i := range c.Maintenances {
+		m := &c.Maintenances[i]
+		if !m.AppliesTo(trigger.Hosts, trigger.Groups) {
+			continue
+		}
+
+		for _, w := range m.Windows(from, till, loc) {
+			out = append(out, span{w.Start, w.End})
+		}
+	}
+
+	return out.merge()
+}
+
+// Calculate computes the availability of each Trigger and host group in the
+// given period.
+func (c *Calculator) Calculate(from, till time.Time) *Report {
+	report := &Report{
+		From:     from,
+		Till:     till,
+		Triggers: make([]Result, len(c.Triggers)),
+		Groups:   make([]Result, 0),
+	}
+
+	period := spans{{from, till}}
+	type group struct {
+		result      Result
+		maintenance spans
+		problems    []spans
+	}
+	groups := make(map[string]*group)
+
+	for i := range c.Triggers {
+		trigger := &c.Triggers[i]
+		maintenance := c.maintenance(trigger, from, till)
+
+		// each problem outside maintenance, and in the period for the groups,
+		// which are only in maintenance while all of their Triggers are
+		down := make(spans, 0)
+		incidents := 0
+		problems := make([]spans, 0)
+		for _, in := range c.Incidents {
+			if in.TriggerID != trigger.TriggerID {
+				continue
+			}
+
+			end := till
+			if in.Resolved() && in.End.Before(till) {
+				end = in.End
+			}
+
+			problem := spans{{in.Start, end}}.intersect(period)
+			problems = append(problems, problem)
+
+			problem = problem.subtract(maintenance)
+			if problem.total() > 0 {
+				incidents++
+				down = append(down, problem...)
+			}
+		}
+		down = down.merge()
+
+		report.Triggers[i] = Result{
+			ID:          trigger.TriggerID,
+			Name:        trigger.Description,
+			Period:      till.Sub(from) - maintenance.total(),
+			Maintenance: maintenance.total(),
+			Downtime:    down.total(),
+			Incidents:   incidents,
+		}
+
+		for _, hostgroup := range trigger.Groups {
+			g, ok := groups[hostgroup.GroupID]
+			if !ok {
+				g = &group{
+					result:      Result{ID: hostgroup.GroupID, Name: hostgroup.Name},
+					maintenance: maintenance,
+				}
+				groups[hostgroup.GroupID] = g
+			} else {
+				g.maintenance = g.maintenance.intersect(maintenance)
+			}
+
+			g.problems = append(g.problems, problems...)
+		}
+	}
+
+	for _, g := range groups {
+		down := make(spans, 0)
+		for _, problem := range g.problems {
+			problem = problem.subtract(g.maintenance)
+			if problem.total() > 0 {
+				g.result.Incidents++
+				down = append(down, problem...)
+			}
+		}
+
+		g.result.Maintenance = g.maintenance.total()
+		g.result.Period = till.Sub(from) - g.result.Maintenance
+		g.result.Downtime = down.merge().total()
+		report.Groups = append(report.Groups, g.result)
+	}
+
+	sort.Slice(report.Groups, func(i, j int) bool {
+		if report.Groups[i].Name != report.Groups[j].Name {
+			return report.Groups[i].Name < report.Groups[j].Name
+		}
+		a, _ := strconv.Atoi(report.Groups[i].ID)
+		b, _ := strconv.Atoi(report.Groups[j].ID)
+		return a < b
+	})
+
+	return report
+}
+
+// span is a time range.
+type span struct {
+	start, end time.Time
+}
+
+// spans is a set of time ranges.
+type spans []span
+
+// merge returns the spans sorted with overlapping spans merged.
+func (c spans) merge() spans {
+	sorted := make(spans, 0, len(c))
+	for _, s := range c {
+		if s.start.Before(s.end) {
+			sorted = append(sorted, s)
+		}
+	}
+
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].start.Before(sorted[j].start) })
+	out := make(spans, 0, len(sorted))
+	for _, s := range sorted {
+		if n := len(out); n > 0 && !s.start.After(out[n-1].end) {
+			if s.end.After(out[n-1].end) {
+				out[n-1].end = s.end
+			}
+			continue
+		}
+		out = append(out, s)
+	}
+
+	return out
+}
+
+// intersect returns the time ranges which are in both sets of spans.
+func (c spans) intersect(other spans) spans {
+	out := make(spans, 0)
+	for _, a := range c.merge() {
+		for _, b := range other.merge() {
+			start, end := a.start, a.end
+			if b.start.After(start) {
+				start = b.start
+			}
+			if b.end.Before(end) {
+				end = b.end
+			}
+			if start.Before(end) {
+				out = append(out, span{start, end})
+			}
+		}
+	}
+
+	return out.merge()
+}
+
+// subtract returns the time ranges which are not in the other spans.
+func (c spans) subtract(other spans) spans {
+	out := c.merge()
+	for _, b := range other.merge() {
+		next := make(spans, 0, len(out))
+		for _, a := range out {
+			if !b.start.Before(a.end) || !b.end.After(a.start) {
+				next = append(next, a)
+				continue
+			}
+			if a.start.Before(b.start) {
+				next = append(next, span{a.start, b.start})
+			}
+			if b.end.Before(a.end) {
+				next = append(next, span{b.end, a.end})
+			}
+		}
+		out = next
+	}
+
+	return out
+}
+
+// total returns the sum of the durations of the spans.
+func (c spans) total() time.Duration {
+	var d time.Duration
+	for _, s := range c {
+		d += s.end.Sub(s.start)
+	}
+
+	return d
+}
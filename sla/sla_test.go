@@ -0,0 +1,126 @@
+package sla
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cavaliercoder/go-zabbix"
+	"github.com/cavaliercoder/go-zabbix/incident"
+)
+
+func testCalculator() (*Calculator, time.Time, time.Time) {
+	from := time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC)
+	till := from.Add(100 * time.Hour)
+	at := func(hour int) time.Time { return from.Add(time.Duration(hour) * time.Hour) }
+
+	group := zabbix.Hostgroup{GroupID: "2", Name: "Web servers"}
+	calc := &Calculator{
+		Triggers: []zabbix.Trigger{
+			{TriggerID: "1", Description: "web01 down", Hosts: []zabbix.Host{{HostID: "101"}}, Groups: []zabbix.Hostgroup{group}},
+			{TriggerID: "2", Description: "web02 down", Hosts: []zabbix.Host{{HostID: "102"}}, Groups: []zabbix.Hostgroup{group}},
+		},
+		Incidents: []incident.Incident{
+			// started before the period
+			{TriggerID: "1", Start: from.Add(-time.Hour), End: at(2), Recovery: &zabbix.Event{}},
+			// partially in maintenance
+			{TriggerID: "1", Start: at(10), End: at(14), Recovery: &zabbix.Event{}},
+			// entirely in maintenance
+			{TriggerID: "1", Start: at(20), End: at(21), Recovery: &zabbix.Event{}},
+			// overlaps the first incident of web01
+			{TriggerID: "2", Start: at(1), End: at(4), Recovery: &zabbix.Event{}},
+			// unresolved
+			{TriggerID: "2", Start: at(96)},
+		},
+		Maintenances: []zabbix.Maintenance{
+			{
+				Hosts:       []zabbix.Host{{HostID: "101"}},
+				ActiveSince: from,
+				ActiveTill:  till,
+				Timeperiods: []zabbix.Timeperiods{
+					{TimeperiodType: zabbix.MaintenancePeriodOnce, StartDate: at(12).Unix(), Period: 10 * 3600},
+				},
+			},
+		},
+		Location: time.UTC,
+	}
+
+	return calc, from, till
+}
+
+func TestCalculate(t *testing.T) {
+	calc, from, till := testCalculator()
+	report := calc.Calculate(from, till)
+
+	web01 := report.Triggers[0]
+	if web01.Period != 90*time.Hour || web01.Maintenance != 10*time.Hour {
+		t.Errorf("Unexpected period of web01: %v, maintenance %v", web01.Period, web01.Maintenance)
+	}
+
+	if web01.Downtime != 4*time.Hour || web01.Incidents != 2 {
+		t.Errorf("Unexpected downtime of web01: %v in %d incidents", web01.Downtime, web01.Incidents)
+	}
+
+	if web01.MTTR() != 2*time.Hour || web01.MTBF() != 43*time.Hour {
+		t.Errorf("Unexpected MTTR %v or MTBF %v of web01", web01.MTTR(), web01.MTBF())
+	}
+
+	web02 := report.Triggers[1]
+	if web02.Downtime != 7*time.Hour || web02.Incidents != 2 || web02.Uptime() != 93 {
+		t.Errorf("Unexpected availability of web02: %+v", web02)
+	}
+
+	// the group is down while either trigger is and never in maintenance, as
+	// web02 is not, so the problems of web01 in maintenance are downtime of
+	// the group, and the overlapping problems of web01 and web02 are separate
+	// incidents
+	if len(report.Groups) != 1 {
+		t.Fatalf("Expected 1 group, got %d", len(report.Groups))
+	}
+
+	group := report.Groups[0]
+	if group.Maintenance != 0 || group.Downtime != 13*time.Hour || group.Incidents != 5 {
+		t.Errorf("Unexpected availability of group: %+v", group)
+	}
+}
+
+func TestReport(t *testing.T) {
+	calc, from, till := testCalculator()
+	report := calc.Calculate(from, till)
+
+	var buf bytes.Buffer
+	if err := report.WriteCSV(&buf); err != nil {
+		t.Fatalf("Error writing CSV: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 || lines[1] != "trigger,1,web01 down,324000,95.556,14400,36000,2,7200,154800" {
+		t.Errorf("Unexpected CSV report:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := report.WriteJSON(&buf); err != nil {
+		t.Fatalf("Error writing JSON: %v", err)
+	}
+
+	var v struct {
+		Groups []struct {
+			Name     string
+			Downtime int64
+		}
+	}
+	if err := json.Unmarshal(buf.Bytes(), &v); err != nil || len(v.Groups) != 1 || v.Groups[0].Downtime != 46800 {
+		t.Errorf("Unexpected JSON report (%v):\n%s", err, buf.String())
+	}
+
+	buf.Reset()
+	if err := report.WriteText(&buf); err != nil {
+		t.Fatalf("Error writing text: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "Web servers") || !strings.Contains(buf.String(), "95.556%") {
+		t.Errorf("Unexpected text report:\n%s", buf.String())
+	}
+}
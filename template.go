@@ -0,0 +1,492 @@
+package zabbix
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Template represents a Zabbix Template returned from the Zabbix API.
+//
+// When a Template is given as a linked template in the parameters of a create
+// or update method, only TemplateID must be set.
+//
+// See: https://www.zabbix.com/documentation/current/manual/api/reference/template/object
+type Template struct {
+	// TemplateID is the unique ID of the Template.
+	TemplateID string `json:"templateid,omitempty"`
+
+	// Hostname is the technical name of the Template.
+	Hostname string `json:"host,omitempty"`
+
+	// DisplayName is the visible name of the Template.
+	DisplayName string `json:"name,omitempty"`
+
+	// Description of the Template.
+	Description string `json:"description,omitempty"`
+
+	// UUID is the universal unique identifier of the Template, used to link
+	// imported templates to existing ones. Only supported since Zabbix 5.4.
+	UUID string `json:"uuid,omitempty"`
+
+	// Groups contains all groups the Template belongs to. Since Zabbix 6.2,
+	// these are the template groups of the Template.
+	//
+	// Groups is only populated if TemplateGetParams.SelectGroups is given in
+	// the query parameters that returned this Template.
+	Groups []Hostgroup `json:"-"`
+
+	// ParentTemplates contains the Templates that are linked to this Template
+	// and from which it inherits.
+	//
+	// ParentTemplates is only populated if
+	// TemplateGetParams.SelectParentTemplates is given in the query parameters
+	// that returned this Template.
+	ParentTemplates []Template `json:"-"`
+
+	// ChildTemplates contains the Templates that this Template is linked to.
+	//
+	// ChildTemplates is only populated if TemplateGetParams.SelectTemplates is
+	// given in the query parameters that returned this Template.
+	ChildTemplates []Template `json:"-"`
+
+	// Hosts contains the Hosts that this Template is linked to.
+	//
+	// Hosts is only populated if TemplateGetParams.SelectHosts is given in the
+	// query parameters that returned this Template.
+	Hosts []Host `json:"-"`
+
+	// Items contains the Items of the Template.
+	//
+	// Items is only populated if TemplateGetParams.SelectItems is given in the
+	// query parameters that returned this Template.
+	Items []Item `json:"-"`
+
+	// Triggers contains the Triggers of the Template.
+	//
+	// Triggers is only populated if TemplateGetParams.SelectTriggers is given
+	// in the query parameters that returned this Template.
+	Triggers []Trigger `json:"-"`
+
+	// Macros contains the user macros of the Template.
+	//
+	// Macros is only populated if TemplateGetParams.SelectMacros is given in
+	// the query parameters that returned this Template.
+	Macros []HostMacro `json:"-"`
+
+	// Tags contains the tags of the Template.
+	//
+	// Tags is only populated if TemplateGetParams.SelectTags is given in the
+	// query parameters that returned this Template.
+	Tags []HostTag `json:"-"`
+}
+
+// TemplateGetParams represent the parameters for a `template.get` API call.
+//
+// See: https://www.zabbix.com/documentation/current/manual/api/reference/template/get#parameters
+type TemplateGetParams struct {
+	GetParameters
+
+	// TemplateIDs filters search results to templates with the given Template
+	// IDs.
+	TemplateIDs []string `json:"templateids,omitempty"`
+
+	// GroupIDs filters search results to templates that belong to the given
+	// Group IDs.
+	GroupIDs []string `json:"groupids,omitempty"`
+
+	// ParentTemplateIDs filters search results to templates that are linked to
+	// the given Template IDs.
+	ParentTemplateIDs []string `json:"parentTemplateids,omitempty"`
+
+	// HostIDs filters search results to templates that are linked to the given
+	// Host IDs.
+	HostIDs []string `json:"hostids,omitempty"`
+
+	// GraphIDs filters search results to templates that contain the given
+	// Graph IDs.
+	GraphIDs []string `json:"graphids,omitempty"`
+
+	// ItemIDs filters search results to templates that contain the given Item
+	// IDs.
+	ItemIDs []string `json:"itemids,omitempty"`
+
+	// TriggerIDs filters search results to templates that contain the given
+	// Trigger IDs.
+	TriggerIDs []string `json:"triggerids,omitempty"`
+
+	// WithItems filters search results to templates that have items.
+	WithItems bool `json:"with_items,omitempty"`
+
+	// WithTriggers filters search results to templates that have triggers.
+	WithTriggers bool `json:"with_triggers,omitempty"`
+
+	// WithGraphs filters search results to templates that have graphs.
+	WithGraphs bool `json:"with_graphs,omitempty"`
+
+	// WithWebScenarios filters search results to templates that have web
+	// scenarios.
+	WithWebScenarios bool `json:"with_httptests,omitempty"`
+
+	// SelectGroups causes the groups of each Template to be attached in the
+	// search results.
+	//
+	// Since Zabbix 6.2, GetTemplates selects the template groups of each
+	// Template instead.
+	SelectGroups SelectQuery `json:"selectGroups,omitempty"`
+
+	// SelectTemplateGroups causes the template groups of each Template to be
+	// attached to Template.Groups in the search results. Only supported since
+	// Zabbix 6.2.
+	SelectTemplateGroups SelectQuery `json:"selectTemplateGroups,omitempty"`
+
+	SelectTags            SelectQuery `json:"selectTags,omitempty"`
+	SelectHosts           SelectQuery `json:"selectHosts,omitempty"`
+	SelectTemplates       SelectQuery `json:"selectTemplates,omitempty"`
+	SelectParentTemplates SelectQuery `json:"selectParentTemplates,omitempty"`
+	SelectWebScenarios    SelectQuery `json:"selectHttpTests,omitempty"`
+	SelectItems           SelectQuery `json:"selectItems,omitempty"`
+	SelectDiscoveries     SelectQuery `json:"selectDiscoveries,omitempty"`
+	SelectTriggers        SelectQuery `json:"selectTriggers,omitempty"`
+	SelectGraphs          SelectQuery `json:"selectGraphs,omitempty"`
+	SelectMacros          SelectQuery `json:"selectMacros,omitempty"`
+}
+
+// GetTemplates queries the Zabbix API for Templates matching the given search
+// parameters.
+//
+// ErrNotFound is returned if the search result set is empty.
+// An error is returned if a transport, parsing or API error occurs.
+func (c *Session) GetTemplates(params TemplateGetParams) ([]Template, error) {
+	if params.SelectGroups != nil {
+		split, err := c.VersionAtLeast(6, 2)
+		if err != nil {
+			return nil, err
+		}
+
+		if split {
+			params.SelectTemplateGroups = params.SelectGroups
+			params.SelectGroups = nil
+		}
+	}
+
+	templates := make(jTemplates, 0)
+	err := c.Get("template.get", params, &templates)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(templates) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return templates.Templates()
+}
+
+// CountTemplates queries the Zabbix API for the number of Templates matching
+// the given search parameters.
+//
+// An error is returned if a transport, parsing or API error occurs.
+func (c *Session) CountTemplates(params TemplateGetParams) (int, error) {
+	params.CountOutput = true
+	return c.count("template.get", params)
+}
+
+// TemplateResponse represent template action response body
+type TemplateResponse struct {
+	TemplateIDs []string `json:"templateids"`
+}
+
+// TemplateCreateParams represent the parameters for a `template.create` API
+// call.
+//
+// See: https://www.zabbix.com/documentation/current/manual/api/reference/template/create
+type TemplateCreateParams struct {
+	// Hostname is the technical name of the Template.
+	Hostname string `json:"host"`
+
+	// DisplayName is the visible name of the Template.
+	DisplayName string `json:"name,omitempty"`
+
+	// Description of the Template.
+	Description string `json:"description,omitempty"`
+
+	// Groups are the groups to add the Template to. Only GroupID must be set
+	// for each group.
+	//
+	// Since Zabbix 6.2, each GroupID must be the ID of a TemplateGroup.
+	Groups []Hostgroup `json:"groups"`
+
+	// Templates are the Templates to link to the Template.
+	Templates []Template `json:"templates,omitempty"`
+
+	// Macros are the user macros to create for the Template.
+	Macros []HostMacro `json:"macros,omitempty"`
+
+	// Tags are the tags to assign to the Template.
+	Tags []HostTag `json:"tags,omitempty"`
+}
+
+// TemplateUpdateParams represent the parameters for a `template.update` API
+// call.
+//
+// Only the fields that are set are updated. Groups, Templates, Macros and Tags
+// replace the existing values of the Template when given.
+//
+// See: https://www.zabbix.com/documentation/current/manual/api/reference/template/update
+type TemplateUpdateParams struct {
+	// TemplateID is the ID of the Template to update.
+	TemplateID string `json:"templateid"`
+
+	Hostname    string `json:"host,omitempty"`
+	DisplayName string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	Groups    []Hostgroup `json:"groups,omitempty"`
+	Templates []Template  `json:"templates,omitempty"`
+	Macros    []HostMacro `json:"macros,omitempty"`
+	Tags      []HostTag   `json:"tags,omitempty"`
+
+	// TemplatesClear are the linked Templates to unlink and clear from the
+	// Template.
+	TemplatesClear []Template `json:"templates_clear,omitempty"`
+}
+
+// TemplateMassAddParams represent the parameters for a `template.massadd` API
+// call.
+//
+// See: https://www.zabbix.com/documentation/current/manual/api/reference/template/massadd
+type TemplateMassAddParams struct {
+	// TemplateIDs are the IDs of the Templates to update.
+	TemplateIDs []string `json:"-"`
+
+	Groups []Hostgroup `json:"groups,omitempty"`
+	Macros []HostMacro `json:"macros,omitempty"`
+
+	// TemplatesLink are the Templates to link to the Templates.
+	TemplatesLink []Template `json:"templates_link,omitempty"`
+}
+
+// MarshalJSON encodes TemplateMassAddParams as expected by the Zabbix API.
+func (c TemplateMassAddParams) MarshalJSON() ([]byte, error) {
+	type params TemplateMassAddParams
+	return json.Marshal(struct {
+		params
+		Templates []map[string]string `json:"templates"`
+	}{params(c), objectIDs("templateid", c.TemplateIDs)})
+}
+
+// TemplateMassRemoveParams represent the parameters for a `template.massremove`
+// API call.
+//
+// See: https://www.zabbix.com/documentation/current/manual/api/reference/template/massremove
+type TemplateMassRemoveParams struct {
+	// TemplateIDs are the IDs of the Templates to update.
+	TemplateIDs []string `json:"templateids"`
+
+	GroupIDs []string `json:"groupids,omitempty"`
+
+	// Macros are the names of the user macros to delete from the Templates.
+	Macros []string `json:"macros,omitempty"`
+
+	// TemplateIDsLink are the IDs of the linked Templates to unlink from the
+	// Templates.
+	TemplateIDsLink []string `json:"templateids_link,omitempty"`
+
+	// TemplateIDsClear are the IDs of the linked Templates to unlink and clear
+	// from the Templates.
+	TemplateIDsClear []string `json:"templateids_clear,omitempty"`
+}
+
+// templateAction calls the given template write method and returns the
+// affected Template IDs.
+func (c *Session) templateAction(method string, params interface{}) ([]string, error) {
+	var body TemplateResponse
+
+	if err := c.Get(method, params, &body); err != nil {
+		return nil, err
+	}
+
+	if len(body.TemplateIDs) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return body.TemplateIDs, nil
+}
+
+// CreateTemplates creates a single or multiple new templates.
+// Returns a list of template id(s) of created template(s).
+//
+// Zabbix API docs: https://www.zabbix.com/documentation/current/manual/api/reference/template/create
+func (c *Session) CreateTemplates(templates ...TemplateCreateParams) (templateIDs []string, err error) {
+	return c.templateAction("template.create", templates)
+}
+
+// UpdateTemplates updates a single or multiple existing templates.
+// Returns a list of updated template id(s).
+//
+// Zabbix API docs: https://www.zabbix.com/documentation/current/manual/api/reference/template/update
+func (c *Session) UpdateTemplates(templates ...TemplateUpdateParams) (templateIDs []string, err error) {
+	return c.templateAction("template.update", templates)
+}
+
+// DeleteTemplates deletes a single or multiple templates.
+// Returns a list of deleted template id(s).
+//
+// Zabbix API docs: https://www.zabbix.com/documentation/current/manual/api/reference/template/delete
+func (c *Session) DeleteTemplates(templateIDs ...string) ([]string, error) {
+	return c.templateAction("template.delete", templateIDs)
+}
+
+// MassAddTemplates adds groups, macros and linked templates to multiple
+// templates.
+// Returns a list of updated template id(s).
+//
+// Zabbix API docs: https://www.zabbix.com/documentation/current/manual/api/reference/template/massadd
+func (c *Session) MassAddTemplates(params TemplateMassAddParams) (templateIDs []string, err error) {
+	return c.templateAction("template.massadd", params)
+}
+
+// MassRemoveTemplates removes groups, macros and linked templates from multiple
+// templates.
+// Returns a list of updated template id(s).
+//
+// Zabbix API docs: https://www.zabbix.com/documentation/current/manual/api/reference/template/massremove
+func (c *Session) MassRemoveTemplates(params TemplateMassRemoveParams) (templateIDs []string, err error) {
+	return c.templateAction("template.massremove", params)
+}
+
+// LinkTemplates links the given templates to the given hosts, in addition to
+// any templates that are already linked.
+// Returns a list of updated host id(s).
+func (c *Session) LinkTemplates(hostIDs []string, templateIDs ...string) ([]string, error) {
+	templates := make([]Template, len(templateIDs))
+	for i, id := range templateIDs {
+		templates[i] = Template{TemplateID: id}
+	}
+
+	return c.MassAddHosts(HostMassAddParams{
+		HostIDs:   hostIDs,
+		Templates: templates,
+	})
+}
+
+// UnlinkTemplates unlinks the given templates from the given hosts.
+//
+// If clear is true, all entities inherited from the templates (items,
+// triggers, graphs, etc.) are deleted from the hosts. Otherwise, the inherited
+// entities are kept on the hosts as unlinked copies.
+// Returns a list of updated host id(s).
+func (c *Session) UnlinkTemplates(hostIDs []string, clear bool, templateIDs ...string) ([]string, error) {
+	params := HostMassRemoveParams{HostIDs: hostIDs}
+	if clear {
+		params.TemplateIDsClear = templateIDs
+	} else {
+		params.TemplateIDs = templateIDs
+	}
+
+	return c.MassRemoveHosts(params)
+}
+
+// TemplateTree represents a Template and the tree of Templates it inherits
+// from.
+type TemplateTree struct {
+	// Template is the Template at this node of the tree.
+	Template Template
+
+	// Parents are the trees of the Templates linked to Template.
+	Parents []TemplateTree
+}
+
+// Templates returns the Template at the root of the tree and all of the
+// Templates it inherits from, in depth-first order. Templates inherited via
+// multiple paths are only returned once.
+func (c *TemplateTree) Templates() []Template {
+	seen := make(map[string]bool)
+	out := make([]Template, 0)
+
+	var walk func(tree *TemplateTree)
+	walk = func(tree *TemplateTree) {
+		if seen[tree.Template.TemplateID] {
+			return
+		}
+		seen[tree.Template.TemplateID] = true
+		out = append(out, tree.Template)
+
+		for i := range tree.Parents {
+			walk(&tree.Parents[i])
+		}
+	}
+	walk(c)
+
+	return out
+}
+
+// GetHostTemplateTree returns a tree for each Template linked directly to the
+// given host, including all Templates they inherit from.
+//
+// ErrNotFound is returned if the host does not exist.
+// An error is returned if a transport, parsing or API error occurs.
+func (c *Session) GetHostTemplateTree(hostID string) ([]TemplateTree, error) {
+	hosts, err := c.GetHosts(HostGetParams{
+		GetParameters: GetParameters{
+			OutputFields: SelectFields{"hostid"},
+		},
+		HostIDs:               []string{hostID},
+		SelectParentTemplates: SelectFields{"templateid"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// fetch each level of inherited templates until no new templates are found
+	templates := make(map[string]Template)
+	pending := make([]string, 0)
+	for _, template := range hosts[0].ParentTemplates {
+		pending = append(pending, template.TemplateID)
+	}
+
+	for len(pending) > 0 {
+		level, err := c.GetTemplates(TemplateGetParams{
+			TemplateIDs:           pending,
+			SelectParentTemplates: SelectFields{"templateid"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Error getting inherited Templates: %v", err)
+		}
+
+		pending = make([]string, 0)
+		for _, template := range level {
+			templates[template.TemplateID] = template
+		}
+
+		for _, template := range level {
+			for _, parent := range template.ParentTemplates {
+				if _, ok := templates[parent.TemplateID]; !ok {
+					pending = append(pending, parent.TemplateID)
+				}
+			}
+		}
+	}
+
+	var build func(id string, path map[string]bool) TemplateTree
+	build = func(id string, path map[string]bool) TemplateTree {
+		tree := TemplateTree{Template: templates[id]}
+		path[id] = true
+		for _, parent := range templates[id].ParentTemplates {
+			if path[parent.TemplateID] {
+				continue // guard against circular links
+			}
+			tree.Parents = append(tree.Parents, build(parent.TemplateID, path))
+		}
+		delete(path, id)
+
+		return tree
+	}
+
+	out := make([]TemplateTree, len(hosts[0].ParentTemplates))
+	for i, template := range hosts[0].ParentTemplates {
+		out[i] = build(template.TemplateID, make(map[string]bool))
+	}
+
+	return out, nil
+}
@@ -0,0 +1,240 @@
+package zabbix
+
+import (
+	"encoding/json"
+)
+
+// TemplateGroup represents a Zabbix Template Group returned from the Zabbix
+// API. Template groups were split from host groups in Zabbix 6.2.
+//
+// See: https://www.zabbix.com/documentation/6.2/manual/api/reference/templategroup/object
+type TemplateGroup struct {
+	// GroupID is the unique ID of the Template Group.
+	GroupID string `json:"groupid,omitempty"`
+
+	// Name is the name of the Template Group.
+	Name string `json:"name,omitempty"`
+
+	// UUID is the universal unique identifier of the Template Group.
+	UUID string `json:"uuid,omitempty"`
+
+	// Templates contains the Templates that belong to the Template Group.
+	//
+	// Templates is only populated if TemplateGroupGetParams.SelectTemplates is
+	// given in the query parameters that returned this Template Group.
+	Templates []Template `json:"-"`
+}
+
+// Hostgroup returns the TemplateGroup as a Hostgroup, as it would have been
+// returned by Zabbix servers older than v6.2.
+func (c *TemplateGroup) Hostgroup() Hostgroup {
+	return Hostgroup{
+		GroupID:   c.GroupID,
+		Name:      c.Name,
+		Templates: c.Templates,
+	}
+}
+
+// TemplateGroupGetParams represent the parameters for a `templategroup.get` API
+// call.
+//
+// See: https://www.zabbix.com/documentation/6.2/manual/api/reference/templategroup/get
+type TemplateGroupGetParams struct {
+	GetParameters
+
+	// Return only template groups with the given template group IDs
+	GroupIDs []string `json:"groupids,omitempty"`
+
+	// Return only template groups that contain the given templates
+	TemplateIDs []string `json:"templateids,omitempty"`
+
+	// Return only template groups that contain templates
+	WithTemplates bool `json:"with_templates,omitempty"`
+
+	// Return only template groups that contain templates with items
+	WithItems bool `json:"with_items,omitempty"`
+
+	// Return only template groups that contain templates with triggers
+	WithTriggers bool `json:"with_triggers,omitempty"`
+
+	// Return only template groups that contain templates with graphs
+	WithGraphs bool `json:"with_graphs,omitempty"`
+
+	// Return only template groups that contain templates with web checks
+	WithHttptests bool `json:"with_httptests,omitempty"`
+
+	// Return the templates that belong to the template group in the templates
+	// property
+	SelectTemplates SelectQuery `json:"selectTemplates,omitempty"`
+
+	// Limits the number of records returned by subselects
+	LimitSelects int `json:"limitSelects,omitempty"`
+}
+
+// GetTemplateGroups queries the Zabbix API for Template Groups matching the
+// given search parameters.
+//
+// ErrUnsupportedVersion is returned if the connected Zabbix API is older than
+// v6.2.
+// ErrNotFound is returned if the search result set is empty.
+// An error is returned if a transport, parsing or API error occurs.
+func (c *Session) GetTemplateGroups(params TemplateGroupGetParams) ([]TemplateGroup, error) {
+	if err := c.requireVersion(6, 2); err != nil {
+		return nil, err
+	}
+
+	templateGroups := make(jTemplateGroups, 0)
+	err := c.Get("templategroup.get", params, &templateGroups)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(templateGroups) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return templateGroups.TemplateGroups()
+}
+
+// CountTemplateGroups queries the Zabbix API for the number of Template Groups
+// matching the given search parameters.
+//
+// An error is returned if a transport, parsing or API error occurs.
+func (c *Session) CountTemplateGroups(params TemplateGroupGetParams) (int, error) {
+	if err := c.requireVersion(6, 2); err != nil {
+		return 0, err
+	}
+
+	params.CountOutput = true
+	return c.count("templategroup.get", params)
+}
+
+// TemplateGroupMassParams represent the parameters for the
+// `templategroup.massadd` and `templategroup.massupdate` API calls.
+//
+// For `templategroup.massupdate`, the given Templates replace the existing
+// members of each Template Group.
+type TemplateGroupMassParams struct {
+	// GroupIDs are the IDs of the Template Groups to update.
+	GroupIDs []string `json:"-"`
+
+	// TemplateIDs are the IDs of the Templates to add to the Template Groups.
+	TemplateIDs []string `json:"-"`
+}
+
+// MarshalJSON encodes TemplateGroupMassParams as expected by the Zabbix API.
+func (c TemplateGroupMassParams) MarshalJSON() ([]byte, error) {
+	templates := objectIDs("templateid", c.TemplateIDs)
+	if templates == nil {
+		templates = []map[string]string{}
+	}
+
+	return json.Marshal(struct {
+		Groups    []map[string]string `json:"groups"`
+		Templates []map[string]string `json:"templates"`
+	}{objectIDs("groupid", c.GroupIDs), templates})
+}
+
+// TemplateGroupMassRemoveParams represent the parameters for a
+// `templategroup.massremove` API call.
+type TemplateGroupMassRemoveParams struct {
+	// GroupIDs are the IDs of the Template Groups to update.
+	GroupIDs []string `json:"groupids"`
+
+	// TemplateIDs are the IDs of the Templates to remove from the Template
+	// Groups.
+	TemplateIDs []string `json:"templateids"`
+}
+
+// TemplateGroupPropagateParams represent the parameters for a
+// `templategroup.propagate` API call.
+type TemplateGroupPropagateParams struct {
+	// GroupIDs are the IDs of the Template Groups to propagate to their
+	// subgroups.
+	GroupIDs []string `json:"-"`
+
+	// Permissions causes the permissions of each Template Group to be applied
+	// to its subgroups.
+	Permissions bool `json:"permissions"`
+}
+
+// MarshalJSON encodes TemplateGroupPropagateParams as expected by the Zabbix
+// API.
+func (c TemplateGroupPropagateParams) MarshalJSON() ([]byte, error) {
+	type params TemplateGroupPropagateParams
+	return json.Marshal(struct {
+		params
+		Groups []map[string]string `json:"groups"`
+	}{params(c), objectIDs("groupid", c.GroupIDs)})
+}
+
+// templateGroupAction calls the given templategroup write method and returns
+// the affected Template Group IDs.
+//
+// ErrUnsupportedVersion is returned if the connected Zabbix API is older than
+// v6.2.
+func (c *Session) templateGroupAction(method string, params interface{}) ([]string, error) {
+	if err := c.requireVersion(6, 2); err != nil {
+		return nil, err
+	}
+
+	return c.hostgroupAction(method, params)
+}
+
+// CreateTemplateGroups creates a single or multiple new template groups. Only
+// the Name of each given TemplateGroup is required.
+// Returns a list of group id(s) of created template group(s).
+//
+// Zabbix API docs: https://www.zabbix.com/documentation/6.2/manual/api/reference/templategroup/create
+func (c *Session) CreateTemplateGroups(templateGroups ...TemplateGroup) (groupIDs []string, err error) {
+	return c.templateGroupAction("templategroup.create", templateGroups)
+}
+
+// UpdateTemplateGroups renames a single or multiple existing template groups.
+// Returns a list of updated group id(s).
+//
+// Zabbix API docs: https://www.zabbix.com/documentation/6.2/manual/api/reference/templategroup/update
+func (c *Session) UpdateTemplateGroups(templateGroups ...TemplateGroup) (groupIDs []string, err error) {
+	return c.templateGroupAction("templategroup.update", templateGroups)
+}
+
+// DeleteTemplateGroups deletes a single or multiple template groups.
+// Returns a list of deleted group id(s).
+//
+// Zabbix API docs: https://www.zabbix.com/documentation/6.2/manual/api/reference/templategroup/delete
+func (c *Session) DeleteTemplateGroups(groupIDs ...string) ([]string, error) {
+	return c.templateGroupAction("templategroup.delete", groupIDs)
+}
+
+// MassAddTemplateGroups adds templates to multiple template groups.
+// Returns a list of updated group id(s).
+//
+// Zabbix API docs: https://www.zabbix.com/documentation/6.2/manual/api/reference/templategroup/massadd
+func (c *Session) MassAddTemplateGroups(params TemplateGroupMassParams) (groupIDs []string, err error) {
+	return c.templateGroupAction("templategroup.massadd", params)
+}
+
+// MassUpdateTemplateGroups replaces the templates of multiple template groups.
+// Returns a list of updated group id(s).
+//
+// Zabbix API docs: https://www.zabbix.com/documentation/6.2/manual/api/reference/templategroup/massupdate
+func (c *Session) MassUpdateTemplateGroups(params TemplateGroupMassParams) (groupIDs []string, err error) {
+	return c.templateGroupAction("templategroup.massupdate", params)
+}
+
+// MassRemoveTemplateGroups removes templates from multiple template groups.
+// Returns a list of updated group id(s).
+//
+// Zabbix API docs: https://www.zabbix.com/documentation/6.2/manual/api/reference/templategroup/massremove
+func (c *Session) MassRemoveTemplateGroups(params TemplateGroupMassRemoveParams) (groupIDs []string, err error) {
+	return c.templateGroupAction("templategroup.massremove", params)
+}
+
+// PropagateTemplateGroups applies the permissions of the given template groups
+// to all of their subgroups.
+// Returns a list of updated group id(s).
+//
+// Zabbix API docs: https://www.zabbix.com/documentation/6.2/manual/api/reference/templategroup/propagate
+func (c *Session) PropagateTemplateGroups(params TemplateGroupPropagateParams) (groupIDs []string, err error) {
+	return c.templateGroupAction("templategroup.propagate", params)
+}
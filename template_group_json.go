@@ -0,0 +1,55 @@
+package zabbix
+
+import (
+	"fmt"
+)
+
+// jTemplateGroup is a private map for the Zabbix API Template Group object.
+// See: https://www.zabbix.com/documentation/6.2/manual/api/reference/templategroup/object
+type jTemplateGroup struct {
+	GroupID   string     `json:"groupid"`
+	Name      string     `json:"name"`
+	UUID      string     `json:"uuid"`
+	Templates jTemplates `json:"templates,omitempty"`
+}
+
+// TemplateGroup returns a native Go TemplateGroup struct mapped from the given
+// JSON Template Group data.
+func (c *jTemplateGroup) TemplateGroup() (*TemplateGroup, error) {
+	var err error
+
+	templateGroup := &TemplateGroup{}
+	templateGroup.GroupID = c.GroupID
+	templateGroup.Name = c.Name
+	templateGroup.UUID = c.UUID
+
+	templateGroup.Templates, err = c.Templates.Templates()
+	if err != nil {
+		return nil, err
+	}
+
+	return templateGroup, nil
+}
+
+// jTemplateGroups is a slice of jTemplateGroup structs.
+type jTemplateGroups []jTemplateGroup
+
+// TemplateGroups returns a native Go slice of TemplateGroups mapped from the
+// given JSON Template Groups data.
+func (c jTemplateGroups) TemplateGroups() ([]TemplateGroup, error) {
+	if c != nil {
+		templateGroups := make([]TemplateGroup, len(c))
+		for i, jtemplateGroup := range c {
+			templateGroup, err := jtemplateGroup.TemplateGroup()
+			if err != nil {
+				return nil, fmt.Errorf("Error unmarshalling Template Group %d in JSON data: %v", i, err)
+			}
+
+			templateGroups[i] = *templateGroup
+		}
+
+		return templateGroups, nil
+	}
+
+	return nil, nil
+}
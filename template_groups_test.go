@@ -0,0 +1,28 @@
+package zabbix
+
+import (
+	"testing"
+)
+
+func TestTemplateGroups(t *testing.T) {
+	session := GetTestSession(t)
+
+	if ok, err := session.VersionAtLeast(6, 2); err != nil || !ok {
+		t.Skip("Template Groups require Zabbix 6.2 or later")
+	}
+
+	templateGroups, err := session.GetTemplateGroups(TemplateGroupGetParams{
+		SelectTemplates: SelectExtendedOutput,
+	})
+	if err != nil {
+		t.Fatalf("Error getting Template Groups: %v", err)
+	}
+
+	for i, templateGroup := range templateGroups {
+		if templateGroup.GroupID == "" {
+			t.Fatalf("Template Group %d returned in response body has no Group ID", i)
+		}
+	}
+
+	t.Logf("Validated %d Template Groups", len(templateGroups))
+}
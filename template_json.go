@@ -0,0 +1,106 @@
+package zabbix
+
+import (
+	"fmt"
+)
+
+// jTemplate is a private map for the Zabbix API Template object.
+// See: https://www.zabbix.com/documentation/current/manual/api/reference/template/object
+type jTemplate struct {
+	TemplateID      string          `json:"templateid"`
+	Host            string          `json:"host"`
+	Name            string          `json:"name"`
+	Description     string          `json:"description"`
+	UUID            string          `json:"uuid"`
+	Groups          jHostgroups     `json:"groups"`
+	TemplateGroups  jTemplateGroups `json:"templategroups"`
+	ParentTemplates jTemplates      `json:"parentTemplates"`
+	Templates       jTemplates      `json:"templates"`
+	Hosts           jHosts          `json:"hosts"`
+	Items           jItems          `json:"items"`
+	Triggers        jTriggers       `json:"triggers"`
+	Macros          []HostMacro     `json:"macros"`
+	Tags            []HostTag       `json:"tags"`
+}
+
+// Template returns a native Go Template struct mapped from the given JSON
+// Template data.
+func (c *jTemplate) Template() (*Template, error) {
+	var err error
+
+	template := &Template{}
+	template.TemplateID = c.TemplateID
+	template.Hostname = c.Host
+	template.DisplayName = c.Name
+	template.Description = c.Description
+	template.UUID = c.UUID
+	template.Macros = c.Macros
+	template.Tags = c.Tags
+
+	template.Groups, err = c.Groups.Hostgroups()
+	if err != nil {
+		return nil, err
+	}
+
+	// map template groups returned since v6.2
+	if c.TemplateGroups != nil {
+		templateGroups, err := c.TemplateGroups.TemplateGroups()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, templateGroup := range templateGroups {
+			template.Groups = append(template.Groups, templateGroup.Hostgroup())
+		}
+	}
+
+	template.ParentTemplates, err = c.ParentTemplates.Templates()
+	if err != nil {
+		return nil, err
+	}
+
+	template.ChildTemplates, err = c.Templates.Templates()
+	if err != nil {
+		return nil, err
+	}
+
+	template.Hosts, err = c.Hosts.Hosts()
+	if err != nil {
+		return nil, err
+	}
+
+	template.Items, err = c.Items.Items()
+	if err != nil {
+		return nil, err
+	}
+
+	template.Triggers, err = c.Triggers.Triggers()
+	if err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+// jTemplates is a slice of jTemplate structs.
+type jTemplates []jTemplate
+
+// Templates returns a native Go slice of Templates mapped from the given JSON
+// Templates data.
+func (c jTemplates) Templates() ([]Template, error) {
+	if c != nil {
+		templates := make([]Template, len(c))
+		for i, jtemplate := range c {
+			template, err := jtemplate.Template()
+			if err != nil {
+				return nil, fmt.Errorf("Error unmarshalling Template %d in JSON data: %v", i, err)
+			}
+
+			templates[i] = *template
+		}
+
+		return templates, nil
+	}
+
+	return nil, nil
+}
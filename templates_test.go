@@ -0,0 +1,33 @@
+package zabbix
+
+import (
+	"testing"
+)
+
+func TestTemplates(t *testing.T) {
+	session := GetTestSession(t)
+
+	params := TemplateGetParams{
+		SelectParentTemplates: SelectExtendedOutput,
+		SelectItems:           SelectExtendedOutput,
+		SelectTriggers:        SelectExtendedOutput,
+		SelectMacros:          SelectExtendedOutput,
+	}
+
+	templates, err := session.GetTemplates(params)
+	if err != nil {
+		t.Fatalf("Error getting Templates: %v", err)
+	}
+
+	if len(templates) == 0 {
+		t.Fatal("No Templates found")
+	}
+
+	for i, template := range templates {
+		if template.TemplateID == "" {
+			t.Fatalf("Template %d returned in response body has no Template ID", i)
+		}
+	}
+
+	t.Logf("Validated %d Templates", len(templates))
+}
@@ -12,15 +12,18 @@ type UnixTimestamp struct {
 }
 
 func (t UnixTimestamp) MarshalJSON() ([]byte, error) {
-	stamp := fmt.Sprintf("\"%s\"", t.Unix())
+	stamp := fmt.Sprintf("\"%d\"", t.Unix())
 	return []byte(stamp), nil
 }
 
 func (t *UnixTimestamp) UnmarshalJSON(data []byte) (err error) {
-	var unixString string
-	err = json.Unmarshal(data, &unixString)
-	if err != nil {
-		return
+	// the timestamp may be given as a JSON string or number
+	unixString := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		err = json.Unmarshal(data, &unixString)
+		if err != nil {
+			return
+		}
 	}
 
 	unix, err := strconv.ParseInt(unixString, 10, 64)
@@ -31,3 +34,34 @@ func (t *UnixTimestamp) UnmarshalJSON(data []byte) (err error) {
 
 	return
 }
+
+// timeSuffixes maps the time suffixes supported by Zabbix to their duration.
+var timeSuffixes = map[byte]time.Duration{
+	's': time.Second,
+	'm': time.Minute,
+	'h': time.Hour,
+	'd': 24 * time.Hour,
+	'w': 7 * 24 * time.Hour,
+}
+
+// parseTimeSuffix parses a Zabbix time period with an optional time suffix
+// (e.g. "30", "30s", "5m", "1h", "1d" or "1w"). Periods without a suffix are
+// in seconds.
+func parseTimeSuffix(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, fmt.Errorf("Error parsing time period: empty value")
+	}
+
+	unit := time.Second
+	if d, ok := timeSuffixes[s[len(s)-1]]; ok {
+		unit = d
+		s = s[:len(s)-1]
+	}
+
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("Error parsing time period: %v", err)
+	}
+
+	return time.Duration(n) * unit, nil
+}
@@ -0,0 +1,148 @@
+package zabbix
+
+import (
+	"fmt"
+	"time"
+)
+
+// Trend represents a Zabbix Trend returned from the Zabbix API. A Trend is the
+// hourly aggregate of the numeric History of an Item.
+//
+// Trends of Items with value type ItemValueTypeFloat and
+// ItemValueTypeUnsigned are stored in separate tables but are returned
+// together by the API. Values of both are mapped to float64, so unsigned
+// values above 2^53 lose precision.
+//
+// See: https://www.zabbix.com/documentation/5.0/manual/api/reference/trend/object
+type Trend struct {
+	// ItemID is the ID of the related item.
+	ItemID int
+
+	// Clock is the start of the hour which the Trend aggregates.
+	Clock int
+
+	// Num is the number of values that were received during the hour.
+	Num int
+
+	// ValueMin is the minimum value received during the hour.
+	ValueMin float64
+
+	// ValueAvg is the average value received during the hour.
+	ValueAvg float64
+
+	// ValueMax is the maximum value received during the hour.
+	ValueMax float64
+}
+
+// Time returns the start of the hour which the Trend aggregates.
+func (c *Trend) Time() time.Time {
+	return time.Unix(int64(c.Clock), 0)
+}
+
+// TrendGetParams is query params for trend.get call
+type TrendGetParams struct {
+	GetParameters
+
+	// ItemIDs filters search results to trends of the given Item ID's.
+	ItemIDs []string `json:"itemids,omitempty"`
+
+	// Return only values that have been received after or at the given time.
+	TimeFrom float64 `json:"time_from,omitempty"`
+
+	// Return only values that have been received before or at the given time.
+	TimeTill float64 `json:"time_till,omitempty"`
+}
+
+// GetTrends queries the Zabbix API for Trends matching the given search
+// parameters.
+//
+// ErrNotFound is returned if the search result set is empty.
+// An error is returned if a transport, parsing or API error occurs.
+func (c *Session) GetTrends(params TrendGetParams) ([]Trend, error) {
+	trends := make(jTrends, 0)
+	err := c.Get("trend.get", params, &trends)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(trends) == 0 {
+		return nil, ErrNotFound
+	}
+
+	// map JSON Trends to Go Trends
+	out := make([]Trend, len(trends))
+	for i, jtrend := range trends {
+		trend, err := jtrend.Trend()
+		if err != nil {
+			return nil, fmt.Errorf("Error mapping Trend %d in response: %v", i, err)
+		}
+
+		out[i] = *trend
+	}
+
+	return out, nil
+}
+
+// CountTrends queries the Zabbix API for the number of Trends matching the
+// given search parameters.
+//
+// An error is returned if a transport, parsing or API error occurs.
+func (c *Session) CountTrends(params TrendGetParams) (int, error) {
+	params.CountOutput = true
+	return c.count("trend.get", params)
+}
+
+// StreamTrends queries the Zabbix API for Trends matching the given search
+// parameters and calls fn for each Trend, so that long time ranges may be
+// processed without holding all Trends in memory.
+//
+// The API does not sort Trends, so the time range from TimeFrom to TimeTill is
+// queried in consecutive windows of the given duration, which must be at least
+// one hour. Trends are in order of time between windows but not within a
+// window. TimeFrom is required. If TimeTill is zero, the current time is used.
+// ResultLimit is ignored.
+//
+// If fn returns an error, streaming stops and the error is returned.
+// An error is returned if TimeFrom is zero, or if a transport, parsing or API
+// error occurs.
+func (c *Session) StreamTrends(params TrendGetParams, window time.Duration, fn func(Trend) error) error {
+	if window < time.Hour {
+		return fmt.Errorf("Trend window must be at least one hour")
+	}
+
+	if params.TimeFrom <= 0 {
+		return fmt.Errorf("TimeFrom is required to stream Trends")
+	}
+
+	till := int64(params.TimeTill)
+	if till == 0 {
+		till = time.Now().Unix()
+	}
+
+	step := int64(window / time.Second)
+	params.ResultLimit = 0
+	for from := int64(params.TimeFrom); from <= till; from += step {
+		p := params
+		p.TimeFrom = float64(from)
+		p.TimeTill = float64(from + step - 1)
+		if from+step-1 > till {
+			p.TimeTill = float64(till)
+		}
+
+		trends, err := c.GetTrends(p)
+		if err == ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, trend := range trends {
+			if err := fn(trend); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package zabbix
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// jTrend is a private map for the Zabbix API Trend object.
+// See: https://www.zabbix.com/documentation/5.0/manual/api/reference/trend/object
+type jTrend struct {
+	ItemID   string `json:"itemid"`
+	Clock    string `json:"clock"`
+	Num      string `json:"num"`
+	ValueMin string `json:"value_min"`
+	ValueAvg string `json:"value_avg"`
+	ValueMax string `json:"value_max"`
+}
+
+// Trend returns a native Go Trend struct mapped from the given JSON Trend data.
+func (c *jTrend) Trend() (*Trend, error) {
+	var err error
+	trend := &Trend{}
+
+	trend.ItemID, err = strconv.Atoi(c.ItemID)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing Trend ItemID: %v", err)
+	}
+
+	trend.Clock, err = strconv.Atoi(c.Clock)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing Trend Clock: %v", err)
+	}
+
+	trend.Num, err = atoi(c.Num)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing Trend Num: %v", err)
+	}
+
+	// values of both the float and unsigned trend tables parse as floats
+	for _, v := range []struct {
+		Name  string
+		Value string
+		Out   *float64
+	}{
+		{"ValueMin", c.ValueMin, &trend.ValueMin},
+		{"ValueAvg", c.ValueAvg, &trend.ValueAvg},
+		{"ValueMax", c.ValueMax, &trend.ValueMax},
+	} {
+		if v.Value == "" {
+			continue
+		}
+
+		*v.Out, err = strconv.ParseFloat(v.Value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing Trend %s: %v", v.Name, err)
+		}
+	}
+
+	return trend, nil
+}
+
+// jTrends is a slice of jTrend structs.
+type jTrends []jTrend
+
+// Trends returns a native Go slice of Trends mapped from the given JSON Trends
+// data.
+func (c jTrends) Trends() ([]Trend, error) {
+	if c != nil {
+		trends := make([]Trend, len(c))
+		for i, jtrend := range c {
+			trend, err := jtrend.Trend()
+			if err != nil {
+				return nil, fmt.Errorf("Error unmarshalling Trend %d in JSON data: %v", i, err)
+			}
+			trends[i] = *trend
+		}
+
+		return trends, nil
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,65 @@
+package zabbix
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestTrendMapping(t *testing.T) {
+	trends, err := jTrends{
+		{ItemID: "1", Clock: "3600", Num: "60", ValueMin: "0.5", ValueAvg: "1.25", ValueMax: "2"},
+		{ItemID: "2", Clock: "3600", Num: "60", ValueMin: "10", ValueAvg: "15", ValueMax: "20"},
+	}.Trends()
+	if err != nil {
+		t.Fatalf("Error mapping Trends: %v", err)
+	}
+
+	if trends[0].ValueAvg != 1.25 || trends[1].ValueMax != 20 || trends[1].Num != 60 {
+		t.Errorf("Unexpected Trend values: %+v", trends)
+	}
+}
+
+func TestStreamTrendsValidation(t *testing.T) {
+	session := &Session{}
+	fn := func(Trend) error { return nil }
+
+	if err := session.StreamTrends(TrendGetParams{TimeFrom: 3600}, time.Minute, fn); err == nil {
+		t.Error("Expected an error for a window shorter than one hour")
+	}
+
+	if err := session.StreamTrends(TrendGetParams{}, time.Hour, fn); err == nil {
+		t.Error("Expected an error for a zero TimeFrom")
+	}
+}
+
+func TestTrends(t *testing.T) {
+	session := GetTestSession(t)
+
+	items, err := session.GetItems(ItemGetParams{
+		GetParameters: GetParameters{
+			Filter: map[string]interface{}{"value_type": []int{ItemValueTypeFloat, ItemValueTypeUnsigned}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Error getting Items: %v", err)
+	}
+
+	params := TrendGetParams{ItemIDs: []string{strconv.Itoa(items[0].ItemID)}}
+	params.ResultLimit = 10
+	trends, err := session.GetTrends(params)
+	if err == ErrNotFound {
+		t.Skip("No Trends found")
+	}
+	if err != nil {
+		t.Fatalf("Error getting Trends: %v", err)
+	}
+
+	for i, trend := range trends {
+		if trend.ValueMin > trend.ValueMax {
+			t.Errorf("Trend %d minimum is greater than its maximum", i)
+		}
+	}
+
+	t.Logf("Validated %d Trends", len(trends))
+}
@@ -40,6 +40,57 @@ const (
 	TriggerSeverityDisaster
 )
 
+const (
+	// TriggerStatusEnabled means the trigger is enabled
+	TriggerStatusEnabled = iota
+
+	// TriggerStatusDisabled means the trigger is disabled
+	TriggerStatusDisabled
+)
+
+const (
+	// TriggerTypeSingle means the trigger generates a single problem event
+	TriggerTypeSingle = iota
+
+	// TriggerTypeMultiple means the trigger generates a problem event each
+	// time it is evaluated in problem state
+	TriggerTypeMultiple
+)
+
+const (
+	// TriggerRecoveryModeExpression means problems are resolved when the
+	// trigger expression evaluates to false
+	TriggerRecoveryModeExpression = iota
+
+	// TriggerRecoveryModeRecoveryExpression means problems are resolved when
+	// the recovery expression evaluates to true
+	TriggerRecoveryModeRecoveryExpression
+
+	// TriggerRecoveryModeNone means problems are only resolved manually or by
+	// event correlation
+	TriggerRecoveryModeNone
+)
+
+const (
+	// TriggerCorrelationModeAll means all problems of the trigger are resolved
+	// by an OK event
+	TriggerCorrelationModeAll = iota
+
+	// TriggerCorrelationModeTag means only problems with a matching
+	// correlation tag value are resolved by an OK event
+	TriggerCorrelationModeTag
+)
+
+const (
+	// TriggerManualCloseNo means problems of the trigger can not be closed
+	// manually
+	TriggerManualCloseNo = iota
+
+	// TriggerManualCloseYes means problems of the trigger can be closed
+	// manually
+	TriggerManualCloseYes
+)
+
 // Trigger represents a Zabbix Trigger returned from the Zabbix API.
 //
 // See: https://www.zabbix.com/documentation/3.4/manual/config/triggers
@@ -99,12 +150,92 @@ type Trigger struct {
 
 	// URL is a link to the trigger graph in Zabbix
 	URL string
+
+	// Comments are additional description of the trigger.
+	Comments string
+
+	// Type is whether the trigger generates multiple problem events.
+	//
+	// Type must be one of the TriggerType constants.
+	Type int
+
+	// RecoveryMode is how problems of the trigger are resolved.
+	//
+	// RecoveryMode must be one of the TriggerRecoveryMode constants.
+	RecoveryMode int
+
+	// RecoveryExpression is the recovery expression of the trigger.
+	RecoveryExpression string
+
+	// CorrelationMode is which problems are resolved by an OK event.
+	//
+	// CorrelationMode must be one of the TriggerCorrelationMode constants.
+	CorrelationMode int
+
+	// CorrelationTag is the tag used for matching problems of the trigger
+	// with TriggerCorrelationModeTag.
+	CorrelationTag string
+
+	// ManualClose shows whether problems of the trigger can be closed
+	// manually.
+	ManualClose bool
+
+	// OpData is the operational data of the trigger.
+	OpData string
+
+	// EventName is the problem event name of the trigger.
+	EventName string
+
+	// TemplateID is the ID of the parent template trigger if the trigger was
+	// inherited from a template.
+	TemplateID string
+
+	// Dependencies is an array of Triggers that the trigger depends on.
+	//
+	// Dependencies is only populated if TriggerGetParams.SelectDependencies is
+	// given in the query parameters that returned this Trigger.
+	Dependencies []Trigger
+
+	// Functions is an array of the functions used in the trigger expression.
+	//
+	// Functions is only populated if TriggerGetParams.SelectFunctions is given
+	// in the query parameters that returned this Trigger.
+	Functions []TriggerFunction
+
+	// Items is an array of Items used in the trigger expression.
+	//
+	// Items is only populated if TriggerGetParams.SelectItems is given in the
+	// query parameters that returned this Trigger.
+	Items []Item
 }
 
 // TriggerTag is trigger tag
 type TriggerTag struct {
-	Name  string
-	Value string
+	Name  string `json:"tag"`
+	Value string `json:"value"`
+}
+
+// TriggerFunction is a function used in a trigger expression. Functions are
+// referenced in the expression returned by the Zabbix API by their ID, as
+// in {12345}.
+type TriggerFunction struct {
+	// FunctionID is the ID of the function.
+	FunctionID string `json:"functionid"`
+
+	// ItemID is the ID of the Item used by the function.
+	ItemID string `json:"itemid"`
+
+	// Function is the name of the function, such as "last" or "avg".
+	Function string `json:"function"`
+
+	// Parameter is the parameters of the function. Since Zabbix 5.4, the first
+	// parameter is a placeholder for the Item, "$".
+	Parameter string `json:"parameter"`
+}
+
+// TriggerDependency identifies a Trigger that another Trigger depends on.
+type TriggerDependency struct {
+	TriggerID string `json:"triggerid"`
 }
 
 // TriggerGetParams is params for trigger.get query
@@ -177,10 +308,16 @@ type TriggerGetParams struct {
 	// Trigger to be attached in the search results.
 	SelectHosts SelectQuery `json:"selectHosts,omitempty"`
 
+	// SelectItems causes the Items used in each Trigger expression to be
+	// attached in the search results.
 	SelectItems SelectQuery `json:"selectItems,omitempty"`
 
+	// SelectFunctions causes the functions used in each Trigger expression to
+	// be attached in the search results.
 	SelectFunctions SelectQuery `json:"selectFunctions,omitempty"`
 
+	// SelectDependencies causes the Triggers that each Trigger depends on to
+	// be attached in the search results.
 	SelectDependencies SelectQuery `json:"selectDependencies,omitempty"`
 
 	SelectDiscoveryRule SelectQuery `json:"selectDiscoveryRule,omitempty"`
@@ -188,6 +325,10 @@ type TriggerGetParams struct {
 	SelectLastEvent SelectQuery `json:"selectLastEvent,omitempty"`
 
 	SelectTags SelectQuery `json:"selectTags,omitempty"`
+
+	// GroupCount causes a CountOutput query to return the number of Triggers
+	// grouped by the values of the fields given in OutputFields.
+	GroupCount bool `json:"groupCount,omitempty"`
 }
 
 // GetTriggers queries the Zabbix API for Triggers matching the given search
@@ -219,3 +360,184 @@ func (c *Session) GetTriggers(params TriggerGetParams) ([]Trigger, error) {
 
 	return out, nil
 }
+
+// CountTriggers queries the Zabbix API for the number of Triggers matching the
+// given search parameters.
+//
+// An error is returned if a transport, parsing or API error occurs.
+func (c *Session) CountTriggers(params TriggerGetParams) (int, error) {
+	params.CountOutput = true
+	params.GroupCount = false
+	return c.count("trigger.get", params)
+}
+
+// CountTriggersBy queries the Zabbix API for the number of Triggers matching
+// the given search parameters, grouped by the values of the given fields.
+//
+// An error is returned if a transport, parsing or API error occurs.
+func (c *Session) CountTriggersBy(params TriggerGetParams, fields ...string) ([]GroupCount, error) {
+	params.CountOutput = true
+	params.GroupCount = true
+	params.OutputFields = SelectFields(fields)
+	return c.groupCount("trigger.get", params)
+}
+
+// CountTriggersBySeverity queries the Zabbix API for the number of Triggers
+// matching the given search parameters for each severity. The returned map is
+// keyed by the TriggerSeverity constants.
+//
+// An error is returned if a transport, parsing or API error occurs.
+func (c *Session) CountTriggersBySeverity(params TriggerGetParams) (map[int]int, error) {
+	groups, err := c.CountTriggersBy(params, "priority")
+	if err != nil {
+		return nil, err
+	}
+
+	return countBySeverity(groups, "priority")
+}
+
+// TriggerResponse represent trigger action response body
+type TriggerResponse struct {
+	TriggerIDs []string `json:"triggerids"`
+}
+
+// TriggerCreateParams represent the parameters for a `trigger.create` API
+// call.
+//
+// See: https://www.zabbix.com/documentation/current/manual/api/reference/trigger/create
+type TriggerCreateParams struct {
+	// Description is the name of the trigger.
+	Description string `json:"description"`
+
+	// Expression is the trigger expression.
+	Expression string `json:"expression"`
+
+	// Priority is the severity of the trigger and must be one of the
+	// TriggerSeverity constants.
+	Priority int `json:"priority"`
+
+	// Status must be one of the TriggerStatus constants.
+	Status int `json:"status"`
+
+	// Type must be one of the TriggerType constants.
+	Type int `json:"type"`
+
+	// RecoveryMode must be one of the TriggerRecoveryMode constants.
+	RecoveryMode int `json:"recovery_mode"`
+
+	// RecoveryExpression is required with
+	// TriggerRecoveryModeRecoveryExpression.
+	RecoveryExpression string `json:"recovery_expression,omitempty"`
+
+	// CorrelationMode must be one of the TriggerCorrelationMode constants.
+	CorrelationMode int `json:"correlation_mode"`
+
+	// CorrelationTag is required with TriggerCorrelationModeTag.
+	CorrelationTag string `json:"correlation_tag,omitempty"`
+
+	// ManualClose must be one of the TriggerManualClose constants.
+	ManualClose int `json:"manual_close"`
+
+	URL       string `json:"url,omitempty"`
+	Comments  string `json:"comments,omitempty"`
+	OpData    string `json:"opdata,omitempty"`
+	EventName string `json:"event_name,omitempty"`
+
+	Tags         []TriggerTag        `json:"tags,omitempty"`
+	Dependencies []TriggerDependency `json:"dependencies,omitempty"`
+}
+
+// TriggerUpdateParams represent the parameters for a `trigger.update` API
+// call.
+//
+// Only the fields that are set are updated. Tags and Dependencies replace the
+// existing values of the Trigger when given.
+//
+// See: https://www.zabbix.com/documentation/current/manual/api/reference/trigger/update
+type TriggerUpdateParams struct {
+	// TriggerID is the ID of the Trigger to update.
+	TriggerID string `json:"triggerid"`
+
+	Description        string  `json:"description,omitempty"`
+	Expression         string  `json:"expression,omitempty"`
+	Priority           *int    `json:"priority,omitempty"`
+	Status             *int    `json:"status,omitempty"`
+	Type               *int    `json:"type,omitempty"`
+	RecoveryMode       *int    `json:"recovery_mode,omitempty"`
+	RecoveryExpression *string `json:"recovery_expression,omitempty"`
+	CorrelationMode    *int    `json:"correlation_mode,omitempty"`
+	CorrelationTag     *string `json:"correlation_tag,omitempty"`
+	ManualClose        *int    `json:"manual_close,omitempty"`
+	URL                *string `json:"url,omitempty"`
+	Comments           *string `json:"comments,omitempty"`
+	OpData             *string `json:"opdata,omitempty"`
+	EventName          *string `json:"event_name,omitempty"`
+
+	Tags         []TriggerTag        `json:"tags,omitempty"`
+	Dependencies []TriggerDependency `json:"dependencies,omitempty"`
+}
+
+// triggerAction calls the given trigger write method and returns the affected
+// Trigger IDs.
+func (c *Session) triggerAction(method string, params interface{}) ([]string, error) {
+	var body TriggerResponse
+
+	if err := c.Get(method, params, &body); err != nil {
+		return nil, err
+	}
+
+	if len(body.TriggerIDs) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return body.TriggerIDs, nil
+}
+
+// CreateTriggers creates a single or multiple new triggers.
+// Returns a list of trigger id(s) of created trigger(s).
+//
+// Zabbix API docs: https://www.zabbix.com/documentation/current/manual/api/reference/trigger/create
+func (c *Session) CreateTriggers(triggers ...TriggerCreateParams) (triggerIDs []string, err error) {
+	return c.triggerAction("trigger.create", triggers)
+}
+
+// UpdateTriggers updates a single or multiple existing triggers.
+// Returns a list of updated trigger id(s).
+//
+// Zabbix API docs: https://www.zabbix.com/documentation/current/manual/api/reference/trigger/update
+func (c *Session) UpdateTriggers(triggers ...TriggerUpdateParams) (triggerIDs []string, err error) {
+	return c.triggerAction("trigger.update", triggers)
+}
+
+// DeleteTriggers deletes a single or multiple triggers.
+// Returns a list of deleted trigger id(s).
+//
+// Zabbix API docs: https://www.zabbix.com/documentation/current/manual/api/reference/trigger/delete
+func (c *Session) DeleteTriggers(triggerIDs ...string) ([]string, error) {
+	return c.triggerAction("trigger.delete", triggerIDs)
+}
+
+// AddTriggerDependencies makes the given trigger depend on each of the given
+// triggers, in addition to its existing dependencies.
+// Returns a list of updated trigger id(s).
+//
+// Zabbix API docs: https://www.zabbix.com/documentation/current/manual/api/reference/trigger/adddependencies
+func (c *Session) AddTriggerDependencies(triggerID string, dependsOnTriggerIDs ...string) ([]string, error) {
+	params := make([]map[string]string, len(dependsOnTriggerIDs))
+	for i, id := range dependsOnTriggerIDs {
+		params[i] = map[string]string{
+			"triggerid":          triggerID,
+			"dependsOnTriggerid": id,
+		}
+	}
+
+	return c.triggerAction("trigger.adddependencies", params)
+}
+
+// DeleteTriggerDependencies removes all dependencies of the given triggers.
+// Returns a list of updated trigger id(s).
+//
+// Zabbix API docs: https://www.zabbix.com/documentation/current/manual/api/reference/trigger/deletedependencies
+func (c *Session) DeleteTriggerDependencies(triggerIDs ...string) ([]string, error) {
+	return c.triggerAction("trigger.deletedependencies", objectIDs("triggerid", triggerIDs))
+}
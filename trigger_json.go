@@ -18,6 +18,20 @@ type jTrigger struct {
 	Tags        jTriggerTags `json:"tags"`
 	LastEvent   *jEvent      `json:"lastEvent"`
 	URL         string       `json:"url"`
+
+	Comments           string            `json:"comments"`
+	Type               string            `json:"type"`
+	RecoveryMode       string            `json:"recovery_mode"`
+	RecoveryExpression string            `json:"recovery_expression"`
+	CorrelationMode    string            `json:"correlation_mode"`
+	CorrelationTag     string            `json:"correlation_tag"`
+	ManualClose        string            `json:"manual_close"`
+	OpData             string            `json:"opdata"`
+	EventName          string            `json:"event_name"`
+	TemplateID         string            `json:"templateid"`
+	Dependencies       jTriggers         `json:"dependencies"`
+	Functions          []TriggerFunction `json:"functions"`
+	Items              jItems            `json:"items"`
 }
 
 type jTriggerTag struct {
@@ -62,6 +76,32 @@ func (c *jTrigger) Trigger() (*Trigger, error) {
 	trigger.Description = c.Description
 	trigger.Expression = c.Expression
 	trigger.URL = c.URL
+	trigger.Comments = c.Comments
+	trigger.RecoveryExpression = c.RecoveryExpression
+	trigger.CorrelationTag = c.CorrelationTag
+	trigger.ManualClose = (c.ManualClose == "1")
+	trigger.OpData = c.OpData
+	trigger.EventName = c.EventName
+	trigger.Functions = c.Functions
+
+	if c.TemplateID != "0" {
+		trigger.TemplateID = c.TemplateID
+	}
+
+	trigger.Type, err = atoi(c.Type)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing Trigger Type: %v", err)
+	}
+
+	trigger.RecoveryMode, err = atoi(c.RecoveryMode)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing Trigger RecoveryMode: %v", err)
+	}
+
+	trigger.CorrelationMode, err = atoi(c.CorrelationMode)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing Trigger CorrelationMode: %v", err)
+	}
 
 	if c.LastEvent != nil {
 		trigger.LastEvent, err = c.LastEvent.Event()
@@ -92,5 +132,40 @@ func (c *jTrigger) Trigger() (*Trigger, error) {
 		return nil, err
 	}
 
+	// map dependencies
+	trigger.Dependencies, err = c.Dependencies.Triggers()
+	if err != nil {
+		return nil, err
+	}
+
+	// map items
+	trigger.Items, err = c.Items.Items()
+	if err != nil {
+		return nil, err
+	}
+
 	return trigger, nil
 }
+
+// jTriggers is a slice of jTrigger structs.
+type jTriggers []jTrigger
+
+// Triggers returns a native Go slice of Triggers mapped from the given JSON
+// Triggers data.
+func (c jTriggers) Triggers() ([]Trigger, error) {
+	if c != nil {
+		triggers := make([]Trigger, len(c))
+		for i, jtrigger := range c {
+			trigger, err := jtrigger.Trigger()
+			if err != nil {
+				return nil, fmt.Errorf("Error unmarshalling Trigger %d in JSON data: %v", i, err)
+			}
+
+			triggers[i] = *trigger
+		}
+
+		return triggers, nil
+	}
+
+	return nil, nil
+}
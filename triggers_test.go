@@ -0,0 +1,102 @@
+package zabbix
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTriggerMapping(t *testing.T) {
+	data := `{
+		"triggerid": "100",
+		"description": "High load",
+		"expression": "{200}>5",
+		"priority": "3",
+		"recovery_mode": "1",
+		"recovery_expression": "{201}<2",
+		"manual_close": "1",
+		"functions": [{"functionid": "200", "itemid": "300", "function": "last", "parameter": "$"}],
+		"dependencies": [{"triggerid": "101", "description": "Host unreachable"}],
+		"items": [{"itemid": "300", "key_": "system.cpu.load"}]
+	}`
+
+	var jtrigger jTrigger
+	if err := json.Unmarshal([]byte(data), &jtrigger); err != nil {
+		t.Fatalf("Error unmarshalling Trigger: %v", err)
+	}
+
+	trigger, err := jtrigger.Trigger()
+	if err != nil {
+		t.Fatalf("Error mapping Trigger: %v", err)
+	}
+
+	if trigger.RecoveryMode != TriggerRecoveryModeRecoveryExpression || !trigger.ManualClose {
+		t.Errorf("Unexpected recovery settings: %+v", trigger)
+	}
+
+	if len(trigger.Functions) != 1 || trigger.Functions[0].ItemID != "300" {
+		t.Errorf("Unexpected Trigger functions: %+v", trigger.Functions)
+	}
+
+	if len(trigger.Dependencies) != 1 || trigger.Dependencies[0].TriggerID != "101" {
+		t.Errorf("Unexpected Trigger dependencies: %+v", trigger.Dependencies)
+	}
+
+	if len(trigger.Items) != 1 || trigger.Items[0].Key != "system.cpu.load" {
+		t.Errorf("Unexpected Trigger items: %+v", trigger.Items)
+	}
+}
+
+func TestTriggerCRUD(t *testing.T) {
+	session := GetTestSession(t)
+
+	hosts, err := session.GetHosts(HostGetParams{})
+	if err != nil {
+		t.Fatalf("Error getting Hosts: %v", err)
+	}
+
+	host := hosts[0].Hostname
+	triggerIDs, err := session.CreateTriggers(
+		TriggerCreateParams{
+			Description: "go-zabbix test dependency",
+			Expression:  "last(/" + host + "/agent.ping)=0",
+			Priority:    TriggerSeverityHigh,
+		},
+		TriggerCreateParams{
+			Description: "go-zabbix test trigger",
+			Expression:  "last(/" + host + "/agent.ping)=0",
+			Priority:    TriggerSeverityWarning,
+			ManualClose: TriggerManualCloseYes,
+			Tags:        []TriggerTag{{Name: "test", Value: "go-zabbix"}},
+		},
+	)
+	if err != nil {
+		t.Fatalf("Error creating Triggers: %v", err)
+	}
+	defer session.DeleteTriggers(triggerIDs...)
+
+	if _, err := session.AddTriggerDependencies(triggerIDs[1], triggerIDs[0]); err != nil {
+		t.Fatalf("Error adding Trigger dependency: %v", err)
+	}
+
+	triggers, err := session.GetTriggers(TriggerGetParams{
+		TriggerIDs:         triggerIDs[1:],
+		SelectDependencies: SelectExtendedOutput,
+		SelectFunctions:    SelectExtendedOutput,
+	})
+	if err != nil {
+		t.Fatalf("Error getting Trigger: %v", err)
+	}
+
+	if len(triggers[0].Dependencies) != 1 || triggers[0].Dependencies[0].TriggerID != triggerIDs[0] {
+		t.Errorf("Expected Trigger to depend on Trigger %s", triggerIDs[0])
+	}
+
+	if _, err := session.DeleteTriggerDependencies(triggerIDs[1]); err != nil {
+		t.Errorf("Error deleting Trigger dependencies: %v", err)
+	}
+
+	priority := TriggerSeverityAverage
+	if _, err := session.UpdateTriggers(TriggerUpdateParams{TriggerID: triggerIDs[1], Priority: &priority}); err != nil {
+		t.Errorf("Error updating Trigger: %v", err)
+	}
+}
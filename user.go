@@ -0,0 +1,19 @@
+package zabbix
+
+// User represents a Zabbix User as attached to other objects returned from the
+// Zabbix API.
+//
+// See: https://www.zabbix.com/documentation/current/manual/api/reference/user/object
+type User struct {
+	// UserID is the unique ID of the User.
+	UserID string
+
+	// Username is the login name of the User.
+	Username string
+
+	// Name is the first name of the User.
+	Name string
+
+	// Surname is the last name of the User.
+	Surname string
+}
@@ -0,0 +1,46 @@
+package zabbix
+
+// jUser is a private map for the Zabbix API User object.
+// See: https://www.zabbix.com/documentation/current/manual/api/reference/user/object
+type jUser struct {
+	UserID   string `json:"userid"`
+	Username string `json:"username"`
+	Alias    string `json:"alias"`
+	Name     string `json:"name"`
+	Surname  string `json:"surname"`
+}
+
+// User returns a native Go User struct mapped from the given JSON User data.
+func (c *jUser) User() *User {
+	user := &User{
+		UserID:   c.UserID,
+		Username: c.Username,
+		Name:     c.Name,
+		Surname:  c.Surname,
+	}
+
+	// the username is named alias before Zabbix 5.4
+	if user.Username == "" {
+		user.Username = c.Alias
+	}
+
+	return user
+}
+
+// jUsers is a slice of jUser structs.
+type jUsers []jUser
+
+// Users returns a native Go slice of Users mapped from the given JSON Users
+// data.
+func (c jUsers) Users() []User {
+	if c == nil {
+		return nil
+	}
+
+	out := make([]User, len(c))
+	for i, juser := range c {
+		out[i] = *juser.User()
+	}
+
+	return out
+}
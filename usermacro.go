@@ -102,3 +102,12 @@ func (c *Session) UpdateUserMacros(macros ...HostMacro) (hostMacroIds []string,
 
 	return body.HostMacroIDs, nil
 }
+
+// CountUserMacros queries the Zabbix API for the number of user macros
+// matching the given search parameters.
+//
+// An error is returned if a transport, parsing or API error occurs.
+func (c *Session) CountUserMacros(params UserMacroGetParams) (int, error) {
+	params.CountOutput = true
+	return c.count("usermacro.get", params)
+}
@@ -0,0 +1,70 @@
+package zabbix
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrUnsupportedVersion describes an API call that is not supported by the
+// version of the connected Zabbix API.
+var ErrUnsupportedVersion = errors.New("The method is not supported by the connected Zabbix API version")
+
+// parseVersion returns the major and minor version numbers of the given
+// Zabbix API version string (e.g. "6.2.1").
+func parseVersion(version string) (major, minor int, err error) {
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) < 2 {
+		return 0, 0, fmt.Errorf("Error parsing Zabbix API version: %q", version)
+	}
+
+	major, err = strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("Error parsing Zabbix API major version: %v", err)
+	}
+
+	minor, err = strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("Error parsing Zabbix API minor version: %v", err)
+	}
+
+	return major, minor, nil
+}
+
+// VersionAtLeast returns true if the connected Zabbix API version is the given
+// major and minor version or later.
+//
+// An error is returned if the API version could not be retrieved or parsed.
+func (c *Session) VersionAtLeast(major, minor int) (bool, error) {
+	version, err := c.GetVersion()
+	if err != nil {
+		return false, err
+	}
+
+	vmajor, vminor, err := parseVersion(version)
+	if err != nil {
+		return false, err
+	}
+
+	if vmajor != major {
+		return vmajor > major, nil
+	}
+
+	return vminor >= minor, nil
+}
+
+// requireVersion returns ErrUnsupportedVersion if the connected Zabbix API is
+// older than the given major and minor version.
+func (c *Session) requireVersion(major, minor int) error {
+	supported, err := c.VersionAtLeast(major, minor)
+	if err != nil {
+		return err
+	}
+
+	if !supported {
+		return ErrUnsupportedVersion
+	}
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package zabbix
+
+import (
+	"testing"
+)
+
+func TestVersionAtLeast(t *testing.T) {
+	tests := []struct {
+		Version      string
+		Major, Minor int
+		Expect       bool
+	}{
+		{"6.2.1", 6, 2, true},
+		{"6.0.12", 6, 2, false},
+		{"5.4.0", 6, 0, false},
+		{"7.0.0", 6, 4, true},
+		{"4.0.3", 3, 4, true},
+	}
+
+	for _, test := range tests {
+		session := &Session{APIVersion: test.Version}
+		ok, err := session.VersionAtLeast(test.Major, test.Minor)
+		if err != nil {
+			t.Fatalf("Error comparing version %s: %v", test.Version, err)
+		}
+
+		if ok != test.Expect {
+			t.Errorf("Expected %s >= %d.%d to be %v", test.Version, test.Major, test.Minor, test.Expect)
+		}
+	}
+}